@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/joho/godotenv"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/config"
+	"github.com/wesjorgensen/EthAppList/backend/internal/repository"
+)
+
+// reconcile-upvotes recomputes products.upvote_count from the upvotes table
+// and reports any products whose stored counter had drifted.
+func main() {
+	repair := flag.Bool("repair", false, "write recomputed counts back to the products table")
+	flag.Parse()
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found")
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		log.Fatalf("Failed to initialize configuration: %v", err)
+	}
+
+	pgRepo, err := repository.NewPostgres(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize PostgreSQL repository: %v", err)
+	}
+	defer pgRepo.Close()
+
+	report, err := pgRepo.ReconcileUpvoteCounts(*repair)
+	if err != nil {
+		log.Fatalf("Failed to reconcile upvote counts: %v", err)
+	}
+
+	for _, drift := range report.Drift {
+		log.Printf("%s (%s): stored %d, actual %d", drift.ProductID, drift.Title, drift.StoredCount, drift.ActualCount)
+	}
+
+	log.Printf("Checked %d products, %d with drift", report.ProductsChecked, len(report.Drift))
+	if len(report.Drift) > 0 {
+		if report.Repaired {
+			log.Println("Counters repaired")
+		} else {
+			log.Println("Dry run: re-run with -repair to fix counters")
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
@@ -13,6 +14,7 @@ import (
 	"github.com/wesjorgensen/EthAppList/backend/internal/config"
 	"github.com/wesjorgensen/EthAppList/backend/internal/handlers"
 	"github.com/wesjorgensen/EthAppList/backend/internal/middleware"
+	"github.com/wesjorgensen/EthAppList/backend/internal/ratelimit"
 	"github.com/wesjorgensen/EthAppList/backend/internal/repository"
 	"github.com/wesjorgensen/EthAppList/backend/internal/service"
 )
@@ -40,7 +42,22 @@ func main() {
 	defer pgRepo.Close()
 
 	// Initialize service layer
-	svc := service.New(pgRepo, cfg)
+	svc, err := service.New(pgRepo, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize service: %v", err)
+	}
+
+	// Start background vote manipulation analysis
+	stopVoteAnalyzer := svc.StartVoteAnalyzer()
+	defer stopVoteAnalyzer()
+
+	// Build the contract lookup index and keep it fresh
+	stopContractIndexer := svc.StartContractIndexer()
+	defer stopContractIndexer()
+
+	// Look up users' ENS names in the background
+	stopENSRefresher := svc.StartENSRefresher()
+	defer stopENSRefresher()
 
 	// Initialize router
 	r := mux.NewRouter()
@@ -52,6 +69,21 @@ func main() {
 	// Set up API routes
 	apiRouter := r.PathPrefix("/api").Subrouter()
 
+	// Rate limit API requests, sharing buckets between replicas through
+	// Postgres when configured to
+	var rateLimitStore ratelimit.Store
+	switch cfg.RateLimitBackend {
+	case "memory":
+		rateLimitStore = ratelimit.NewMemoryStore()
+	case "postgres":
+		rateLimitStore = ratelimit.NewPostgresStore(pgRepo)
+	}
+	if rateLimitStore != nil {
+		stopRateLimitSweeper := ratelimit.StartSweeper(rateLimitStore, time.Minute)
+		defer stopRateLimitSweeper()
+		apiRouter.Use(middleware.RateLimit(cfg, rateLimitStore))
+	}
+
 	// Auth routes
 	authRouter := apiRouter.PathPrefix("/auth").Subrouter()
 	handlers.RegisterAuthHandlers(authRouter, svc)
@@ -60,22 +92,58 @@ func main() {
 	productsRouter := apiRouter.PathPrefix("/products").Subrouter()
 	handlers.RegisterProductHandlers(productsRouter, svc)
 
+	// Review and comment routes
+	reviewsRouter := apiRouter.PathPrefix("/reviews").Subrouter()
+	handlers.RegisterReviewHandlers(reviewsRouter, svc)
+	commentsRouter := apiRouter.PathPrefix("/comments").Subrouter()
+	handlers.RegisterCommentHandlers(commentsRouter, svc)
+
 	// Category routes
 	categoriesRouter := apiRouter.PathPrefix("/categories").Subrouter()
 	handlers.RegisterCategoryHandlers(categoriesRouter, svc)
 
+	// Tag routes
+	tagsRouter := apiRouter.PathPrefix("/tags").Subrouter()
+	handlers.RegisterTagHandlers(tagsRouter, svc)
+
+	// Chain routes
+	chainsRouter := apiRouter.PathPrefix("/chains").Subrouter()
+	handlers.RegisterChainHandlers(chainsRouter, svc)
+
+	// Score methodology routes
+	scoringRouter := apiRouter.PathPrefix("/scoring").Subrouter()
+	handlers.RegisterScoringHandlers(scoringRouter, svc)
+
+	// Contract lookup routes
+	lookupRouter := apiRouter.PathPrefix("/lookup").Subrouter()
+	handlers.RegisterLookupHandlers(lookupRouter, svc)
+
 	// User routes
 	userRouter := apiRouter.PathPrefix("/user").Subrouter()
 	handlers.RegisterUserHandlers(userRouter, svc)
 
+	// Public profile routes
+	usersRouter := apiRouter.PathPrefix("/users").Subrouter()
+	handlers.RegisterUserProfileHandlers(usersRouter, svc)
+
+	// Report routes
+	reportsRouter := apiRouter.PathPrefix("/reports").Subrouter()
+	handlers.RegisterReportHandlers(reportsRouter, svc)
+
+	// Moderation queue, open to curators as well as the admin. Registered
+	// ahead of the admin routes so it is matched first.
+	moderationRouter := apiRouter.PathPrefix("/admin/moderation").Subrouter()
+	moderationRouter.Use(middleware.CuratorOnly(cfg, svc.CheckUserAccess))
+	handlers.RegisterModerationHandlers(moderationRouter, svc)
+
 	// Admin routes
 	adminRouter := apiRouter.PathPrefix("/admin").Subrouter()
-	adminRouter.Use(middleware.AdminOnly(cfg))
+	adminRouter.Use(middleware.AdminOnly(cfg, svc.CheckUserAccess))
 	handlers.RegisterAdminHandlers(adminRouter, svc)
 
 	// Temporary endpoint for testing - DELETE ALL PRODUCTS
 	dropRouter := apiRouter.PathPrefix("/drop").Subrouter()
-	dropRouter.Use(middleware.AdminOnly(cfg))
+	dropRouter.Use(middleware.AdminOnly(cfg, svc.CheckUserAccess))
 	dropRouter.HandleFunc("", handlers.New(svc).DeleteAllProducts).Methods("POST")
 
 	// Health check
@@ -89,6 +157,7 @@ func main() {
 		AllowedOrigins:   []string{"*"}, // Update with your frontend domain in production
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		ExposedHeaders:   []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"},
 		AllowCredentials: true,
 	})
 
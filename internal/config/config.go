@@ -2,9 +2,14 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"net/url"
 	"os"
+	"path"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Config holds all application configuration
@@ -17,7 +22,8 @@ type Config struct {
 	Environment string
 
 	// Admin configuration
-	AdminWallet string
+	AdminWallet    string
+	CuratorWallets []string // wallets with curator rights in addition to the admin
 
 	// Database configuration
 	DBHost     string
@@ -25,8 +31,106 @@ type Config struct {
 	DBUser     string
 	DBPassword string
 	DBName     string
+
+	// Ethereum RPC endpoint used for on-chain lookups
+	EthRPCURL string
+
+	// ENS names and avatars shown for users
+	ENSRPCURL          string        // mainnet RPC endpoint for ENS lookups; empty disables them
+	ENSCacheTTL        time.Duration // how long a user's resolved name is kept before it is looked up again
+	ENSRefreshInterval time.Duration // how often stale names are refreshed in the background; 0 disables it
+
+	// Twitter/X account linking
+	TwitterVerifier     string        // "api", "stub" (development only) or "none"
+	TwitterBearerToken  string        // app bearer token for the API verifier
+	TwitterChallengeTTL time.Duration // how long a user has to post their challenge
+
+	// Linking more wallets to an account
+	WalletLinkTTL time.Duration // how long a user has to sign a wallet link message
+
+	// Vote weighting configuration
+	VoteWeightSignals            map[string]float64 // signal name to its relative importance
+	VoteWeightMin                float64            // weight of a vote that scores zero on every signal
+	VoteWeightAccountAgeDays     int                // account age at which the age signal is maxed out
+	VoteWeightContributionTarget int                // contributions at which the history signal is maxed out
+	VoteWeightTxCountTarget      int                // transactions at which the on-chain signal is maxed out
+	VoteWeightCacheTTL           time.Duration      // how long a voter's on-chain score is reused
+
+	// Vote anomaly analysis configuration
+	VoteAnalysisInterval     time.Duration // how often the background analyzer runs; 0 disables it
+	VoteAnalysisLookback     time.Duration // how far back each run looks at votes
+	VoteBurstWindow          time.Duration
+	VoteBurstSize            int
+	VoteNewAccountAge        time.Duration
+	VoteNewAccountMinVotes   int
+	VoteCoVoteWindow         time.Duration
+	VoteCoVoteMinShared      int
+	VoteCoVoteMinClusterSize int
+
+	// Contract lookup index configuration
+	ContractIndexRefresh time.Duration // how often the index is rebuilt to pick up changes made by other instances
+
+	// Duplicate detection configuration
+	DuplicateTitleSimilarity float64 // trigram similarity at which a submitted title counts as a likely duplicate
+
+	// Community rating aggregation
+	RatingPriorMean   float64 // rating a product is assumed to have before anyone rates it
+	RatingPriorWeight float64 // how many ratings the prior counts as in the Bayesian average
+	RatingTrim        float64 // fraction of ratings dropped from each end for the trimmed mean
+
+	// User sanctions
+	ProbationDays int // probation that follows a suspension or an upheld report, 0 to disable
+
+	// Reputation needed to unlock privileges
+	ReputationDirectEdit int // edit products without review
+	ReputationRevert     int // revert products to an earlier revision
+
+	// Rate limiting
+	RateLimitBackend  string            // "memory", "postgres" or "none"
+	RateLimitPolicies []RateLimitPolicy // checked in order; the first match applies
+	TrustedProxies    []*net.IPNet      // proxies whose X-Forwarded-For is believed
+}
+
+// RateLimitPolicy limits requests matching Method and Path to Limit per
+// Window for each user, or for each client IP when anonymous
+type RateLimitPolicy struct {
+	Method string // HTTP method, or "*" for any
+	Path   string // path.Match pattern; a trailing "/" matches everything below it
+	Limit  int
+	Window time.Duration
+}
+
+// Name identifies the policy, as written in RATE_LIMIT_POLICIES
+func (p RateLimitPolicy) Name() string {
+	return p.Method + " " + p.Path
+}
+
+// Matches reports whether a request falls under the policy
+func (p RateLimitPolicy) Matches(method, requestPath string) bool {
+	if p.Method != "*" && !strings.EqualFold(p.Method, method) {
+		return false
+	}
+	if strings.HasSuffix(p.Path, "/") {
+		return strings.HasPrefix(requestPath, p.Path)
+	}
+	matched, _ := path.Match(p.Path, requestPath)
+	return matched
 }
 
+// defaultRateLimitPolicies guards sign-in and the write endpoints most open
+// to abuse, with a generous ceiling on everything else
+const defaultRateLimitPolicies = "POST /api/auth/wallet=10/1m," +
+	"POST /api/products=10/1h," +
+	"PUT /api/products/*=30/1h," +
+	"POST /api/products/*/revert/*=10/1h," +
+	"POST /api/products/*/reviews=10/1h," +
+	"POST /api/products/*/comments=60/1h," +
+	"POST /api/reports=20/1h," +
+	"POST /api/user/ens/refresh=5/1h," +
+	"POST /api/user/twitter/*=10/1h," +
+	"POST /api/user/wallets/*=10/1h," +
+	"* /api/=300/1m"
+
 // New creates a new configuration from environment variables
 func New() (*Config, error) {
 	// Check if we're running on Railway with DATABASE_URL
@@ -92,6 +196,13 @@ func New() (*Config, error) {
 		return nil, errors.New("ADMIN_WALLET_ADDRESS is required")
 	}
 
+	var curatorWallets []string
+	for _, wallet := range strings.Split(os.Getenv("CURATOR_WALLET_ADDRESSES"), ",") {
+		if wallet = strings.TrimSpace(wallet); wallet != "" {
+			curatorWallets = append(curatorWallets, wallet)
+		}
+	}
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080" // Default port
@@ -102,16 +213,259 @@ func New() (*Config, error) {
 		environment = "development" // Default environment
 	}
 
+	ethRPCURL := os.Getenv("ETH_RPC_URL")
+
+	// ENS lookups use their own endpoint if given, since names live on mainnet
+	ensRPCURL := getEnv("ENS_RPC_URL", ethRPCURL)
+	ensCacheTTLHours, err := getEnvInt("ENS_CACHE_TTL_HOURS", 24)
+	if err != nil {
+		return nil, err
+	}
+	if ensCacheTTLHours <= 0 {
+		return nil, errors.New("ENS_CACHE_TTL_HOURS must be positive")
+	}
+	ensRefreshIntervalMinutes, err := getEnvInt("ENS_REFRESH_INTERVAL_MINUTES", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	// Twitter/X handles can only be linked once a verifier is chosen
+	twitterVerifier := getEnv("TWITTER_VERIFIER", "none")
+	twitterBearerToken := os.Getenv("TWITTER_BEARER_TOKEN")
+	switch twitterVerifier {
+	case "api":
+		if twitterBearerToken == "" {
+			return nil, errors.New("TWITTER_BEARER_TOKEN is required for the api Twitter verifier")
+		}
+	case "stub":
+		if environment == "production" {
+			return nil, errors.New("the stub Twitter verifier cannot be used in production")
+		}
+	case "none":
+	default:
+		return nil, errors.New("TWITTER_VERIFIER must be api, stub or none")
+	}
+	twitterChallengeTTLMinutes, err := getEnvInt("TWITTER_CHALLENGE_TTL_MINUTES", 60)
+	if err != nil {
+		return nil, err
+	}
+	if twitterChallengeTTLMinutes <= 0 {
+		return nil, errors.New("TWITTER_CHALLENGE_TTL_MINUTES must be positive")
+	}
+
+	walletLinkTTLMinutes, err := getEnvInt("WALLET_LINK_TTL_MINUTES", 15)
+	if err != nil {
+		return nil, err
+	}
+	if walletLinkTTLMinutes <= 0 {
+		return nil, errors.New("WALLET_LINK_TTL_MINUTES must be positive")
+	}
+
+	// Vote weighting defaults to signals that need no external services
+	voteWeightSignals, err := parseSignalWeights(getEnv("VOTE_WEIGHT_SIGNALS", "account_age:1,contributions:1"))
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := voteWeightSignals["onchain"]; ok && ethRPCURL == "" {
+		return nil, errors.New("ETH_RPC_URL is required for the onchain vote weight signal")
+	}
+	if _, ok := voteWeightSignals["ens"]; ok && ensRPCURL == "" {
+		return nil, errors.New("ENS_RPC_URL or ETH_RPC_URL is required for the ens vote weight signal")
+	}
+
+	voteWeightMin, err := getEnvFloat("VOTE_WEIGHT_MIN", 0.1)
+	if err != nil {
+		return nil, err
+	}
+	if voteWeightMin < 0 || voteWeightMin > 1 {
+		return nil, errors.New("VOTE_WEIGHT_MIN must be between 0 and 1")
+	}
+
+	voteWeightAccountAgeDays, err := getEnvInt("VOTE_WEIGHT_ACCOUNT_AGE_DAYS", 30)
+	if err != nil {
+		return nil, err
+	}
+
+	voteWeightContributionTarget, err := getEnvInt("VOTE_WEIGHT_CONTRIBUTION_TARGET", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	voteWeightTxCountTarget, err := getEnvInt("VOTE_WEIGHT_TX_COUNT_TARGET", 50)
+	if err != nil {
+		return nil, err
+	}
+
+	voteWeightCacheMinutes, err := getEnvInt("VOTE_WEIGHT_CACHE_MINUTES", 60)
+	if err != nil {
+		return nil, err
+	}
+	if voteWeightCacheMinutes < 0 {
+		return nil, errors.New("VOTE_WEIGHT_CACHE_MINUTES must not be negative")
+	}
+
+	// Vote anomaly analysis thresholds
+	voteAnalysisIntervalMinutes, err := getEnvInt("VOTE_ANALYSIS_INTERVAL_MINUTES", 15)
+	if err != nil {
+		return nil, err
+	}
+	voteAnalysisLookbackHours, err := getEnvInt("VOTE_ANALYSIS_LOOKBACK_HOURS", 168)
+	if err != nil {
+		return nil, err
+	}
+	voteBurstWindowMinutes, err := getEnvInt("VOTE_BURST_WINDOW_MINUTES", 10)
+	if err != nil {
+		return nil, err
+	}
+	voteBurstSize, err := getEnvInt("VOTE_BURST_SIZE", 10)
+	if err != nil {
+		return nil, err
+	}
+	voteNewAccountMinutes, err := getEnvInt("VOTE_NEW_ACCOUNT_MINUTES", 30)
+	if err != nil {
+		return nil, err
+	}
+	voteNewAccountMinVotes, err := getEnvInt("VOTE_NEW_ACCOUNT_MIN_VOTES", 3)
+	if err != nil {
+		return nil, err
+	}
+	voteCoVoteWindowMinutes, err := getEnvInt("VOTE_COVOTE_WINDOW_MINUTES", 10)
+	if err != nil {
+		return nil, err
+	}
+	voteCoVoteMinShared, err := getEnvInt("VOTE_COVOTE_MIN_SHARED", 3)
+	if err != nil {
+		return nil, err
+	}
+	voteCoVoteMinClusterSize, err := getEnvInt("VOTE_COVOTE_MIN_CLUSTER_SIZE", 3)
+	if err != nil {
+		return nil, err
+	}
+
+	contractIndexRefreshMinutes, err := getEnvInt("CONTRACT_INDEX_REFRESH_MINUTES", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicateTitleSimilarity, err := getEnvFloat("DUPLICATE_TITLE_SIMILARITY", 0.6)
+	if err != nil {
+		return nil, err
+	}
+	if duplicateTitleSimilarity <= 0 || duplicateTitleSimilarity > 1 {
+		return nil, errors.New("DUPLICATE_TITLE_SIMILARITY must be greater than 0 and at most 1")
+	}
+
+	ratingPriorMean, err := getEnvFloat("RATING_PRIOR_MEAN", 0.5)
+	if err != nil {
+		return nil, err
+	}
+	if ratingPriorMean < 0 || ratingPriorMean > 1 {
+		return nil, errors.New("RATING_PRIOR_MEAN must be between 0 and 1")
+	}
+	ratingPriorWeight, err := getEnvFloat("RATING_PRIOR_WEIGHT", 5)
+	if err != nil {
+		return nil, err
+	}
+	if ratingPriorWeight < 0 {
+		return nil, errors.New("RATING_PRIOR_WEIGHT must not be negative")
+	}
+	ratingTrim, err := getEnvFloat("RATING_TRIM", 0.1)
+	if err != nil {
+		return nil, err
+	}
+	if ratingTrim < 0 || ratingTrim >= 0.5 {
+		return nil, errors.New("RATING_TRIM must be at least 0 and below 0.5")
+	}
+
+	probationDays, err := getEnvInt("PROBATION_DAYS", 30)
+	if err != nil {
+		return nil, err
+	}
+	if probationDays < 0 {
+		return nil, errors.New("PROBATION_DAYS must not be negative")
+	}
+
+	reputationDirectEdit, err := getEnvInt("REPUTATION_DIRECT_EDIT", 20)
+	if err != nil {
+		return nil, err
+	}
+	reputationRevert, err := getEnvInt("REPUTATION_REVERT", 50)
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimitBackend := getEnv("RATE_LIMIT_BACKEND", "memory")
+	switch rateLimitBackend {
+	case "memory", "postgres", "none":
+	default:
+		return nil, errors.New("RATE_LIMIT_BACKEND must be memory, postgres or none")
+	}
+	rateLimitPolicies, err := parseRateLimitPolicies(getEnv("RATE_LIMIT_POLICIES", defaultRateLimitPolicies))
+	if err != nil {
+		return nil, err
+	}
+	trustedProxies, err := parseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
-		JWTSecret:   jwtSecret,
-		Port:        port,
-		Environment: environment,
-		AdminWallet: adminWallet,
-		DBHost:      dbHost,
-		DBPort:      dbPort,
-		DBUser:      dbUser,
-		DBPassword:  dbPassword,
-		DBName:      dbName,
+		JWTSecret:      jwtSecret,
+		Port:           port,
+		Environment:    environment,
+		AdminWallet:    adminWallet,
+		CuratorWallets: curatorWallets,
+		DBHost:         dbHost,
+		DBPort:         dbPort,
+		DBUser:         dbUser,
+		DBPassword:     dbPassword,
+		DBName:         dbName,
+
+		EthRPCURL: ethRPCURL,
+
+		ENSRPCURL:          ensRPCURL,
+		ENSCacheTTL:        time.Duration(ensCacheTTLHours) * time.Hour,
+		ENSRefreshInterval: time.Duration(ensRefreshIntervalMinutes) * time.Minute,
+
+		TwitterVerifier:     twitterVerifier,
+		TwitterBearerToken:  twitterBearerToken,
+		TwitterChallengeTTL: time.Duration(twitterChallengeTTLMinutes) * time.Minute,
+
+		WalletLinkTTL: time.Duration(walletLinkTTLMinutes) * time.Minute,
+
+		VoteWeightSignals:            voteWeightSignals,
+		VoteWeightMin:                voteWeightMin,
+		VoteWeightAccountAgeDays:     voteWeightAccountAgeDays,
+		VoteWeightContributionTarget: voteWeightContributionTarget,
+		VoteWeightTxCountTarget:      voteWeightTxCountTarget,
+		VoteWeightCacheTTL:           time.Duration(voteWeightCacheMinutes) * time.Minute,
+
+		VoteAnalysisInterval:     time.Duration(voteAnalysisIntervalMinutes) * time.Minute,
+		VoteAnalysisLookback:     time.Duration(voteAnalysisLookbackHours) * time.Hour,
+		VoteBurstWindow:          time.Duration(voteBurstWindowMinutes) * time.Minute,
+		VoteBurstSize:            voteBurstSize,
+		VoteNewAccountAge:        time.Duration(voteNewAccountMinutes) * time.Minute,
+		VoteNewAccountMinVotes:   voteNewAccountMinVotes,
+		VoteCoVoteWindow:         time.Duration(voteCoVoteWindowMinutes) * time.Minute,
+		VoteCoVoteMinShared:      voteCoVoteMinShared,
+		VoteCoVoteMinClusterSize: voteCoVoteMinClusterSize,
+
+		ContractIndexRefresh: time.Duration(contractIndexRefreshMinutes) * time.Minute,
+
+		DuplicateTitleSimilarity: duplicateTitleSimilarity,
+
+		RatingPriorMean:   ratingPriorMean,
+		RatingPriorWeight: ratingPriorWeight,
+		RatingTrim:        ratingTrim,
+
+		ProbationDays: probationDays,
+
+		ReputationDirectEdit: reputationDirectEdit,
+		ReputationRevert:     reputationRevert,
+
+		RateLimitBackend:  rateLimitBackend,
+		RateLimitPolicies: rateLimitPolicies,
+		TrustedProxies:    trustedProxies,
 	}, nil
 }
 
@@ -120,7 +474,176 @@ func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
 }
 
+// IsAdminWallet returns true if the wallet is the configured admin wallet
+func (c *Config) IsAdminWallet(wallet string) bool {
+	return strings.EqualFold(wallet, c.AdminWallet)
+}
+
+// IsCuratorWallet returns true if the wallet is the admin or a configured curator
+func (c *Config) IsCuratorWallet(wallet string) bool {
+	if c.IsAdminWallet(wallet) {
+		return true
+	}
+	for _, curator := range c.CuratorWallets {
+		if strings.EqualFold(wallet, curator) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsProduction returns true if the environment is set to production
 func (c *Config) IsProduction() bool {
 	return c.Environment == "production"
 }
+
+// IsTrustedProxy returns true if ip belongs to a configured trusted proxy
+func (c *Config) IsTrustedProxy(ip net.IP) bool {
+	for _, network := range c.TrustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// getEnv returns an environment variable or a default value if not set
+func getEnv(key, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvInt returns an integer environment variable or a default value if not set
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", key)
+	}
+	return parsed, nil
+}
+
+// getEnvFloat returns a numeric environment variable or a default value if not set
+func getEnvFloat(key string, defaultValue float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", key)
+	}
+	return parsed, nil
+}
+
+// parseSignalWeights parses a list like "account_age:1,ens:0.5". A signal
+// without an explicit weight gets 1, and "none" disables weighting entirely.
+func parseSignalWeights(value string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	if strings.TrimSpace(value) == "none" {
+		return weights, nil
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, weightStr, hasWeight := strings.Cut(entry, ":")
+		weight := 1.0
+		if hasWeight {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+			if err != nil || parsed < 0 {
+				return nil, fmt.Errorf("invalid weight for vote weight signal %s", name)
+			}
+			weight = parsed
+		}
+
+		switch name = strings.TrimSpace(name); name {
+		case "account_age", "contributions", "onchain", "ens":
+			weights[name] = weight
+		default:
+			return nil, fmt.Errorf("unknown vote weight signal: %s", name)
+		}
+	}
+
+	return weights, nil
+}
+
+// parseRateLimitPolicies parses a list like "POST /api/auth/wallet=10/1m,* /api/=300/1m"
+func parseRateLimitPolicies(value string) ([]RateLimitPolicy, error) {
+	var policies []RateLimitPolicy
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		route, limitStr, ok := strings.Cut(entry, "=")
+		method, pattern, hasPath := strings.Cut(strings.TrimSpace(route), " ")
+		countStr, windowStr, hasWindow := strings.Cut(limitStr, "/")
+		if !ok || !hasPath || !hasWindow {
+			return nil, fmt.Errorf("invalid rate limit policy %q, expected \"METHOD /path=limit/window\"", entry)
+		}
+
+		policy := RateLimitPolicy{Method: strings.ToUpper(method), Path: strings.TrimSpace(pattern)}
+		if _, err := path.Match(policy.Path, "/"); err != nil || !strings.HasPrefix(policy.Path, "/") {
+			return nil, fmt.Errorf("invalid path in rate limit policy %q", entry)
+		}
+
+		limit, err := strconv.Atoi(strings.TrimSpace(countStr))
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("invalid limit in rate limit policy %q", entry)
+		}
+		window, err := time.ParseDuration(strings.TrimSpace(windowStr))
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid window in rate limit policy %q", entry)
+		}
+		policy.Limit = limit
+		policy.Window = window
+
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// parseTrustedProxies parses a list of IP addresses and CIDR ranges
+func parseTrustedProxies(value string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy address: %s", entry)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			entry = fmt.Sprintf("%s/%d", entry, bits)
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy range: %s", entry)
+		}
+		proxies = append(proxies, network)
+	}
+
+	return proxies, nil
+}
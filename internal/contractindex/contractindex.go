@@ -0,0 +1,59 @@
+package contractindex
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// key identifies a contract by EVM chain ID and lowercased address
+type key struct {
+	chainID int64
+	address string
+}
+
+// Index is an in-memory map from deployed contracts to products. It is safe
+// for concurrent use; Replace swaps the whole map at once so lookups never
+// see a half-built index.
+type Index struct {
+	mu      sync.RWMutex
+	entries map[key]models.ContractMatch
+	builtAt time.Time
+}
+
+// New returns an empty index
+func New() *Index {
+	return &Index{entries: make(map[key]models.ContractMatch)}
+}
+
+// Replace rebuilds the index from a full list of matches
+func (i *Index) Replace(matches []models.ContractMatch) {
+	entries := make(map[key]models.ContractMatch, len(matches))
+	for _, match := range matches {
+		entries[key{match.EVMChainID, strings.ToLower(match.Address)}] = match
+	}
+
+	i.mu.Lock()
+	i.entries = entries
+	i.builtAt = time.Now()
+	i.mu.Unlock()
+}
+
+// Lookup returns the product that deployed address on the given EVM chain
+func (i *Index) Lookup(chainID int64, address string) (models.ContractMatch, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	match, ok := i.entries[key{chainID, strings.ToLower(strings.TrimSpace(address))}]
+	return match, ok
+}
+
+// Stats returns the number of indexed contracts and when the index was last built
+func (i *Index) Stats() (size int, builtAt time.Time) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return len(i.entries), i.builtAt
+}
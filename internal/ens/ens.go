@@ -0,0 +1,253 @@
+package ens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// registryAddress is the ENS registry deployed on Ethereum mainnet
+var registryAddress = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+// Function selectors for the registry and resolver methods used here
+var (
+	resolverSelector = crypto.Keccak256([]byte("resolver(bytes32)"))[:4]
+	nameSelector     = crypto.Keccak256([]byte("name(bytes32)"))[:4]
+	addrSelector     = crypto.Keccak256([]byte("addr(bytes32)"))[:4]
+	textSelector     = crypto.Keccak256([]byte("text(bytes32,string)"))[:4]
+)
+
+// Gateways used to turn decentralised storage URIs into HTTP URLs
+const (
+	ipfsGateway    = "https://ipfs.io/ipfs/"
+	arweaveGateway = "https://arweave.net/"
+)
+
+// Profile is what ENS says about an address
+type Profile struct {
+	Name   string // primary name, verified to resolve back to the address
+	Avatar string // HTTP URL of the name's avatar, if it has a usable one
+}
+
+// Resolver resolves ENS records through an Ethereum JSON-RPC endpoint
+type Resolver struct {
+	client *ethclient.Client
+}
+
+// NewResolver connects a resolver to the given RPC endpoint
+func NewResolver(rpcURL string) (*Resolver, error) {
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RPC endpoint: %w", err)
+	}
+
+	return &Resolver{client: client}, nil
+}
+
+// Client returns the underlying RPC client for other on-chain lookups
+func (r *Resolver) Client() *ethclient.Client {
+	return r.client
+}
+
+// ReverseLookup returns the name set as the primary ENS name for an address,
+// or "" if the address has none
+func (r *Resolver) ReverseLookup(ctx context.Context, address string) (string, error) {
+	if !common.IsHexAddress(address) {
+		return "", errors.New("invalid address")
+	}
+
+	reverseName := strings.ToLower(strings.TrimPrefix(common.HexToAddress(address).Hex(), "0x")) + ".addr.reverse"
+	node := Namehash(reverseName)
+
+	resolver, err := r.resolverFor(ctx, node)
+	if err != nil {
+		return "", err
+	}
+	if resolver == (common.Address{}) {
+		return "", nil
+	}
+
+	out, err := r.call(ctx, resolver, nameSelector, node[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to read reverse record: %w", err)
+	}
+
+	return decodeString(out)
+}
+
+// Resolve returns the address a name resolves to, or the zero address if it
+// has no resolver or address record
+func (r *Resolver) Resolve(ctx context.Context, name string) (common.Address, error) {
+	node := Namehash(name)
+
+	resolver, err := r.resolverFor(ctx, node)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if resolver == (common.Address{}) {
+		return common.Address{}, nil
+	}
+
+	out, err := r.call(ctx, resolver, addrSelector, node[:])
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to read address record: %w", err)
+	}
+	if len(out) < 32 {
+		return common.Address{}, nil
+	}
+
+	return common.BytesToAddress(out[12:32]), nil
+}
+
+// Text returns a name's text record for key, or "" if it is not set
+func (r *Resolver) Text(ctx context.Context, name, key string) (string, error) {
+	node := Namehash(name)
+
+	resolver, err := r.resolverFor(ctx, node)
+	if err != nil {
+		return "", err
+	}
+	if resolver == (common.Address{}) {
+		return "", nil
+	}
+
+	out, err := r.call(ctx, resolver, textSelector, node[:], encodeString(key))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s text record: %w", key, err)
+	}
+
+	return decodeString(out)
+}
+
+// LookupProfile returns the primary name and avatar of an address. The
+// reverse record is only trusted when the name resolves back to the address,
+// since anyone can claim any name in their reverse record.
+func (r *Resolver) LookupProfile(ctx context.Context, address string) (Profile, error) {
+	name, err := r.ReverseLookup(ctx, address)
+	if err != nil || name == "" {
+		return Profile{}, err
+	}
+
+	resolved, err := r.Resolve(ctx, name)
+	if err != nil {
+		return Profile{}, err
+	}
+	if resolved != common.HexToAddress(address) {
+		return Profile{}, nil
+	}
+
+	avatar, err := r.Text(ctx, name, "avatar")
+	if err != nil {
+		return Profile{}, err
+	}
+
+	return Profile{Name: name, Avatar: AvatarURL(avatar)}, nil
+}
+
+// AvatarURL turns an avatar text record into an HTTP URL, or "" if it cannot.
+// NFT avatars (eip155:...) need the token's metadata fetched and are not
+// supported.
+func AvatarURL(record string) string {
+	record = strings.TrimSpace(record)
+	lower := strings.ToLower(record)
+
+	switch {
+	case strings.HasPrefix(lower, "https://"), strings.HasPrefix(lower, "http://"):
+		return record
+	case strings.HasPrefix(lower, "ipfs://"):
+		cid := strings.TrimPrefix(record[len("ipfs://"):], "ipfs/")
+		if cid == "" {
+			return ""
+		}
+		return ipfsGateway + cid
+	case strings.HasPrefix(lower, "ar://"):
+		if len(record) == len("ar://") {
+			return ""
+		}
+		return arweaveGateway + record[len("ar://"):]
+	default:
+		return ""
+	}
+}
+
+// resolverFor returns the resolver contract registered for a node
+func (r *Resolver) resolverFor(ctx context.Context, node [32]byte) (common.Address, error) {
+	out, err := r.call(ctx, registryAddress, resolverSelector, node[:])
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to look up resolver: %w", err)
+	}
+	if len(out) < 32 {
+		return common.Address{}, nil
+	}
+
+	return common.BytesToAddress(out[12:32]), nil
+}
+
+// call performs an eth_call of selector with pre-encoded arguments
+func (r *Resolver) call(ctx context.Context, to common.Address, selector []byte, args ...[]byte) ([]byte, error) {
+	data := append([]byte{}, selector...)
+	for _, arg := range args {
+		data = append(data, arg...)
+	}
+
+	return r.client.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+}
+
+// Namehash computes the EIP-137 namehash of an ENS name
+func Namehash(name string) [32]byte {
+	var node [32]byte
+	if name == "" {
+		return node
+	}
+
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		copy(node[:], crypto.Keccak256(node[:], labelHash))
+	}
+
+	return node
+}
+
+// encodeString ABI-encodes a string as the last of two arguments, the first
+// being a static 32-byte value
+func encodeString(value string) []byte {
+	padded := (len(value) + 31) / 32 * 32
+
+	out := make([]byte, 64+padded)
+	new(big.Int).SetUint64(64).FillBytes(out[:32])
+	new(big.Int).SetUint64(uint64(len(value))).FillBytes(out[32:64])
+	copy(out[64:], value)
+
+	return out
+}
+
+// decodeString decodes a single ABI-encoded dynamic string return value
+func decodeString(out []byte) (string, error) {
+	if len(out) == 0 {
+		return "", nil
+	}
+	if len(out) < 64 {
+		return "", errors.New("malformed string result")
+	}
+
+	offset := new(big.Int).SetBytes(out[:32]).Uint64()
+	if offset+32 > uint64(len(out)) {
+		return "", errors.New("malformed string result")
+	}
+
+	length := new(big.Int).SetBytes(out[offset : offset+32]).Uint64()
+	start := offset + 32
+	if start+length > uint64(len(out)) {
+		return "", errors.New("malformed string result")
+	}
+
+	return string(out[start : start+length]), nil
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/middleware"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// GetCategoryTree handles getting categories nested under their parents
+func (h *Handler) GetCategoryTree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.svc.GetCategoryTree()
+	if err != nil {
+		http.Error(w, "Failed to get categories: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tree)
+}
+
+// GetCategory handles getting a single category by ID or slug
+func (h *Handler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	category, err := h.svc.GetCategory(vars["id"])
+	if err != nil {
+		http.Error(w, "Failed to get category: "+err.Error(), categoryErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(category)
+}
+
+// UpdateCategory handles updating a category's name, slug, description or parent
+func (h *Handler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var category models.Category
+	err := json.NewDecoder(r.Body).Decode(&category)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Ensure the category ID matches the URL parameter
+	category.ID = vars["id"]
+
+	err = h.svc.UpdateCategory(&category, user.ID)
+	if err != nil {
+		http.Error(w, "Failed to update category: "+err.Error(), categoryErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(category)
+}
+
+// DeleteCategory handles deleting an empty category
+func (h *Handler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	err := h.svc.DeleteCategory(vars["id"], user.ID)
+	if err != nil {
+		http.Error(w, "Failed to delete category: "+err.Error(), categoryErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MergeCategories handles folding one category into another
+func (h *Handler) MergeCategories(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		TargetID string `json:"target_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TargetID == "" {
+		http.Error(w, "target_id is required", http.StatusBadRequest)
+		return
+	}
+
+	moved, err := h.svc.MergeCategories(vars["id"], req.TargetID, user.ID)
+	if err != nil {
+		http.Error(w, "Failed to merge categories: "+err.Error(), categoryErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"merged_into":    req.TargetID,
+		"products_moved": moved,
+	})
+}
+
+// categoryErrorStatus maps category service errors to HTTP status codes
+func categoryErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.HasSuffix(msg, "category not found") && !strings.HasPrefix(msg, "parent"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "already taken"), strings.Contains(msg, "has products"),
+		strings.Contains(msg, "has subcategories"), strings.Contains(msg, "duplicate key"):
+		return http.StatusConflict
+	case strings.Contains(msg, "required"), strings.Contains(msg, "must "),
+		strings.Contains(msg, "cycle"), strings.Contains(msg, "cannot merge"),
+		strings.HasPrefix(msg, "parent category"), strings.Contains(msg, "nest at most"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
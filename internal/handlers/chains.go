@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/middleware"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/service"
+)
+
+// RegisterChainHandlers registers chain-related routes
+func RegisterChainHandlers(router *mux.Router, svc *service.Service) {
+	h := New(svc)
+
+	router.HandleFunc("", h.GetChains).Methods("GET")
+	router.HandleFunc("/{id}", h.GetChain).Methods("GET")
+
+	// Admin-only routes
+	adminRouter := router.NewRoute().Subrouter()
+	adminRouter.Use(middleware.AdminOnly(svc.GetConfig(), svc.CheckUserAccess))
+
+	adminRouter.HandleFunc("", h.CreateChain).Methods("POST")
+	adminRouter.HandleFunc("/{id}", h.UpdateChain).Methods("PUT")
+	adminRouter.HandleFunc("/{id}", h.DeleteChain).Methods("DELETE")
+}
+
+// GetChains handles getting all chains
+func (h *Handler) GetChains(w http.ResponseWriter, r *http.Request) {
+	chains, err := h.svc.GetChains()
+	if err != nil {
+		http.Error(w, "Failed to get chains: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chains)
+}
+
+// GetChain handles getting a single chain
+func (h *Handler) GetChain(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	chain, err := h.svc.GetChain(id)
+	if err != nil {
+		http.Error(w, "Failed to get chain: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chain)
+}
+
+// CreateChain handles adding a new chain
+func (h *Handler) CreateChain(w http.ResponseWriter, r *http.Request) {
+	var chain models.Chain
+	err := json.NewDecoder(r.Body).Decode(&chain)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.svc.CreateChain(&chain)
+	if err != nil {
+		http.Error(w, "Failed to create chain: "+err.Error(), chainErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(chain)
+}
+
+// UpdateChain handles updating a chain's metadata
+func (h *Handler) UpdateChain(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var chain models.Chain
+	err := json.NewDecoder(r.Body).Decode(&chain)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Ensure the chain ID matches the URL parameter
+	chain.ID = vars["id"]
+
+	err = h.svc.UpdateChain(&chain)
+	if err != nil {
+		http.Error(w, "Failed to update chain: "+err.Error(), chainErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chain)
+}
+
+// DeleteChain handles deleting a chain
+func (h *Handler) DeleteChain(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	err := h.svc.DeleteChain(id)
+	if err != nil {
+		http.Error(w, "Failed to delete chain: "+err.Error(), chainErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// chainErrorStatus maps chain service errors to HTTP status codes
+func chainErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case msg == "chain not found":
+		return http.StatusNotFound
+	case msg == "chain is in use", strings.Contains(msg, "duplicate key"):
+		return http.StatusConflict
+	case strings.Contains(msg, "required"), strings.Contains(msg, "must be"),
+		strings.Contains(msg, "cycle"), strings.HasPrefix(msg, "parent chain"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/middleware"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/service"
+)
+
+// RegisterReviewHandlers registers routes acting on a single review
+func RegisterReviewHandlers(router *mux.Router, svc *service.Service) {
+	h := New(svc)
+
+	protectedRouter := router.NewRoute().Subrouter()
+	protectedRouter.Use(middleware.Auth(svc.GetConfig(), svc.CheckUserAccess))
+	protectedRouter.HandleFunc("/{id}", h.UpdateReview).Methods("PUT")
+	protectedRouter.HandleFunc("/{id}", h.DeleteReview).Methods("DELETE")
+
+	curatorRouter := router.NewRoute().Subrouter()
+	curatorRouter.Use(middleware.CuratorOnly(svc.GetConfig(), svc.CheckUserAccess))
+	curatorRouter.HandleFunc("/{id}/moderate", h.ModerateReview).Methods("POST")
+}
+
+// RegisterCommentHandlers registers routes acting on a single comment
+func RegisterCommentHandlers(router *mux.Router, svc *service.Service) {
+	h := New(svc)
+
+	protectedRouter := router.NewRoute().Subrouter()
+	protectedRouter.Use(middleware.Auth(svc.GetConfig(), svc.CheckUserAccess))
+	protectedRouter.HandleFunc("/{id}", h.UpdateComment).Methods("PUT")
+	protectedRouter.HandleFunc("/{id}", h.DeleteComment).Methods("DELETE")
+
+	curatorRouter := router.NewRoute().Subrouter()
+	curatorRouter.Use(middleware.CuratorOnly(svc.GetConfig(), svc.CheckUserAccess))
+	curatorRouter.HandleFunc("/{id}/moderate", h.ModerateComment).Methods("POST")
+}
+
+// discussionPage parses the page and per_page query parameters of a
+// discussion listing
+func discussionPage(r *http.Request) (int, int) {
+	page := 1
+	perPage := 20
+
+	if parsed, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && parsed > 0 {
+		page = parsed
+	}
+	if parsed, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && parsed > 0 && parsed <= 100 {
+		perPage = parsed
+	}
+
+	return page, perPage
+}
+
+// GetProductReviews handles listing a product's reviews
+func (h *Handler) GetProductReviews(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	page, perPage := discussionPage(r)
+
+	reviews, total, own, err := h.svc.GetProductReviews(vars["id"], h.viewer(r), page, perPage)
+	if err != nil {
+		http.Error(w, "Failed to get reviews: "+err.Error(), discussionErrorStatus(err))
+		return
+	}
+
+	response := struct {
+		Reviews      []models.Review `json:"reviews"`
+		ViewerReview *models.Review  `json:"viewer_review,omitempty"`
+		Total        int             `json:"total"`
+		Page         int             `json:"page"`
+		PerPage      int             `json:"per_page"`
+		Pages        int             `json:"pages"`
+	}{
+		Reviews:      reviews,
+		ViewerReview: own,
+		Total:        total,
+		Page:         page,
+		PerPage:      perPage,
+		Pages:        (total + perPage - 1) / perPage,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateReview handles reviewing a product
+func (h *Handler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		Body   string `json:"body"`
+		Rating *int   `json:"rating,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.svc.CreateReview(vars["id"], req.Body, req.Rating, user)
+	if err != nil {
+		http.Error(w, "Failed to create review: "+err.Error(), discussionErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(review)
+}
+
+// UpdateReview handles the author rewriting their review
+func (h *Handler) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		Body   string `json:"body"`
+		Rating *int   `json:"rating,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.svc.UpdateReview(vars["id"], req.Body, req.Rating, user)
+	if err != nil {
+		http.Error(w, "Failed to update review: "+err.Error(), discussionErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// DeleteReview handles the author deleting their review
+func (h *Handler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	if err := h.svc.DeleteReview(vars["id"], user); err != nil {
+		http.Error(w, "Failed to delete review: "+err.Error(), discussionErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ModerateReview handles a curator hiding, restoring or removing a review
+func (h *Handler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		Action string `json:"action"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Action == "" {
+		http.Error(w, "action is required", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.svc.ModerateReview(vars["id"], req.Action, strings.TrimSpace(req.Reason), user)
+	if err != nil {
+		http.Error(w, "Failed to moderate review: "+err.Error(), discussionErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// GetProductComments handles listing a product's comment threads
+func (h *Handler) GetProductComments(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	page, perPage := discussionPage(r)
+
+	threads, total, err := h.svc.GetProductComments(vars["id"], h.viewer(r), page, perPage)
+	if err != nil {
+		http.Error(w, "Failed to get comments: "+err.Error(), discussionErrorStatus(err))
+		return
+	}
+
+	response := struct {
+		Threads []*models.Comment `json:"threads"`
+		Total   int               `json:"total"`
+		Page    int               `json:"page"`
+		PerPage int               `json:"per_page"`
+		Pages   int               `json:"pages"`
+	}{
+		Threads: threads,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+		Pages:   (total + perPage - 1) / perPage,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateComment handles commenting on a product or replying to a comment
+func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		Body     string  `json:"body"`
+		ParentID *string `json:"parent_id,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.svc.CreateComment(vars["id"], req.ParentID, req.Body, user)
+	if err != nil {
+		http.Error(w, "Failed to create comment: "+err.Error(), discussionErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+// UpdateComment handles the author rewriting their comment
+func (h *Handler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.svc.UpdateComment(vars["id"], req.Body, user)
+	if err != nil {
+		http.Error(w, "Failed to update comment: "+err.Error(), discussionErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comment)
+}
+
+// DeleteComment handles the author deleting their comment
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	if err := h.svc.DeleteComment(vars["id"], user); err != nil {
+		http.Error(w, "Failed to delete comment: "+err.Error(), discussionErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ModerateComment handles a curator hiding, restoring or removing a comment
+func (h *Handler) ModerateComment(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		Action string `json:"action"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Action == "" {
+		http.Error(w, "action is required", http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.svc.ModerateComment(vars["id"], req.Action, strings.TrimSpace(req.Reason), user)
+	if err != nil {
+		http.Error(w, "Failed to moderate comment: "+err.Error(), discussionErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comment)
+}
+
+// discussionErrorStatus maps review and comment errors to HTTP status codes
+func discussionErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.HasSuffix(msg, "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(msg, "only the author"):
+		return http.StatusForbidden
+	case strings.HasPrefix(msg, "you have already"), strings.HasPrefix(msg, "only listed"),
+		strings.Contains(msg, "has been removed"), strings.HasPrefix(msg, "cannot"),
+		strings.HasPrefix(msg, "content is already"):
+		return http.StatusConflict
+	case strings.Contains(msg, "required"), strings.Contains(msg, "must be"),
+		strings.HasPrefix(msg, "unknown"), strings.HasPrefix(msg, "replies may"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
 	"github.com/wesjorgensen/EthAppList/backend/internal/middleware"
 	"github.com/wesjorgensen/EthAppList/backend/internal/models"
 	"github.com/wesjorgensen/EthAppList/backend/internal/service"
@@ -35,24 +39,52 @@ func RegisterAuthHandlers(router *mux.Router, svc *service.Service) {
 func RegisterProductHandlers(router *mux.Router, svc *service.Service) {
 	h := New(svc)
 
-	router.HandleFunc("", h.GetProducts).Methods("GET")
-	router.HandleFunc("/{id}", h.GetProduct).Methods("GET")
-
-	// Revision system endpoints
-	router.HandleFunc("/{id}/history", h.GetProductHistory).Methods("GET")
-	router.HandleFunc("/{id}/revisions/{revision}", h.GetProductRevision).Methods("GET")
-	router.HandleFunc("/{id}/compare/{rev1}/{rev2}", h.CompareProductRevisions).Methods("GET")
+	// Public routes that report viewer state when a token is present
+	publicRouter := router.NewRoute().Subrouter()
+	publicRouter.Use(middleware.OptionalAuth(svc.GetConfig(), svc.CheckUserAccess))
+
+	publicRouter.HandleFunc("", h.GetProducts).Methods("GET")
+	publicRouter.HandleFunc("/by-slug/{slug}", h.GetProductBySlug).Methods("GET")
+	publicRouter.HandleFunc("/by-contract/{chainId}/{address}", h.GetProductByContract).Methods("GET")
+	publicRouter.HandleFunc("/{id}", h.GetProduct).Methods("GET")
+	publicRouter.HandleFunc("/{id}/ratings", h.GetProductRatings).Methods("GET")
+	publicRouter.HandleFunc("/{id}/reviews", h.GetProductReviews).Methods("GET")
+	publicRouter.HandleFunc("/{id}/comments", h.GetProductComments).Methods("GET")
+
+	// Revision system endpoints; unlisted products only show their history
+	// to the submitter and curators
+	publicRouter.HandleFunc("/{id}/history", h.GetProductHistory).Methods("GET")
+	publicRouter.HandleFunc("/{id}/revisions/{revision}", h.GetProductRevision).Methods("GET")
+	publicRouter.HandleFunc("/{id}/compare/{rev1}/{rev2}", h.CompareProductRevisions).Methods("GET")
+	publicRouter.HandleFunc("/{id}/status-history", h.GetProductStatusHistory).Methods("GET")
+
+	// Score assessments
+	publicRouter.HandleFunc("/{id}/scores", h.GetProductScores).Methods("GET")
+	publicRouter.HandleFunc("/{id}/scores/history", h.GetProductScoreHistory).Methods("GET")
 
 	// Protected routes
 	protectedRouter := router.NewRoute().Subrouter()
-	protectedRouter.Use(middleware.Auth(svc.GetConfig()))
+	protectedRouter.Use(middleware.Auth(svc.GetConfig(), svc.CheckUserAccess))
 
 	protectedRouter.HandleFunc("", h.SubmitProduct).Methods("POST")
 	protectedRouter.HandleFunc("/{id}/upvote", h.UpvoteProduct).Methods("POST")
+	protectedRouter.HandleFunc("/{id}/upvote", h.RemoveUpvote).Methods("DELETE")
 	protectedRouter.HandleFunc("/{id}", h.UpdateProduct).Methods("PUT")
+	protectedRouter.HandleFunc("/{id}/status", h.ChangeProductStatus).Methods("POST")
+	protectedRouter.HandleFunc("/{id}/ratings", h.RateProduct).Methods("PUT")
+	protectedRouter.HandleFunc("/{id}/ratings", h.RemoveProductRatings).Methods("DELETE")
+	protectedRouter.HandleFunc("/{id}/reviews", h.CreateReview).Methods("POST")
+	protectedRouter.HandleFunc("/{id}/comments", h.CreateComment).Methods("POST")
 
 	// Admin-only revision routes
 	protectedRouter.HandleFunc("/{id}/revert/{revision}", h.RevertProduct).Methods("POST")
+
+	// Curator-only routes
+	curatorRouter := router.NewRoute().Subrouter()
+	curatorRouter.Use(middleware.CuratorOnly(svc.GetConfig(), svc.CheckUserAccess))
+
+	curatorRouter.HandleFunc("/{id}/merge", h.MergeProduct).Methods("POST")
+	curatorRouter.HandleFunc("/{id}/scores/{dimension}", h.AssessProductScore).Methods("PUT")
 }
 
 // RegisterCategoryHandlers registers category-related routes
@@ -60,12 +92,22 @@ func RegisterCategoryHandlers(router *mux.Router, svc *service.Service) {
 	h := New(svc)
 
 	router.HandleFunc("", h.GetCategories).Methods("GET")
+	router.HandleFunc("/tree", h.GetCategoryTree).Methods("GET")
+	router.HandleFunc("/{id}", h.GetCategory).Methods("GET")
 
 	// Protected routes
 	protectedRouter := router.NewRoute().Subrouter()
-	protectedRouter.Use(middleware.Auth(svc.GetConfig()))
+	protectedRouter.Use(middleware.Auth(svc.GetConfig(), svc.CheckUserAccess))
 
 	protectedRouter.HandleFunc("", h.SubmitCategory).Methods("POST")
+
+	// Curator-only routes
+	curatorRouter := router.NewRoute().Subrouter()
+	curatorRouter.Use(middleware.CuratorOnly(svc.GetConfig(), svc.CheckUserAccess))
+
+	curatorRouter.HandleFunc("/{id}", h.UpdateCategory).Methods("PUT")
+	curatorRouter.HandleFunc("/{id}", h.DeleteCategory).Methods("DELETE")
+	curatorRouter.HandleFunc("/{id}/merge", h.MergeCategories).Methods("POST")
 }
 
 // RegisterAdminHandlers registers admin-related routes
@@ -76,6 +118,24 @@ func RegisterAdminHandlers(router *mux.Router, svc *service.Service) {
 	router.HandleFunc("/approve/{id}", h.ApproveEdit).Methods("POST")
 	router.HandleFunc("/reject/{id}", h.RejectEdit).Methods("POST")
 	router.HandleFunc("/recent-edits", h.GetRecentEdits).Methods("GET")
+	router.HandleFunc("/reconcile-upvotes", h.ReconcileUpvoteCounts).Methods("POST")
+	router.HandleFunc("/products/merge", h.MergeProducts).Methods("POST")
+
+	// Vote manipulation review
+	router.HandleFunc("/vote-anomalies", h.GetVoteAnomalies).Methods("GET")
+	router.HandleFunc("/vote-anomalies/analyze", h.AnalyzeVotes).Methods("POST")
+	router.HandleFunc("/vote-anomalies/void", h.VoidAnomalyVotes).Methods("POST")
+	router.HandleFunc("/vote-anomalies/{id}/dismiss", h.DismissVoteAnomaly).Methods("POST")
+	router.HandleFunc("/audit-log", h.GetAuditLog).Methods("GET")
+
+	// User bans, suspensions and probation
+	router.HandleFunc("/users/sanctions", h.GetSanctionedUsers).Methods("GET")
+	router.HandleFunc("/users/{id}/sanctions", h.GetUserSanctions).Methods("GET")
+	router.HandleFunc("/users/{id}/ban", h.BanUser).Methods("POST")
+	router.HandleFunc("/users/{id}/reinstate", h.ReinstateUser).Methods("POST")
+	router.HandleFunc("/users/{id}/probation", h.SetUserProbation).Methods("POST")
+	router.HandleFunc("/users/{id}/probation", h.EndUserProbation).Methods("DELETE")
+	router.HandleFunc("/users/{id}/merge", h.MergeUser).Methods("POST")
 }
 
 // RegisterUserHandlers registers user-related routes
@@ -84,10 +144,21 @@ func RegisterUserHandlers(router *mux.Router, svc *service.Service) {
 
 	// Protected routes that require authentication
 	protectedRouter := router.NewRoute().Subrouter()
-	protectedRouter.Use(middleware.Auth(svc.GetConfig()))
+	protectedRouter.Use(middleware.Auth(svc.GetConfig(), svc.CheckUserAccess))
 
 	protectedRouter.HandleFunc("/profile", h.GetUserProfile).Methods("GET")
+	protectedRouter.HandleFunc("/profile", h.UpdateUserProfile).Methods("PUT")
 	protectedRouter.HandleFunc("/permissions", h.GetUserPermissions).Methods("GET")
+	protectedRouter.HandleFunc("/upvotes", h.GetUserUpvotes).Methods("GET")
+	protectedRouter.HandleFunc("/reputation", h.GetUserReputation).Methods("GET")
+	protectedRouter.HandleFunc("/ens/refresh", h.RefreshUserENS).Methods("POST")
+	protectedRouter.HandleFunc("/twitter/challenge", h.CreateTwitterChallenge).Methods("POST")
+	protectedRouter.HandleFunc("/twitter/verify", h.VerifyTwitterChallenge).Methods("POST")
+	protectedRouter.HandleFunc("/twitter", h.UnlinkTwitterHandle).Methods("DELETE")
+	protectedRouter.HandleFunc("/wallets", h.GetUserWallets).Methods("GET")
+	protectedRouter.HandleFunc("/wallets/link-request", h.CreateWalletLinkRequest).Methods("POST")
+	protectedRouter.HandleFunc("/wallets/link", h.LinkWallet).Methods("POST")
+	protectedRouter.HandleFunc("/wallets/{address}", h.UnlinkWallet).Methods("DELETE")
 }
 
 // AuthenticateWallet handles wallet authentication
@@ -106,7 +177,11 @@ func (h *Handler) AuthenticateWallet(w http.ResponseWriter, r *http.Request) {
 
 	token, err := h.svc.AuthenticateWallet(req.WalletAddress, req.Signature, req.Message)
 	if err != nil {
-		http.Error(w, "Authentication failed: "+err.Error(), http.StatusUnauthorized)
+		status := http.StatusUnauthorized
+		if strings.HasPrefix(err.Error(), "account ") {
+			status = http.StatusForbidden
+		}
+		http.Error(w, "Authentication failed: "+err.Error(), status)
 		return
 	}
 
@@ -154,9 +229,54 @@ func (h *Handler) GetProducts(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Tags may be repeated or comma-separated; products must carry all of them
+	var tags []string
+	for _, param := range r.URL.Query()["tag"] {
+		for _, tag := range strings.Split(param, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	// Listing products outside the public states is a curator task
+	var statuses []string
+	if statusParam := r.URL.Query().Get("status"); statusParam != "" {
+		statuses = strings.Split(statusParam, ",")
+		for _, status := range statuses {
+			if lifecycle.IsPublic(status) {
+				continue
+			}
+			user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+			if !ok || !h.svc.IsUserCurator(user.WalletAddress) {
+				http.Error(w, "Forbidden: curator access required to list "+status+" products", http.StatusForbidden)
+				return
+			}
+		}
+	}
+
 	// Call the service to get products
-	products, total, err := h.svc.GetProducts(categoryID, chainID, searchTerm, sortOption, page, perPage)
+	products, total, err := h.svc.GetProducts(models.ProductFilter{
+		CategoryID:  categoryID,
+		ChainID:     chainID,
+		Tags:        tags,
+		Status:      statuses,
+		SearchQuery: searchTerm,
+		SortBy:      sortOption,
+		Page:        page,
+		PerPage:     perPage,
+	})
 	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "unknown status") {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, "Failed to get products: "+err.Error(), status)
+		return
+	}
+
+	// Flag the products the caller has upvoted, if they are signed in
+	if err := h.svc.MarkViewerUpvotes(h.viewerID(r), products...); err != nil {
 		http.Error(w, "Failed to get products: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -185,12 +305,64 @@ func (h *Handler) GetProduct(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	product, err := h.svc.GetProduct(id)
+	product, err := h.svc.GetProduct(id, h.viewer(r))
+	if err != nil {
+		http.Error(w, "Failed to get product: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	// Flag whether the caller has upvoted this product, if they are signed in
+	if err := h.svc.MarkViewerUpvotes(h.viewerID(r), product); err != nil {
+		http.Error(w, "Failed to get product: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// GetProductBySlug handles getting a product by its slug. Old slugs answer
+// with a permanent redirect to the product's current slug.
+func (h *Handler) GetProductBySlug(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	product, redirected, err := h.svc.GetProductBySlug(vars["slug"], h.viewer(r))
 	if err != nil {
 		http.Error(w, "Failed to get product: "+err.Error(), http.StatusNotFound)
 		return
 	}
 
+	if redirected {
+		http.Redirect(w, r, "/api/products/by-slug/"+url.PathEscape(product.Slug), http.StatusMovedPermanently)
+		return
+	}
+
+	// Flag whether the caller has upvoted this product, if they are signed in
+	if err := h.svc.MarkViewerUpvotes(h.viewerID(r), product); err != nil {
+		http.Error(w, "Failed to get product: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// GetProductByContract handles finding the product that deployed a contract
+func (h *Handler) GetProductByContract(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	product, err := h.svc.GetProductByContract(vars["chainId"], vars["address"], h.viewer(r))
+	if err != nil {
+		http.Error(w, "Failed to get product: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	// Flag whether the caller has upvoted this product, if they are signed in
+	if err := h.svc.MarkViewerUpvotes(h.viewerID(r), product); err != nil {
+		http.Error(w, "Failed to get product: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(product)
 }
@@ -215,19 +387,32 @@ func (h *Handler) SubmitProduct(w http.ResponseWriter, r *http.Request) {
 		user = fullUser
 	}
 
-	var product models.Product
-	err := json.NewDecoder(r.Body).Decode(&product)
+	// confirm_duplicate lets the submitter insist after a 409 listed likely duplicates
+	var req struct {
+		models.Product
+		ConfirmDuplicate bool `json:"confirm_duplicate"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
-
-	// Set submitter ID from the user (either from token or looked up)
-	product.SubmitterID = user.ID
-
-	err = h.svc.SubmitProduct(&product)
+	product := req.Product
+
+	// The submitter is the user (either from token or looked up)
+	err = h.svc.SubmitProduct(&product, user, req.ConfirmDuplicate)
+	var duplicateErr *service.DuplicateProductError
+	if errors.As(err, &duplicateErr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":      err.Error(),
+			"candidates": duplicateErr.Candidates,
+		})
+		return
+	}
 	if err != nil {
-		http.Error(w, "Failed to submit product: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to submit product: "+err.Error(), productErrorStatus(err))
 		return
 	}
 
@@ -261,9 +446,14 @@ func (h *Handler) UpvoteProduct(w http.ResponseWriter, r *http.Request) {
 
 	err := h.svc.UpvoteProduct(user.ID, productID)
 	if err != nil {
-		if err.Error() == "already upvoted" {
+		switch msg := err.Error(); {
+		case msg == "already upvoted":
 			http.Error(w, "Already upvoted", http.StatusConflict)
-		} else {
+		case msg == "product not found":
+			http.Error(w, "Product not found", http.StatusNotFound)
+		case strings.HasPrefix(msg, "only listed"):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
 			http.Error(w, "Failed to upvote product: "+err.Error(), http.StatusInternalServerError)
 		}
 		return
@@ -272,6 +462,42 @@ func (h *Handler) UpvoteProduct(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// RemoveUpvote handles retracting an upvote from a product
+func (h *Handler) RemoveUpvote(w http.ResponseWriter, r *http.Request) {
+	// Get user from context
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Check if user ID is missing
+	if user.ID == "" {
+		// Look up the user from the database by wallet address
+		fullUser, err := h.svc.GetUserByWallet(user.WalletAddress)
+		if err != nil {
+			http.Error(w, "Failed to get user: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		user = fullUser
+	}
+
+	vars := mux.Vars(r)
+	productID := vars["id"]
+
+	err := h.svc.RemoveUpvote(user.ID, productID)
+	if err != nil {
+		if err.Error() == "upvote not found" {
+			http.Error(w, "Upvote not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to remove upvote: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // GetCategories handles getting all categories
 func (h *Handler) GetCategories(w http.ResponseWriter, r *http.Request) {
 	categories, err := h.svc.GetCategories()
@@ -284,18 +510,16 @@ func (h *Handler) GetCategories(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(categories)
 }
 
-// SubmitCategory handles category submission
+// SubmitCategory handles category submission. Curators create categories
+// directly; other users' submissions are queued for review.
 func (h *Handler) SubmitCategory(w http.ResponseWriter, r *http.Request) {
 	// Get user from context
-	_, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
 	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	// Note: Current implementation doesn't use user ID for category creation
-	// but we might need it in the future for audit trails or permissions
-
 	var category models.Category
 	err := json.NewDecoder(r.Body).Decode(&category)
 	if err != nil {
@@ -303,13 +527,21 @@ func (h *Handler) SubmitCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.svc.SubmitCategory(&category)
+	created, err := h.svc.SubmitCategory(&category, user)
 	if err != nil {
-		http.Error(w, "Failed to submit category: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to submit category: "+err.Error(), categoryErrorStatus(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if !created {
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "Category submitted for review",
+		})
+		return
+	}
+
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(category)
 }
@@ -354,6 +586,21 @@ func (h *Handler) RejectEdit(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// ReconcileUpvoteCounts handles recomputing upvote counters from the upvotes table
+func (h *Handler) ReconcileUpvoteCounts(w http.ResponseWriter, r *http.Request) {
+	// Only report drift unless ?dry_run=false is given
+	repair := r.URL.Query().Get("dry_run") == "false"
+
+	report, err := h.svc.ReconcileUpvoteCounts(repair)
+	if err != nil {
+		http.Error(w, "Failed to reconcile upvote counts: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
 // DeleteAllProducts handles the temporary endpoint to delete all products
 func (h *Handler) DeleteAllProducts(w http.ResponseWriter, r *http.Request) {
 	err := h.svc.DeleteAllProducts()
@@ -398,9 +645,9 @@ func (h *Handler) GetProductHistory(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	revisions, total, err := h.svc.GetProductHistory(productID, page, perPage)
+	revisions, total, err := h.svc.GetProductHistory(productID, page, perPage, h.viewer(r))
 	if err != nil {
-		http.Error(w, "Failed to get product history: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to get product history: "+err.Error(), productVisibilityErrorStatus(err))
 		return
 	}
 
@@ -434,7 +681,7 @@ func (h *Handler) GetProductRevision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	productRevision, err := h.svc.GetProductRevision(productID, revision)
+	productRevision, err := h.svc.GetProductRevision(productID, revision, h.viewer(r))
 	if err != nil {
 		http.Error(w, "Failed to get product revision: "+err.Error(), http.StatusNotFound)
 		return
@@ -463,9 +710,9 @@ func (h *Handler) CompareProductRevisions(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	diff, err := h.svc.CompareProductRevisions(productID, rev1, rev2)
+	diff, err := h.svc.CompareProductRevisions(productID, rev1, rev2, h.viewer(r))
 	if err != nil {
-		http.Error(w, "Failed to compare revisions: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to compare revisions: "+err.Error(), productVisibilityErrorStatus(err))
 		return
 	}
 
@@ -473,7 +720,7 @@ func (h *Handler) CompareProductRevisions(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(diff)
 }
 
-// RevertProduct handles reverting a product to a specific revision (admin only)
+// RevertProduct handles reverting a product to a specific revision
 func (h *Handler) RevertProduct(w http.ResponseWriter, r *http.Request) {
 	// Get user from context
 	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
@@ -493,8 +740,7 @@ func (h *Handler) RevertProduct(w http.ResponseWriter, r *http.Request) {
 		user = fullUser
 	}
 
-	// TODO: Add admin check here - for now allowing any authenticated user
-	// In production, you would check if user.IsAdmin or similar
+	// The service checks the user has the reputation to revert
 
 	vars := mux.Vars(r)
 	productID := vars["id"]
@@ -523,7 +769,7 @@ func (h *Handler) RevertProduct(w http.ResponseWriter, r *http.Request) {
 
 	err = h.svc.RevertProduct(productID, revision, user.ID, req.Reason)
 	if err != nil {
-		http.Error(w, "Failed to revert product: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to revert product: "+err.Error(), productErrorStatus(err))
 		return
 	}
 
@@ -611,13 +857,20 @@ func (h *Handler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	// Ensure the product ID matches the URL parameter
 	req.Product.ID = productID
 
-	err = h.svc.UpdateProduct(&req.Product, user.ID, req.EditSummary, req.MinorEdit)
+	queued, err := h.svc.UpdateProduct(&req.Product, user, req.EditSummary, req.MinorEdit)
 	if err != nil {
-		http.Error(w, "Failed to update product: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to update product: "+err.Error(), productErrorStatus(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if queued {
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "Edit submitted for review",
+		})
+		return
+	}
 	json.NewEncoder(w).Encode(map[string]string{
 		"message": "Product updated successfully",
 	})
@@ -633,7 +886,7 @@ func (h *Handler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get full user data from database
-	fullUser, err := h.svc.GetUserByWallet(user.WalletAddress)
+	fullUser, err := h.svc.GetUserProfile(user.WalletAddress)
 	if err != nil {
 		http.Error(w, "Failed to get user profile: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -655,6 +908,78 @@ func (h *Handler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// GetUserUpvotes handles listing the products the current user has upvoted
+func (h *Handler) GetUserUpvotes(w http.ResponseWriter, r *http.Request) {
+	userID := h.viewerID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse pagination parameters
+	page := 1
+	perPage := 20
+
+	pageStr := r.URL.Query().Get("page")
+	perPageStr := r.URL.Query().Get("per_page")
+
+	if pageStr != "" {
+		parsedPage, err := strconv.Atoi(pageStr)
+		if err == nil && parsedPage > 0 {
+			page = parsedPage
+		}
+	}
+
+	if perPageStr != "" {
+		parsedPerPage, err := strconv.Atoi(perPageStr)
+		if err == nil && parsedPerPage > 0 && parsedPerPage <= 100 {
+			perPage = parsedPerPage
+		}
+	}
+
+	upvotes, total, err := h.svc.GetUserUpvotes(userID, page, perPage)
+	if err != nil {
+		http.Error(w, "Failed to get upvotes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		Upvotes []models.Upvote `json:"upvotes"`
+		Total   int             `json:"total"`
+		Page    int             `json:"page"`
+		PerPage int             `json:"per_page"`
+		Pages   int             `json:"pages"`
+	}{
+		Upvotes: upvotes,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+		Pages:   (total + perPage - 1) / perPage,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetUserReputation handles getting the current user's reputation and the
+// privileges it unlocks
+func (h *Handler) GetUserReputation(w http.ResponseWriter, r *http.Request) {
+	userID := h.viewerID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	rep, err := h.svc.GetUserReputation(userID)
+	if err != nil {
+		http.Error(w, "Failed to get reputation: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rep)
+}
+
 // GetUserPermissions handles checking user permissions (curator/admin status)
 func (h *Handler) GetUserPermissions(w http.ResponseWriter, r *http.Request) {
 	// Get user from context (middleware ensures user is authenticated)
@@ -667,9 +992,8 @@ func (h *Handler) GetUserPermissions(w http.ResponseWriter, r *http.Request) {
 	// Check admin status
 	isAdmin := h.svc.IsUserAdmin(user.WalletAddress)
 
-	// For now, curator status is the same as admin status
-	// In the future, this could be expanded to have separate curator roles
-	isCurator := isAdmin
+	// Curators are the admin plus any configured curator wallets
+	isCurator := h.svc.IsUserCurator(user.WalletAddress)
 
 	response := struct {
 		IsAdmin   bool `json:"is_admin"`
@@ -682,3 +1006,70 @@ func (h *Handler) GetUserPermissions(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// viewerID returns the ID of the authenticated user on the request, looking it
+// up by wallet for older tokens without an ID. It returns "" for anonymous requests.
+func (h *Handler) viewerID(r *http.Request) string {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		return ""
+	}
+
+	if user.ID == "" {
+		fullUser, err := h.svc.GetUserByWallet(user.WalletAddress)
+		if err != nil {
+			return ""
+		}
+		return fullUser.ID
+	}
+
+	return user.ID
+}
+
+// viewer returns the authenticated user on the request with its ID filled in,
+// or nil for anonymous requests
+func (h *Handler) viewer(r *http.Request) *models.User {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		return nil
+	}
+
+	if user.ID == "" {
+		fullUser, err := h.svc.GetUserByWallet(user.WalletAddress)
+		if err != nil {
+			return nil
+		}
+		return fullUser
+	}
+
+	return user
+}
+
+// productVisibilityErrorStatus maps errors from reading a product's history
+// to HTTP status codes; products the viewer may not see are not found
+func productVisibilityErrorStatus(err error) int {
+	if err.Error() == "product not found" {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// productErrorStatus maps product submission and update errors to HTTP status codes
+func productErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case msg == "product not found":
+		return http.StatusNotFound
+	case strings.Contains(msg, "on probation"), strings.Contains(msg, "requires a reputation"):
+		return http.StatusForbidden
+	case strings.Contains(msg, "already listed under another product"):
+		return http.StatusConflict
+	case strings.HasPrefix(msg, "tag names must"), strings.Contains(msg, "at most"),
+		strings.HasPrefix(msg, "invalid"), strings.HasPrefix(msg, "unknown link type"),
+		strings.Contains(msg, "must be"), strings.Contains(msg, "EIP-55"),
+		strings.HasPrefix(msg, "contract"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
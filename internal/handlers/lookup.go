@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/service"
+)
+
+// RegisterLookupHandlers registers contract lookup routes
+func RegisterLookupHandlers(router *mux.Router, svc *service.Service) {
+	h := New(svc)
+
+	router.HandleFunc("/contract/{chainId}/{address}", h.LookupContract).Methods("GET")
+	router.HandleFunc("/contracts", h.LookupContracts).Methods("POST")
+}
+
+// LookupContract handles resolving a contract address to the product behind it
+func (h *Handler) LookupContract(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	chainID, err := strconv.ParseInt(vars["chainId"], 10, 64)
+	if err != nil || chainID <= 0 {
+		http.Error(w, "chainId must be a positive EIP-155 chain ID", http.StatusBadRequest)
+		return
+	}
+	if !common.IsHexAddress(vars["address"]) {
+		http.Error(w, "address must be a contract address", http.StatusBadRequest)
+		return
+	}
+
+	match, ok := h.svc.LookupContract(chainID, normalizeAddress(vars["address"]))
+	if !ok {
+		http.Error(w, "No product found for this contract", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(match)
+}
+
+// LookupContracts handles resolving many contract addresses at once. Unknown
+// contracts come back with a null match rather than failing the batch.
+func (h *Handler) LookupContracts(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Lookups []service.ContractLookup `json:"lookups"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Lookups) == 0 {
+		http.Error(w, "lookups is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Lookups) > service.MaxContractLookups {
+		http.Error(w, fmt.Sprintf("at most %d lookups are allowed per request", service.MaxContractLookups), http.StatusBadRequest)
+		return
+	}
+	for i, lookup := range req.Lookups {
+		if lookup.ChainID <= 0 || !common.IsHexAddress(lookup.Address) {
+			http.Error(w, fmt.Sprintf("lookup %d needs a positive chain_id and a contract address", i), http.StatusBadRequest)
+			return
+		}
+		req.Lookups[i].Address = normalizeAddress(lookup.Address)
+	}
+
+	results := h.svc.LookupContracts(req.Lookups)
+
+	response := struct {
+		Results []service.ContractLookupResult `json:"results"`
+		Count   int                            `json:"count"`
+	}{
+		Results: results,
+		Count:   len(results),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// normalizeAddress returns a hex address in the lowercase 0x-prefixed form
+// contracts are indexed under, adding the prefix when it was left off
+func normalizeAddress(address string) string {
+	return strings.ToLower(common.HexToAddress(address).Hex())
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/middleware"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/service"
+)
+
+// RegisterReportHandlers registers routes for filing reports
+func RegisterReportHandlers(router *mux.Router, svc *service.Service) {
+	h := New(svc)
+
+	router.HandleFunc("/reasons", h.GetReportReasons).Methods("GET")
+
+	protectedRouter := router.NewRoute().Subrouter()
+	protectedRouter.Use(middleware.Auth(svc.GetConfig(), svc.CheckUserAccess))
+	protectedRouter.HandleFunc("", h.CreateReport).Methods("POST")
+}
+
+// RegisterModerationHandlers registers the moderation queue routes. The
+// router is expected to be restricted to curators.
+func RegisterModerationHandlers(router *mux.Router, svc *service.Service) {
+	h := New(svc)
+
+	router.HandleFunc("/cases", h.GetModerationQueue).Methods("GET")
+	router.HandleFunc("/cases/{id}", h.GetModerationCase).Methods("GET")
+	router.HandleFunc("/cases/{id}/assign", h.AssignModerationCase).Methods("POST")
+	router.HandleFunc("/cases/{id}/resolve", h.ResolveModerationCase).Methods("POST")
+	router.HandleFunc("/cases/{id}/reopen", h.ReopenModerationCase).Methods("POST")
+}
+
+// GetReportReasons handles listing the reason codes a report may give
+func (h *Handler) GetReportReasons(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]string{"reasons": service.ReportReasons})
+}
+
+// CreateReport handles a user reporting a product, revision, review, comment or user
+func (h *Handler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		TargetType string `json:"target_type"`
+		TargetID   string `json:"target_id"`
+		Reason     string `json:"reason"`
+		Details    string `json:"details"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TargetType == "" || req.TargetID == "" || req.Reason == "" {
+		http.Error(w, "target_type, target_id and reason are required", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.svc.ReportContent(req.TargetType, req.TargetID, req.Reason, req.Details, user)
+	if err != nil {
+		http.Error(w, "Failed to file report: "+err.Error(), moderationErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetModerationQueue handles listing moderation cases
+func (h *Handler) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := models.ModerationCaseFilter{
+		AssigneeID: query.Get("assignee"),
+		TargetType: query.Get("target_type"),
+		Page:       1,
+		PerPage:    20,
+	}
+	if status := query.Get("status"); status != "" {
+		filter.Statuses = strings.Split(status, ",")
+	}
+	if filter.AssigneeID == "me" {
+		filter.AssigneeID = h.viewerID(r)
+	}
+	if parsed, err := strconv.Atoi(query.Get("page")); err == nil && parsed > 0 {
+		filter.Page = parsed
+	}
+	if parsed, err := strconv.Atoi(query.Get("per_page")); err == nil && parsed > 0 && parsed <= 100 {
+		filter.PerPage = parsed
+	}
+
+	cases, total, err := h.svc.GetModerationQueue(filter)
+	if err != nil {
+		http.Error(w, "Failed to get moderation queue: "+err.Error(), moderationErrorStatus(err))
+		return
+	}
+
+	response := struct {
+		Cases   []models.ModerationCase `json:"cases"`
+		Total   int                     `json:"total"`
+		Page    int                     `json:"page"`
+		PerPage int                     `json:"per_page"`
+		Pages   int                     `json:"pages"`
+	}{
+		Cases:   cases,
+		Total:   total,
+		Page:    filter.Page,
+		PerPage: filter.PerPage,
+		Pages:   (total + filter.PerPage - 1) / filter.PerPage,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetModerationCase handles getting a case with its reports and history
+func (h *Handler) GetModerationCase(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	c, err := h.svc.GetModerationCase(vars["id"])
+	if err != nil {
+		http.Error(w, "Failed to get case: "+err.Error(), moderationErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c)
+}
+
+// AssignModerationCase handles assigning a case to a moderator
+func (h *Handler) AssignModerationCase(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		AssigneeID string `json:"assignee_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.AssigneeID == "me" {
+		req.AssigneeID = user.ID
+	}
+
+	c, err := h.svc.AssignModerationCase(vars["id"], req.AssigneeID, user)
+	if err != nil {
+		http.Error(w, "Failed to assign case: "+err.Error(), moderationErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c)
+}
+
+// ResolveModerationCase handles closing a case with an action or dismissing it
+func (h *Handler) ResolveModerationCase(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		Action string `json:"action"`
+		Note   string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Action == "" {
+		http.Error(w, "action is required", http.StatusBadRequest)
+		return
+	}
+
+	c, err := h.svc.ResolveModerationCase(vars["id"], req.Action, strings.TrimSpace(req.Note), user)
+	if err != nil {
+		http.Error(w, "Failed to resolve case: "+err.Error(), moderationErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c)
+}
+
+// ReopenModerationCase handles returning a closed case to the queue
+func (h *Handler) ReopenModerationCase(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	// The body is optional
+	json.NewDecoder(r.Body).Decode(&req)
+
+	c, err := h.svc.ReopenModerationCase(vars["id"], strings.TrimSpace(req.Note), user)
+	if err != nil {
+		http.Error(w, "Failed to reopen case: "+err.Error(), moderationErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c)
+}
+
+// moderationErrorStatus maps report and moderation case errors to HTTP status codes
+func moderationErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.HasSuffix(msg, "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(msg, "you have already"), strings.HasPrefix(msg, "case is"),
+		strings.HasPrefix(msg, "another case"), strings.HasPrefix(msg, "cannot move"),
+		strings.Contains(msg, "has been"), strings.HasPrefix(msg, "curators cannot"),
+		strings.HasPrefix(msg, "the author of this content"):
+		return http.StatusConflict
+	case strings.HasPrefix(msg, "unknown"), strings.Contains(msg, "required"),
+		strings.Contains(msg, "must be"), strings.HasPrefix(msg, "cannot"),
+		strings.HasPrefix(msg, "you cannot"), strings.HasPrefix(msg, "cases may only"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
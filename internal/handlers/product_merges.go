@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/middleware"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// MergeProduct handles folding a duplicate product into the one that survives it
+func (h *Handler) MergeProduct(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		TargetID string `json:"target_id"`
+		Reason   string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TargetID == "" {
+		http.Error(w, "target_id is required", http.StatusBadRequest)
+		return
+	}
+
+	survivor, moved, err := h.svc.MergeProducts(vars["id"], req.TargetID, req.Reason, user)
+	if err != nil {
+		http.Error(w, "Failed to merge products: "+err.Error(), productMergeErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"product":       survivor,
+		"upvotes_moved": moved,
+	})
+}
+
+// MergeProducts handles folding any number of duplicates into a chosen survivor
+func (h *Handler) MergeProducts(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		SurvivorID string   `json:"survivor_id"`
+		ProductIDs []string `json:"product_ids"`
+		Reason     string   `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SurvivorID == "" {
+		http.Error(w, "survivor_id is required", http.StatusBadRequest)
+		return
+	}
+
+	survivor, moved, err := h.svc.MergeProductsInto(req.SurvivorID, req.ProductIDs, req.Reason, user)
+	if err != nil {
+		http.Error(w, "Failed to merge products: "+err.Error(), productMergeErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"product":       survivor,
+		"upvotes_moved": moved,
+	})
+}
+
+// productMergeErrorStatus maps product merge errors to HTTP status codes
+func productMergeErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.HasSuffix(msg, "product not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "already been merged"):
+		return http.StatusConflict
+	case strings.Contains(msg, "cannot merge"), strings.Contains(msg, "required"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/middleware"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// ChangeProductStatus handles moving a product to another lifecycle state
+func (h *Handler) ChangeProductStatus(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Check if user ID is missing
+	if user.ID == "" {
+		fullUser, err := h.svc.GetUserByWallet(user.WalletAddress)
+		if err != nil {
+			http.Error(w, "Failed to get user: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		user = fullUser
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		Status      string  `json:"status"`
+		Reason      string  `json:"reason"`
+		SuccessorID *string `json:"successor_id,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Status == "" {
+		http.Error(w, "status is required", http.StatusBadRequest)
+		return
+	}
+
+	product, err := h.svc.ChangeProductStatus(vars["id"], req.Status, strings.TrimSpace(req.Reason), req.SuccessorID, user)
+	if err != nil {
+		http.Error(w, "Failed to change product status: "+err.Error(), productStatusErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// GetProductStatusHistory handles listing a product's lifecycle moves
+func (h *Handler) GetProductStatusHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	changes, err := h.svc.GetProductStatusHistory(vars["id"], h.viewer(r))
+	if err != nil {
+		http.Error(w, "Failed to get status history: "+err.Error(), productVisibilityErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"changes": changes,
+		"count":   len(changes),
+	})
+}
+
+// productStatusErrorStatus maps lifecycle errors to HTTP status codes
+func productStatusErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case msg == "product not found", strings.HasPrefix(msg, "successor product not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(msg, "only "):
+		return http.StatusForbidden
+	case strings.HasPrefix(msg, "cannot move"), strings.Contains(msg, "concurrently"):
+		return http.StatusConflict
+	case strings.HasPrefix(msg, "unknown status"), strings.Contains(msg, "required"),
+		strings.Contains(msg, "successor"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// GetProductRatings handles getting a product's community ratings and their distribution
+func (h *Handler) GetProductRatings(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	ratings, err := h.svc.GetProductRatings(vars["id"], h.viewer(r))
+	if err != nil {
+		http.Error(w, "Failed to get ratings: "+err.Error(), ratingErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ratings)
+}
+
+// RateProduct handles setting the caller's ratings of a product
+func (h *Handler) RateProduct(w http.ResponseWriter, r *http.Request) {
+	user := h.viewer(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		Ratings map[string]*float64 `json:"ratings"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.RateProduct(user.ID, vars["id"], req.Ratings); err != nil {
+		http.Error(w, "Failed to rate product: "+err.Error(), ratingErrorStatus(err))
+		return
+	}
+
+	ratings, err := h.svc.GetProductRatings(vars["id"], user)
+	if err != nil {
+		http.Error(w, "Failed to get ratings: "+err.Error(), ratingErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ratings)
+}
+
+// RemoveProductRatings handles withdrawing all of the caller's ratings of a product
+func (h *Handler) RemoveProductRatings(w http.ResponseWriter, r *http.Request) {
+	userID := h.viewerID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	if err := h.svc.RemoveProductRatings(userID, vars["id"]); err != nil {
+		http.Error(w, "Failed to remove ratings: "+err.Error(), ratingErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ratingErrorStatus maps rating errors to HTTP status codes
+func ratingErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case msg == "product not found", msg == "rating not found":
+		return http.StatusNotFound
+	case strings.HasPrefix(msg, "only listed"):
+		return http.StatusConflict
+	case strings.HasPrefix(msg, "unknown"), strings.Contains(msg, "must be"),
+		strings.Contains(msg, "required"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/middleware"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/service"
+)
+
+// RegisterScoringHandlers registers score methodology routes
+func RegisterScoringHandlers(router *mux.Router, svc *service.Service) {
+	h := New(svc)
+
+	router.HandleFunc("/rubrics", h.GetScoreRubrics).Methods("GET")
+}
+
+// GetScoreRubrics handles listing the rubric behind each score dimension
+func (h *Handler) GetScoreRubrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.svc.GetScoreRubrics())
+}
+
+// GetProductScores handles getting the assessments behind a product's scores
+func (h *Handler) GetProductScores(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	assessments, err := h.svc.GetProductScores(vars["id"], h.viewer(r))
+	if err != nil {
+		http.Error(w, "Failed to get product scores: "+err.Error(), scoreErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"assessments": assessments,
+		"count":       len(assessments),
+	})
+}
+
+// AssessProductScore handles setting a product score from a rubric assessment
+func (h *Handler) AssessProductScore(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		Criteria []models.ScoreCriterion `json:"criteria"`
+		Note     string                  `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Criteria) == 0 {
+		http.Error(w, "criteria is required", http.StatusBadRequest)
+		return
+	}
+
+	assessment, err := h.svc.AssessProductScore(vars["id"], vars["dimension"], req.Criteria, req.Note, user)
+	if err != nil {
+		http.Error(w, "Failed to assess score: "+err.Error(), scoreErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assessment)
+}
+
+// GetProductScoreHistory handles listing past assessments of a product's scores
+func (h *Handler) GetProductScoreHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	changes, err := h.svc.GetScoreHistory(vars["id"], r.URL.Query().Get("dimension"), limit, h.viewer(r))
+	if err != nil {
+		http.Error(w, "Failed to get score history: "+err.Error(), scoreErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"changes": changes,
+		"count":   len(changes),
+	})
+}
+
+// scoreErrorStatus maps score assessment errors to HTTP status codes
+func scoreErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case msg == "product not found":
+		return http.StatusNotFound
+	case strings.HasPrefix(msg, "unknown"), strings.HasPrefix(msg, "criterion"),
+		strings.Contains(msg, "must be"), strings.Contains(msg, "at most"),
+		strings.Contains(msg, "must apply"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/middleware"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/service"
+)
+
+// RegisterTagHandlers registers tag-related routes
+func RegisterTagHandlers(router *mux.Router, svc *service.Service) {
+	h := New(svc)
+
+	router.HandleFunc("", h.GetTags).Methods("GET")
+	router.HandleFunc("/suggest", h.SuggestTags).Methods("GET", "POST")
+	router.HandleFunc("/{id}", h.GetTag).Methods("GET")
+
+	// Curator-only routes
+	curatorRouter := router.NewRoute().Subrouter()
+	curatorRouter.Use(middleware.CuratorOnly(svc.GetConfig(), svc.CheckUserAccess))
+
+	curatorRouter.HandleFunc("/{id}", h.UpdateTag).Methods("PUT")
+	curatorRouter.HandleFunc("/{id}", h.DeleteTag).Methods("DELETE")
+	curatorRouter.HandleFunc("/{id}/aliases", h.AddTagAlias).Methods("POST")
+	curatorRouter.HandleFunc("/{id}/aliases/{alias}", h.RemoveTagAlias).Methods("DELETE")
+}
+
+// GetTags handles getting all tags
+func (h *Handler) GetTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.svc.GetTags()
+	if err != nil {
+		http.Error(w, "Failed to get tags: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tags)
+}
+
+// GetTag handles getting a single tag by ID, slug or alias
+func (h *Handler) GetTag(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	tag, err := h.svc.GetTag(vars["id"])
+	if err != nil {
+		http.Error(w, "Failed to get tag: "+err.Error(), tagErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tag)
+}
+
+// SuggestTags handles tag suggestions while a product is being submitted or
+// edited. GET autocompletes the q parameter; POST also accepts the product
+// draft and suggests tags mentioned in its text.
+func (h *Handler) SuggestTags(w http.ResponseWriter, r *http.Request) {
+	var draft *models.Product
+	if r.Method == http.MethodPost {
+		draft = &models.Product{}
+		if err := json.NewDecoder(r.Body).Decode(draft); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	tags, err := h.svc.SuggestTags(r.URL.Query().Get("q"), draft)
+	if err != nil {
+		http.Error(w, "Failed to suggest tags: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"suggestions": tags,
+	})
+}
+
+// UpdateTag handles renaming a tag or changing its slug or description
+func (h *Handler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var tag models.Tag
+	err := json.NewDecoder(r.Body).Decode(&tag)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Ensure the tag ID matches the URL parameter
+	tag.ID = vars["id"]
+
+	err = h.svc.UpdateTag(&tag, user.ID)
+	if err != nil {
+		http.Error(w, "Failed to update tag: "+err.Error(), tagErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tag)
+}
+
+// DeleteTag handles deleting a tag
+func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	err := h.svc.DeleteTag(vars["id"], user.ID)
+	if err != nil {
+		http.Error(w, "Failed to delete tag: "+err.Error(), tagErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddTagAlias handles mapping an alias onto a tag
+func (h *Handler) AddTagAlias(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req struct {
+		Alias string `json:"alias"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Alias == "" {
+		http.Error(w, "alias is required", http.StatusBadRequest)
+		return
+	}
+
+	moved, err := h.svc.AddTagAlias(vars["id"], req.Alias, user.ID)
+	if err != nil {
+		http.Error(w, "Failed to add tag alias: "+err.Error(), tagErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"alias":          req.Alias,
+		"products_moved": moved,
+	})
+}
+
+// RemoveTagAlias handles removing an alias from a tag
+func (h *Handler) RemoveTagAlias(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*models.User)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	err := h.svc.RemoveTagAlias(vars["id"], vars["alias"], user.ID)
+	if err != nil {
+		http.Error(w, "Failed to remove tag alias: "+err.Error(), tagErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// tagErrorStatus maps tag service errors to HTTP status codes
+func tagErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case msg == "tag not found", msg == "tag alias not found":
+		return http.StatusNotFound
+	case strings.Contains(msg, "already"), strings.Contains(msg, "duplicate key"):
+		return http.StatusConflict
+	case strings.Contains(msg, "required"), strings.Contains(msg, "must "):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/middleware"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/service"
+)
+
+// RegisterUserProfileHandlers registers the public profile routes. Users are
+// named by ID or wallet address.
+func RegisterUserProfileHandlers(router *mux.Router, svc *service.Service) {
+	h := New(svc)
+
+	publicRouter := router.NewRoute().Subrouter()
+	publicRouter.Use(middleware.OptionalAuth(svc.GetConfig(), svc.CheckUserAccess))
+
+	publicRouter.HandleFunc("/{user}", h.GetPublicProfile).Methods("GET")
+	publicRouter.HandleFunc("/{user}/submissions", h.GetUserSubmissions).Methods("GET")
+	publicRouter.HandleFunc("/{user}/edits", h.GetUserEdits).Methods("GET")
+	publicRouter.HandleFunc("/{user}/upvotes", h.GetUserUpvotedProducts).Methods("GET")
+}
+
+// UpdateUserProfile handles the current user changing their profile
+func (h *Handler) UpdateUserProfile(w http.ResponseWriter, r *http.Request) {
+	userID := h.viewerID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var update models.ProfileUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.svc.UpdateUserProfile(userID, update)
+	if err != nil {
+		http.Error(w, "Failed to update profile: "+err.Error(), profileErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// RefreshUserENS handles the current user asking for their ENS name and
+// avatar to be looked up again, such as after setting a primary name
+func (h *Handler) RefreshUserENS(w http.ResponseWriter, r *http.Request) {
+	userID := h.viewerID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.svc.RefreshUserENS(userID)
+	if err != nil {
+		http.Error(w, "Failed to refresh ENS name: "+err.Error(), profileErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// GetPublicProfile handles getting a user's public profile
+func (h *Handler) GetPublicProfile(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	profile, err := h.svc.GetPublicProfile(vars["user"], h.viewer(r))
+	if err != nil {
+		http.Error(w, "Failed to get profile: "+err.Error(), profileErrorStatus(err))
+		return
+	}
+
+	if err := h.svc.MarkViewerUpvotes(h.viewerID(r), append(profile.Submissions, profile.Upvoted...)...); err != nil {
+		http.Error(w, "Failed to get profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
+
+// GetUserSubmissions handles listing the products a user submitted
+func (h *Handler) GetUserSubmissions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	page, perPage := discussionPage(r)
+
+	products, total, err := h.svc.GetUserSubmissions(vars["user"], h.viewer(r), page, perPage)
+	if err != nil {
+		http.Error(w, "Failed to get submissions: "+err.Error(), profileErrorStatus(err))
+		return
+	}
+
+	h.writeProfileProducts(w, r, products, total, page, perPage)
+}
+
+// GetUserUpvotedProducts handles listing the products a user upvoted
+func (h *Handler) GetUserUpvotedProducts(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	page, perPage := discussionPage(r)
+
+	products, total, err := h.svc.GetUserUpvotedProducts(vars["user"], h.viewer(r), page, perPage)
+	if err != nil {
+		http.Error(w, "Failed to get upvoted products: "+err.Error(), profileErrorStatus(err))
+		return
+	}
+
+	h.writeProfileProducts(w, r, products, total, page, perPage)
+}
+
+// writeProfileProducts responds with a page of products from a profile section
+func (h *Handler) writeProfileProducts(w http.ResponseWriter, r *http.Request, products []*models.Product, total, page, perPage int) {
+	if err := h.svc.MarkViewerUpvotes(h.viewerID(r), products...); err != nil {
+		http.Error(w, "Failed to get products: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		Products []*models.Product `json:"products"`
+		Total    int               `json:"total"`
+		Page     int               `json:"page"`
+		PerPage  int               `json:"per_page"`
+		Pages    int               `json:"pages"`
+	}{
+		Products: products,
+		Total:    total,
+		Page:     page,
+		PerPage:  perPage,
+		Pages:    (total + perPage - 1) / perPage,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetUserEdits handles listing a user's product edits
+func (h *Handler) GetUserEdits(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	page, perPage := discussionPage(r)
+
+	edits, total, err := h.svc.GetUserEdits(vars["user"], h.viewer(r), page, perPage)
+	if err != nil {
+		http.Error(w, "Failed to get edits: "+err.Error(), profileErrorStatus(err))
+		return
+	}
+
+	response := struct {
+		Edits   []models.UserEdit `json:"edits"`
+		Total   int               `json:"total"`
+		Page    int               `json:"page"`
+		PerPage int               `json:"per_page"`
+		Pages   int               `json:"pages"`
+	}{
+		Edits:   edits,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+		Pages:   (total + perPage - 1) / perPage,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// profileErrorStatus maps profile errors to HTTP status codes
+func profileErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case msg == "user not found":
+		return http.StatusNotFound
+	case strings.HasSuffix(msg, "are private"):
+		return http.StatusForbidden
+	case strings.Contains(msg, "must"):
+		return http.StatusBadRequest
+	case msg == "ENS lookups are not configured":
+		return http.StatusServiceUnavailable
+	case strings.HasPrefix(msg, "failed to look up ENS name"):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
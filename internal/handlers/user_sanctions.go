@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// sanctionRequest is the body of the ban and probation endpoints
+type sanctionRequest struct {
+	Reason string `json:"reason"`
+	Days   int    `json:"days"`
+}
+
+// GetSanctionedUsers handles listing users who are banned, suspended or on probation
+func (h *Handler) GetSanctionedUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.svc.GetSanctionedUsers()
+	if err != nil {
+		http.Error(w, "Failed to get sanctioned users: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"users": users,
+		"count": len(users),
+	})
+}
+
+// GetUserSanctions handles getting a user's sanction state and history
+func (h *Handler) GetUserSanctions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	user, history, err := h.svc.GetUserSanctions(vars["id"])
+	if err != nil {
+		http.Error(w, "Failed to get user sanctions: "+err.Error(), sanctionErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		User    *models.User           `json:"user"`
+		History []models.AuditLogEntry `json:"history"`
+	}{
+		User:    user,
+		History: history,
+	})
+}
+
+// BanUser handles banning a user, or suspending them when days is given
+func (h *Handler) BanUser(w http.ResponseWriter, r *http.Request) {
+	h.applySanction(w, r, true, func(userID string, req sanctionRequest, actor *models.User) (*models.User, error) {
+		return h.svc.BanUser(userID, req.Reason, req.Days, actor)
+	})
+}
+
+// ReinstateUser handles lifting a ban or suspension early
+func (h *Handler) ReinstateUser(w http.ResponseWriter, r *http.Request) {
+	h.applySanction(w, r, false, func(userID string, req sanctionRequest, actor *models.User) (*models.User, error) {
+		return h.svc.ReinstateUser(userID, req.Reason, actor)
+	})
+}
+
+// SetUserProbation handles putting a user on probation
+func (h *Handler) SetUserProbation(w http.ResponseWriter, r *http.Request) {
+	h.applySanction(w, r, true, func(userID string, req sanctionRequest, actor *models.User) (*models.User, error) {
+		return h.svc.SetUserProbation(userID, req.Reason, req.Days, actor)
+	})
+}
+
+// EndUserProbation handles taking a user off probation early
+func (h *Handler) EndUserProbation(w http.ResponseWriter, r *http.Request) {
+	h.applySanction(w, r, false, func(userID string, req sanctionRequest, actor *models.User) (*models.User, error) {
+		return h.svc.EndUserProbation(userID, req.Reason, actor)
+	})
+}
+
+// applySanction decodes a sanction request, applies it to the user named in
+// the path and responds with the updated user. The body is optional unless
+// bodyRequired is set.
+func (h *Handler) applySanction(w http.ResponseWriter, r *http.Request, bodyRequired bool,
+	apply func(userID string, req sanctionRequest, actor *models.User) (*models.User, error)) {
+	actor := h.viewer(r)
+	if actor == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	var req sanctionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && bodyRequired {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+
+	user, err := apply(vars["id"], req, actor)
+	if err != nil {
+		http.Error(w, "Failed to update user: "+err.Error(), sanctionErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// sanctionErrorStatus maps user sanction errors to HTTP status codes
+func sanctionErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case msg == "user not found":
+		return http.StatusNotFound
+	case strings.HasPrefix(msg, "user is not"), strings.HasPrefix(msg, "curators cannot"):
+		return http.StatusConflict
+	case strings.Contains(msg, "required"), strings.Contains(msg, "must be"),
+		strings.HasPrefix(msg, "you cannot"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// CreateTwitterChallenge handles the current user starting to link a
+// Twitter/X handle
+func (h *Handler) CreateTwitterChallenge(w http.ResponseWriter, r *http.Request) {
+	userID := h.viewerID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Handle string `json:"handle"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	challenge, err := h.svc.CreateTwitterChallenge(userID, req.Handle)
+	if err != nil {
+		http.Error(w, "Failed to create challenge: "+err.Error(), twitterErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(challenge)
+}
+
+// VerifyTwitterChallenge handles the current user submitting the post that
+// carries their challenge
+func (h *Handler) VerifyTwitterChallenge(w http.ResponseWriter, r *http.Request) {
+	userID := h.viewerID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		PostURL string `json:"post_url"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.svc.VerifyTwitterChallenge(userID, req.PostURL)
+	if err != nil {
+		http.Error(w, "Failed to verify Twitter handle: "+err.Error(), twitterErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// UnlinkTwitterHandle handles the current user removing their Twitter/X handle
+func (h *Handler) UnlinkTwitterHandle(w http.ResponseWriter, r *http.Request) {
+	userID := h.viewerID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.svc.UnlinkTwitterHandle(userID)
+	if err != nil {
+		http.Error(w, "Failed to unlink Twitter handle: "+err.Error(), twitterErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// twitterErrorStatus maps Twitter linking errors to HTTP status codes
+func twitterErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case msg == "Twitter linking is not configured":
+		return http.StatusServiceUnavailable
+	case msg == "user not found", msg == "no Twitter link challenge is pending":
+		return http.StatusNotFound
+	case strings.HasPrefix(msg, "failed to fetch post"):
+		return http.StatusBadGateway
+	case strings.Contains(msg, "must"), strings.Contains(msg, "expired"),
+		msg == "post not found", strings.HasPrefix(msg, "post was not written"),
+		msg == "post does not contain the challenge":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
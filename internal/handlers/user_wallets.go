@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// GetUserWallets handles listing the wallets that sign in to the current
+// user's account
+func (h *Handler) GetUserWallets(w http.ResponseWriter, r *http.Request) {
+	userID := h.viewerID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	wallets, err := h.svc.GetUserWallets(userID)
+	if err != nil {
+		http.Error(w, "Failed to get wallets: "+err.Error(), walletErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"wallets": wallets,
+	})
+}
+
+// CreateWalletLinkRequest handles the current user starting to link another
+// wallet to their account
+func (h *Handler) CreateWalletLinkRequest(w http.ResponseWriter, r *http.Request) {
+	userID := h.viewerID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		WalletAddress string `json:"wallet_address"`
+		Merge         bool   `json:"merge"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	request, err := h.svc.CreateWalletLinkRequest(userID, req.WalletAddress, req.Merge)
+	if err != nil {
+		http.Error(w, "Failed to create link request: "+err.Error(), walletErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(request)
+}
+
+// LinkWallet handles the current user submitting their link message signed
+// by both wallets
+func (h *Handler) LinkWallet(w http.ResponseWriter, r *http.Request) {
+	userID := h.viewerID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		AccountSignature string `json:"account_signature"`
+		WalletSignature  string `json:"wallet_signature"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.AccountSignature == "" || req.WalletSignature == "" {
+		http.Error(w, "account_signature and wallet_signature are required", http.StatusBadRequest)
+		return
+	}
+
+	wallets, err := h.svc.LinkWallet(userID, req.AccountSignature, req.WalletSignature)
+	if err != nil {
+		http.Error(w, "Failed to link wallet: "+err.Error(), walletErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"wallets": wallets,
+	})
+}
+
+// UnlinkWallet handles the current user removing a linked wallet
+func (h *Handler) UnlinkWallet(w http.ResponseWriter, r *http.Request) {
+	userID := h.viewerID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	wallets, err := h.svc.UnlinkWallet(userID, mux.Vars(r)["address"])
+	if err != nil {
+		http.Error(w, "Failed to unlink wallet: "+err.Error(), walletErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"wallets": wallets,
+	})
+}
+
+// MergeUser handles an admin merging a duplicate account into another
+func (h *Handler) MergeUser(w http.ResponseWriter, r *http.Request) {
+	actor := h.viewer(r)
+	if actor == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Into   string `json:"into"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, moved, err := h.svc.MergeUsers(mux.Vars(r)["id"], req.Into, req.Reason, actor)
+	if err != nil {
+		http.Error(w, "Failed to merge users: "+err.Error(), walletErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user":          user,
+		"wallets_moved": moved,
+	})
+}
+
+// walletErrorStatus maps wallet linking and account merge errors to HTTP
+// status codes
+func walletErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.HasSuffix(msg, "user not found"), msg == "wallet not found",
+		msg == "no wallet link request is pending":
+		return http.StatusNotFound
+	case strings.HasPrefix(msg, "invalid signature"):
+		return http.StatusUnauthorized
+	case strings.Contains(msg, "cannot be"):
+		return http.StatusForbidden
+	case strings.Contains(msg, "already"), strings.Contains(msg, "belongs to another account"),
+		strings.Contains(msg, "has been merged"):
+		return http.StatusConflict
+	case strings.Contains(msg, "must"), strings.Contains(msg, "expired"), strings.Contains(msg, "required"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// GetVoteAnomalies handles listing flagged voting patterns
+func (h *Handler) GetVoteAnomalies(w http.ResponseWriter, r *http.Request) {
+	// Default to anomalies still awaiting review; ?status=all lists everything
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = "open"
+	} else if status == "all" {
+		status = ""
+	}
+
+	anomalies, err := h.svc.GetVoteAnomalies(status)
+	if err != nil {
+		http.Error(w, "Failed to get vote anomalies: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		Anomalies []models.VoteAnomaly `json:"anomalies"`
+		Count     int                  `json:"count"`
+	}{
+		Anomalies: anomalies,
+		Count:     len(anomalies),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// AnalyzeVotes handles running vote analysis immediately instead of waiting for the background run
+func (h *Handler) AnalyzeVotes(w http.ResponseWriter, r *http.Request) {
+	flagged, err := h.svc.AnalyzeVotes()
+	if err != nil {
+		http.Error(w, "Failed to analyze votes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{
+		"flagged": flagged,
+	})
+}
+
+// VoidAnomalyVotes handles voiding the votes of one or more flagged anomalies
+func (h *Handler) VoidAnomalyVotes(w http.ResponseWriter, r *http.Request) {
+	actorID := h.viewerID(r)
+	if actorID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		AnomalyIDs []string `json:"anomaly_ids"`
+		Reason     string   `json:"reason"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.AnomalyIDs) == 0 || req.Reason == "" {
+		http.Error(w, "anomaly_ids and reason are required", http.StatusBadRequest)
+		return
+	}
+
+	voided, err := h.svc.VoidAnomalyVotes(req.AnomalyIDs, actorID, req.Reason)
+	if err != nil {
+		http.Error(w, "Failed to void votes: "+err.Error(), voteAnomalyErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{
+		"votes_voided": voided,
+	})
+}
+
+// DismissVoteAnomaly handles marking a flagged anomaly as a false positive
+func (h *Handler) DismissVoteAnomaly(w http.ResponseWriter, r *http.Request) {
+	actorID := h.viewerID(r)
+	if actorID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	anomalyID := vars["id"]
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.svc.DismissVoteAnomaly(anomalyID, actorID, req.Reason)
+	if err != nil {
+		http.Error(w, "Failed to dismiss anomaly: "+err.Error(), voteAnomalyErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAuditLog handles listing recent administrative actions
+func (h *Handler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	entityType := r.URL.Query().Get("entity_type")
+
+	limit := 50 // Default limit
+
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err == nil && parsedLimit > 0 && parsedLimit <= 200 {
+			limit = parsedLimit
+		}
+	}
+
+	entries, err := h.svc.GetAuditLog(entityType, limit)
+	if err != nil {
+		http.Error(w, "Failed to get audit log: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		Entries []models.AuditLogEntry `json:"entries"`
+		Count   int                    `json:"count"`
+		Limit   int                    `json:"limit"`
+	}{
+		Entries: entries,
+		Count:   len(entries),
+		Limit:   limit,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// voteAnomalyErrorStatus maps vote anomaly errors to HTTP status codes. An
+// anomaly that is missing or already resolved is reported as not found.
+func voteAnomalyErrorStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "open anomaly") && strings.HasSuffix(msg, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "required"), msg == "no anomalies given":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
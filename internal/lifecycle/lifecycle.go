@@ -0,0 +1,115 @@
+package lifecycle
+
+import "fmt"
+
+// Product lifecycle states
+const (
+	Draft       = "draft"        // visible only to the submitter
+	Submitted   = "submitted"    // waiting for a curator
+	InReview    = "in_review"    // a curator is reviewing it
+	Published   = "published"    // listed publicly
+	Deprecated  = "deprecated"   // still listed but superseded, optionally by a successor
+	Delisted    = "delisted"     // removed from listings
+	ScamFlagged = "scam_flagged" // listed with a warning so users can check it
+)
+
+// Role is who is asking for a transition
+type Role int
+
+const (
+	Submitter Role = iota // the user who submitted the product
+	Curator               // a curator or the admin
+)
+
+// transition describes a permitted move between two states
+type transition struct {
+	role          Role // least privileged role allowed to make the move
+	requireReason bool
+}
+
+// transitions lists every permitted move. Curators may make any move a
+// submitter can.
+var transitions = map[string]map[string]transition{
+	Draft: {
+		Submitted: {role: Submitter},
+	},
+	Submitted: {
+		Draft:       {role: Submitter},
+		InReview:    {role: Curator},
+		Published:   {role: Curator},
+		Delisted:    {role: Curator, requireReason: true},
+		ScamFlagged: {role: Curator, requireReason: true},
+	},
+	InReview: {
+		Submitted:   {role: Curator, requireReason: true}, // sent back for changes
+		Published:   {role: Curator},
+		Delisted:    {role: Curator, requireReason: true},
+		ScamFlagged: {role: Curator, requireReason: true},
+	},
+	Published: {
+		Deprecated:  {role: Curator, requireReason: true},
+		Delisted:    {role: Curator, requireReason: true},
+		ScamFlagged: {role: Curator, requireReason: true},
+	},
+	Deprecated: {
+		Published:   {role: Curator, requireReason: true},
+		Delisted:    {role: Curator, requireReason: true},
+		ScamFlagged: {role: Curator, requireReason: true},
+	},
+	Delisted: {
+		Submitted: {role: Curator},
+		Published: {role: Curator, requireReason: true},
+	},
+	ScamFlagged: {
+		Published: {role: Curator, requireReason: true}, // cleared after investigation
+		Delisted:  {role: Curator, requireReason: true},
+	},
+}
+
+// States lists every lifecycle state
+var States = []string{Draft, Submitted, InReview, Published, Deprecated, Delisted, ScamFlagged}
+
+// PublicStates are the states anyone may list products in
+var PublicStates = []string{Published, Deprecated, ScamFlagged}
+
+// Valid reports whether state is a known lifecycle state
+func Valid(state string) bool {
+	_, ok := transitions[state]
+	return ok
+}
+
+// IsPublic reports whether products in state may be listed by anyone
+func IsPublic(state string) bool {
+	for _, public := range PublicStates {
+		if state == public {
+			return true
+		}
+	}
+	return false
+}
+
+// Listed reports whether products in state count as approved listings
+func Listed(state string) bool {
+	return state == Published || state == Deprecated
+}
+
+// Check returns an error unless role may move a product from one state to
+// another with the given reason
+func Check(from, to string, role Role, reason string) error {
+	if !Valid(to) {
+		return fmt.Errorf("unknown status %q", to)
+	}
+
+	t, ok := transitions[from][to]
+	if !ok {
+		return fmt.Errorf("cannot move a product from %s to %s", from, to)
+	}
+	if role < t.role {
+		return fmt.Errorf("only curators may move a product from %s to %s", from, to)
+	}
+	if t.requireReason && reason == "" {
+		return fmt.Errorf("a reason is required to move a product to %s", to)
+	}
+
+	return nil
+}
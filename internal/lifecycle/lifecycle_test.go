@@ -0,0 +1,68 @@
+package lifecycle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		role    Role
+		reason  string
+		wantErr string
+	}{
+		{"submitter submits draft", Draft, Submitted, Submitter, "", ""},
+		{"submitter withdraws submission", Submitted, Draft, Submitter, "", ""},
+		{"curator may make submitter moves", Draft, Submitted, Curator, "", ""},
+		{"curator publishes submission", Submitted, Published, Curator, "", ""},
+		{"curator starts review", Submitted, InReview, Curator, "", ""},
+		{"curator deprecates with reason", Published, Deprecated, Curator, "superseded by v2", ""},
+		{"curator relists delisted product", Delisted, Submitted, Curator, "", ""},
+		{"submitter cannot publish", Submitted, Published, Submitter, "", "only curators may"},
+		{"submitter cannot delist", Published, Delisted, Submitter, "spam", "only curators may"},
+		{"reason required to delist", Published, Delisted, Curator, "", "a reason is required"},
+		{"reason required to send back", InReview, Submitted, Curator, "", "a reason is required"},
+		{"reason required to clear scam flag", ScamFlagged, Published, Curator, "", "a reason is required"},
+		{"draft cannot be published directly", Draft, Published, Curator, "", "cannot move"},
+		{"published cannot return to draft", Published, Draft, Curator, "", "cannot move"},
+		{"no move to the same state", Published, Published, Curator, "", "cannot move"},
+		{"unknown target state", Published, "archived", Curator, "", "unknown status"},
+		{"unknown source state", "archived", Published, Curator, "", "cannot move"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.from, tt.to, tt.role, tt.reason)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Check(%s, %s) = %v, want nil", tt.from, tt.to, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Check(%s, %s) = %v, want error containing %q", tt.from, tt.to, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPublicStates(t *testing.T) {
+	for _, state := range States {
+		if !Valid(state) {
+			t.Errorf("Valid(%s) = false", state)
+		}
+
+		public := state == Published || state == Deprecated || state == ScamFlagged
+		if got := IsPublic(state); got != public {
+			t.Errorf("IsPublic(%s) = %v, want %v", state, got, public)
+		}
+
+		listed := state == Published || state == Deprecated
+		if got := Listed(state); got != listed {
+			t.Errorf("Listed(%s) = %v, want %v", state, got, listed)
+		}
+	}
+}
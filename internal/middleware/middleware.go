@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
@@ -27,6 +28,19 @@ const (
 	UserContextKey UserKey = "user"
 )
 
+// AccessCheck reports whether an authenticated user may use the API and, if
+// not, why. The auth middlewares consult it once a token is valid to turn
+// away banned and suspended users.
+type AccessCheck func(user *models.User) (bool, string, error)
+
+// requireAccessCheck refuses to build auth middleware without an access
+// check, so a missing one cannot let sanctioned users through
+func requireAccessCheck(check AccessCheck) {
+	if check == nil {
+		panic("middleware: an access check is required")
+	}
+}
+
 // Logging middleware logs request information
 func Logging(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -53,69 +67,55 @@ func RequestID(next http.Handler) http.Handler {
 	})
 }
 
-// Auth middleware handles authentication
-func Auth(cfg *config.Config) func(http.Handler) http.Handler {
+// Auth middleware handles authentication, turning away users the access
+// check denies
+func Auth(cfg *config.Config, check AccessCheck) func(http.Handler) http.Handler {
+	requireAccessCheck(check)
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				http.Error(w, "Authorization header is required", http.StatusUnauthorized)
-				return
-			}
-
-			// Extract the token from the Authorization header
-			// Format: "Bearer {token}"
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, "Invalid authorization format", http.StatusUnauthorized)
+			user, err := userFromRequest(cfg, r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
 				return
 			}
 
-			tokenString := parts[1]
-
-			// Parse and validate the token
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, jwt.ErrSignatureInvalid
-				}
-				return []byte(cfg.JWTSecret), nil
-			})
-
+			allowed, reason, err := check(user)
 			if err != nil {
-				http.Error(w, "Invalid token: "+err.Error(), http.StatusUnauthorized)
+				http.Error(w, "Failed to check account status", http.StatusInternalServerError)
 				return
 			}
-
-			if !token.Valid {
-				http.Error(w, "Invalid token", http.StatusUnauthorized)
+			if !allowed {
+				http.Error(w, "Forbidden: "+reason, http.StatusForbidden)
 				return
 			}
 
-			// Extract claims
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				http.Error(w, "Invalid token claims", http.StatusUnauthorized)
-				return
-			}
+			ctx := context.WithValue(r.Context(), UserContextKey, user)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
 
-			// Get wallet address from claims
-			walletAddr, ok := claims["wallet"].(string)
-			if !ok {
-				http.Error(w, "Invalid token: missing wallet address", http.StatusUnauthorized)
+// OptionalAuth middleware attaches the user to the context when the request
+// carries a valid token, and otherwise lets the request through anonymously
+func OptionalAuth(cfg *config.Config, check AccessCheck) func(http.Handler) http.Handler {
+	requireAccessCheck(check)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			// Get user ID from claims if available
-			userId, ok := claims["id"].(string)
-			if !ok {
-				// If ID not in token, log a warning but continue
-				log.Printf("Warning: Token missing user ID for wallet %s", walletAddr)
+			user, err := userFromRequest(cfg, r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
 			}
 
-			// Create user with both wallet address and ID
-			user := &models.User{
-				ID:            userId,
-				WalletAddress: walletAddr,
+			// Banned and suspended users browse anonymously
+			if allowed, _, err := check(user); err != nil || !allowed {
+				next.ServeHTTP(w, r)
+				return
 			}
 
 			ctx := context.WithValue(r.Context(), UserContextKey, user)
@@ -124,12 +124,70 @@ func Auth(cfg *config.Config) func(http.Handler) http.Handler {
 	}
 }
 
+// userFromRequest validates the bearer token on a request and returns the user it identifies
+func userFromRequest(cfg *config.Config, r *http.Request) (*models.User, error) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return nil, errors.New("Authorization header is required")
+	}
+
+	// Extract the token from the Authorization header
+	// Format: "Bearer {token}"
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, errors.New("Invalid authorization format")
+	}
+
+	tokenString := parts[1]
+
+	// Parse and validate the token
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(cfg.JWTSecret), nil
+	})
+
+	if err != nil {
+		return nil, errors.New("Invalid token: " + err.Error())
+	}
+
+	if !token.Valid {
+		return nil, errors.New("Invalid token")
+	}
+
+	// Extract claims
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("Invalid token claims")
+	}
+
+	// Get wallet address from claims
+	walletAddr, ok := claims["wallet"].(string)
+	if !ok {
+		return nil, errors.New("Invalid token: missing wallet address")
+	}
+
+	// Get user ID from claims if available
+	userId, ok := claims["id"].(string)
+	if !ok {
+		// If ID not in token, log a warning but continue
+		log.Printf("Warning: Token missing user ID for wallet %s", walletAddr)
+	}
+
+	// Create user with both wallet address and ID
+	return &models.User{
+		ID:            userId,
+		WalletAddress: walletAddr,
+	}, nil
+}
+
 // AdminOnly middleware restricts access to admin users
-func AdminOnly(cfg *config.Config) func(http.Handler) http.Handler {
+func AdminOnly(cfg *config.Config, check AccessCheck) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// First apply Auth middleware to get the user
-			Auth(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Auth(cfg, check)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				// Get the user from context
 				user, ok := r.Context().Value(UserContextKey).(*models.User)
 				if !ok {
@@ -148,3 +206,28 @@ func AdminOnly(cfg *config.Config) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// CuratorOnly middleware restricts access to curators and the admin
+func CuratorOnly(cfg *config.Config, check AccessCheck) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// First apply Auth middleware to get the user
+			Auth(cfg, check)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Get the user from context
+				user, ok := r.Context().Value(UserContextKey).(*models.User)
+				if !ok {
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+
+				// Check if user is a curator
+				if !cfg.IsCuratorWallet(user.WalletAddress) {
+					http.Error(w, "Forbidden: curator access required", http.StatusForbidden)
+					return
+				}
+
+				next.ServeHTTP(w, r)
+			})).ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/config"
+	"github.com/wesjorgensen/EthAppList/backend/internal/ratelimit"
+)
+
+// RateLimit middleware applies the first configured policy matching each
+// request. Authenticated requests are counted per user and anonymous ones
+// per client IP. Requests over the limit get 429 Too Many Requests. If the
+// store fails the request is let through rather than taking the API down.
+func RateLimit(cfg *config.Config, store ratelimit.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var policy *config.RateLimitPolicy
+			for i := range cfg.RateLimitPolicies {
+				if cfg.RateLimitPolicies[i].Matches(r.Method, r.URL.Path) {
+					policy = &cfg.RateLimitPolicies[i]
+					break
+				}
+			}
+			if policy == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			p := ratelimit.Policy{Name: policy.Name(), Limit: policy.Limit, Window: policy.Window}
+			res, err := store.Take(p.Name+"|"+rateLimitIdentity(cfg, r), p, time.Now())
+			if err != nil {
+				log.Printf("Rate limit check failed: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", ceilSeconds(res.Reset))
+			w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%s", p.Limit, ceilSeconds(p.Window)))
+
+			if !res.Allowed {
+				w.Header().Set("Retry-After", ceilSeconds(res.RetryAfter))
+				http.Error(w, "Too many requests, try again later", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitIdentity returns whom a request counts against: the user its token
+// names, or the client IP when it has no valid token
+func rateLimitIdentity(cfg *config.Config, r *http.Request) string {
+	if r.Header.Get("Authorization") != "" {
+		if user, err := userFromRequest(cfg, r); err == nil {
+			if user.ID != "" {
+				return "user:" + user.ID
+			}
+			return "wallet:" + strings.ToLower(user.WalletAddress)
+		}
+	}
+	return "ip:" + ClientIP(cfg, r)
+}
+
+// ClientIP returns the address of the client that made a request. When the
+// request came through a trusted proxy, X-Forwarded-For is read from the
+// right, skipping trusted proxies, so clients cannot spoof their address by
+// sending the header themselves.
+func ClientIP(cfg *config.Config, r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	remote := net.ParseIP(host)
+	if remote == nil || !cfg.IsTrustedProxy(remote) {
+		return host
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if ip == nil {
+			break
+		}
+		if !cfg.IsTrustedProxy(ip) {
+			return ip.String()
+		}
+		host = ip.String()
+	}
+
+	return host
+}
+
+// ceilSeconds formats a duration as whole seconds, rounding up
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
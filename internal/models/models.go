@@ -2,32 +2,161 @@ package models
 
 import (
 	"encoding/json"
+	"sort"
+	"strings"
 	"time"
 )
 
 // User represents a user in the system
 type User struct {
-	ID            string    `json:"id" db:"id"`
-	WalletAddress string    `json:"wallet_address" db:"wallet_address"`
-	TwitterHandle string    `json:"twitter_handle,omitempty" db:"twitter_handle"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	ID                string     `json:"id" db:"id"`
+	WalletAddress     string     `json:"wallet_address" db:"wallet_address"`
+	TwitterHandle     string     `json:"twitter_handle,omitempty" db:"twitter_handle"` // only ever a verified handle
+	TwitterVerifiedAt *time.Time `json:"twitter_verified_at,omitempty" db:"twitter_verified_at"`
+	DisplayName       string     `json:"display_name,omitempty" db:"display_name"` // falls back to the ENS name when others view the user
+	AvatarURL         string     `json:"avatar_url,omitempty" db:"avatar_url"`     // falls back to the ENS avatar when others view the user
+	Bio               string     `json:"bio,omitempty" db:"bio"`
+	BannedAt          *time.Time `json:"banned_at,omitempty" db:"banned_at"`
+	BanReason         string     `json:"ban_reason,omitempty" db:"ban_reason"`
+	BanExpiresAt      *time.Time `json:"ban_expires_at,omitempty" db:"ban_expires_at"`   // set for a suspension, nil for a permanent ban
+	ProbationUntil    *time.Time `json:"probation_until,omitempty" db:"probation_until"` // product edits are queued for review until then
+	ProbationReason   string     `json:"probation_reason,omitempty" db:"probation_reason"`
+	ENSName           string     `json:"ens_name,omitempty" db:"ens_name"` // verified primary name of the wallet
+	ENSAvatar         string     `json:"ens_avatar,omitempty" db:"ens_avatar"`
+	ENSCheckedAt      *time.Time `json:"-" db:"ens_checked_at"`
+	MergedInto        *string    `json:"merged_into,omitempty" db:"merged_into"` // the account this one was merged into
+	MergedAt          *time.Time `json:"merged_at,omitempty" db:"merged_at"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+
+	// What the public profile shows; only returned to the user themselves
+	Privacy *ProfilePrivacy `json:"privacy,omitempty" db:"-"`
+
+	// Every wallet that signs in to the account; only returned to the user themselves
+	Wallets []UserWallet `json:"wallets,omitempty" db:"-"`
 
 	// Internal metrics
 	SubmittedProducts int `json:"submitted_products,omitempty" db:"-"`
-	Upvotes           int `json:"upvotes,omitempty" db:"-"`
+	Upvotes           int `json:"upvotes,omitempty" db:"-"` // upvotes received on the user's products
+	Reputation        int `json:"reputation,omitempty" db:"-"`
+}
+
+// UserWallet is a wallet that signs in to a user's account
+type UserWallet struct {
+	Address  string    `json:"address" db:"address"`
+	Primary  bool      `json:"primary" db:"-"` // the account's own wallet, which roles follow
+	LinkedAt time.Time `json:"linked_at" db:"linked_at"`
+}
+
+// WalletLinkRequest is the message a user signs from both their primary
+// wallet and another wallet to link the other wallet to their account
+type WalletLinkRequest struct {
+	UserID    string    `json:"-" db:"user_id"`
+	Address   string    `json:"wallet_address" db:"address"`
+	Merge     bool      `json:"merge" db:"merge"` // merge the account the wallet belongs to into this one
+	Message   string    `json:"message" db:"message"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// TwitterChallenge is a code a user posts from a Twitter/X account to prove
+// they control it
+type TwitterChallenge struct {
+	UserID    string    `json:"-" db:"user_id"`
+	Handle    string    `json:"handle" db:"handle"`
+	Code      string    `json:"code" db:"code"`
+	Text      string    `json:"text" db:"-"` // suggested post containing the code
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ProfilePrivacy chooses which sections of a user's public profile others can see
+type ProfilePrivacy struct {
+	ShowSubmissions bool `json:"show_submissions" db:"show_submissions"`
+	ShowEdits       bool `json:"show_edits" db:"show_edits"`
+	ShowUpvotes     bool `json:"show_upvotes" db:"show_upvotes"`
+}
+
+// ProfileUpdate changes a user's profile. Nil fields are left as they are.
+type ProfileUpdate struct {
+	DisplayName *string        `json:"display_name"`
+	AvatarURL   *string        `json:"avatar_url"`
+	Bio         *string        `json:"bio"`
+	Privacy     *PrivacyUpdate `json:"privacy"`
+}
+
+// PrivacyUpdate changes a user's profile privacy. Nil fields are left as they are.
+type PrivacyUpdate struct {
+	ShowSubmissions *bool `json:"show_submissions"`
+	ShowEdits       *bool `json:"show_edits"`
+	ShowUpvotes     *bool `json:"show_upvotes"`
+}
+
+// UserEdit is an edit a user made to a product, for their contribution history
+type UserEdit struct {
+	ProductID      string    `json:"product_id"`
+	ProductTitle   string    `json:"product_title"`
+	ProductSlug    string    `json:"product_slug"`
+	RevisionNumber int       `json:"revision_number"`
+	EditSummary    *string   `json:"edit_summary"`
+	Reverted       bool      `json:"reverted"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// UserProfile is the public view of a user and their contributions. Sections
+// the user keeps private are left out unless the viewer may see them.
+type UserProfile struct {
+	User        *User       `json:"user"`
+	Reputation  *Reputation `json:"reputation"`
+	Submissions []*Product  `json:"submissions,omitempty"`
+	Edits       []UserEdit  `json:"edits,omitempty"`
+	Upvoted     []*Product  `json:"upvoted,omitempty"`
+	Hidden      []string    `json:"hidden,omitempty"` // sections withheld from this viewer
+}
+
+// ReputationCounts tallies the contributions a user's reputation is earned from
+type ReputationCounts struct {
+	SubmittedProducts   int `json:"submitted_products"`
+	ApprovedSubmissions int `json:"approved_submissions"`
+	AcceptedEdits       int `json:"accepted_edits"` // edits applied and not since reverted
+	RevertedEdits       int `json:"reverted_edits"` // edits reverted by someone else
+	RejectedEdits       int `json:"rejected_edits"`
+	UpvotesReceived     int `json:"upvotes_received"` // weighted, from other users, on the user's listed products
+	UsefulReports       int `json:"useful_reports"`   // reports on cases resolved with an action
+}
+
+// ReputationPrivilege is something a user may do once their reputation
+// reaches Threshold
+type ReputationPrivilege struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Threshold   int    `json:"threshold"`
+	Unlocked    bool   `json:"unlocked"`
+}
+
+// Reputation is a user's standing, earned from their contributions
+type Reputation struct {
+	UserID     string                `json:"user_id"`
+	Score      int                   `json:"score"`
+	Counts     ReputationCounts      `json:"counts"`
+	Privileges []ReputationPrivilege `json:"privileges"`
 }
 
 // Product represents a crypto product
 type Product struct {
 	ID                    string    `json:"id" db:"id"`
+	Slug                  string    `json:"slug" db:"slug"` // derived from Title; old slugs redirect
 	Title                 string    `json:"title" db:"title"`
 	ShortDesc             string    `json:"short_desc" db:"short_desc"`
 	LongDesc              string    `json:"long_desc" db:"long_desc"`
 	LogoURL               string    `json:"logo_url" db:"logo_url"`
 	MarkdownContent       string    `json:"markdown_content" db:"markdown_content"`
 	SubmitterID           string    `json:"submitter_id" db:"submitter_id"`
-	Approved              bool      `json:"approved" db:"approved"`
+	Approved              bool      `json:"approved" db:"approved"` // true while published or deprecated
+	Status                string    `json:"status" db:"status"`     // lifecycle state, see package lifecycle
+	StatusReason          string    `json:"status_reason,omitempty" db:"status_reason"`
+	SuccessorID           *string   `json:"successor_id,omitempty" db:"successor_id"` // replacement for a deprecated product
+	MergedInto            *string   `json:"merged_into,omitempty" db:"merged_into"`   // survivor this duplicate was merged into
 	IsVerified            bool      `json:"is_verified" db:"is_verified"`
 	AnalyticsList         []string  `json:"analytics_list" db:"analytics_list"`
 	SecurityScore         float64   `json:"security_score" db:"security_score"`
@@ -36,36 +165,285 @@ type Product struct {
 	VibesScore            float64   `json:"vibes_score" db:"vibes_score"`
 	CurrentRevisionNumber int       `json:"current_revision_number" db:"current_revision_number"`
 	LastEditorID          *string   `json:"last_editor_id" db:"last_editor_id"`
+	UpvoteCount           int       `json:"upvote_count" db:"upvote_count"`
+	WeightedScore         float64   `json:"weighted_score" db:"weighted_score"`
+	ReviewCount           int       `json:"review_count" db:"review_count"`   // visible reviews
+	CommentCount          int       `json:"comment_count" db:"comment_count"` // visible comments, replies included
 	CreatedAt             time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
 
 	// Relationships
-	Categories  []Category `json:"categories,omitempty" db:"-"`
-	Chains      []Chain    `json:"chains,omitempty" db:"-"`
-	UpvoteCount int        `json:"upvote_count,omitempty" db:"-"`
-	Submitter   *User      `json:"submitter,omitempty" db:"-"`
-	LastEditor  *User      `json:"last_editor,omitempty" db:"-"`
+	Categories []Category     `json:"categories,omitempty" db:"-"`
+	Chains     []Chain        `json:"chains,omitempty" db:"-"`
+	Tags       []Tag          `json:"tags,omitempty" db:"-"`
+	Links      []ExternalLink `json:"links,omitempty" db:"-"`
+	Contracts  []Contract     `json:"contracts,omitempty" db:"-"`
+	Successor  *ProductLink   `json:"successor,omitempty" db:"-"`
+
+	// Community ratings by score dimension, shown alongside the curator scores
+	CommunityScores map[string]CommunityScore `json:"community_scores,omitempty" db:"-"`
+	Submitter       *User                     `json:"submitter,omitempty" db:"-"`
+	LastEditor      *User                     `json:"last_editor,omitempty" db:"-"`
+
+	// Viewer state, only set when the request is authenticated
+	ViewerHasUpvoted *bool `json:"viewer_has_upvoted,omitempty" db:"-"`
+}
+
+// ProductLink is a minimal reference to another product
+type ProductLink struct {
+	ID    string `json:"id"`
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+}
+
+// DuplicateCandidate is an existing product a new submission may duplicate
+type DuplicateCandidate struct {
+	Product         ProductLink `json:"product"`
+	Status          string      `json:"status"`
+	TitleSimilarity float64     `json:"title_similarity"` // trigram similarity between the titles, 0 to 1
+	Reasons         []string    `json:"reasons"`          // similar_title, same_website or same_contract
+}
+
+// ExternalLink is a typed link from a product to somewhere else, such as its
+// website, docs or GitHub
+type ExternalLink struct {
+	Type  string `json:"type" db:"type"` // website, docs, github, twitter, discord, telegram, blog, whitepaper, audit or other
+	URL   string `json:"url" db:"url"`
+	Label string `json:"label,omitempty" db:"label"`
+}
+
+// Contract is a contract a product has deployed on one of its chains
+type Contract struct {
+	ChainID string `json:"chain_id" db:"chain_id"`
+	Address string `json:"address" db:"address"` // EIP-55 checksummed on EVM chains
+	Label   string `json:"label,omitempty" db:"label"`
+}
+
+// ContractMatch is a contract address resolved to the product that deployed it
+type ContractMatch struct {
+	EVMChainID    int64       `json:"evm_chain_id"`
+	ChainID       string      `json:"chain_id"`
+	Address       string      `json:"address"`
+	Label         string      `json:"label,omitempty"`
+	Product       ProductLink `json:"product"`
+	ProductStatus string      `json:"product_status"` // lets wallets warn about scam_flagged products
+	LogoURL       string      `json:"logo_url,omitempty"`
+}
+
+// ProductStatusChange records a move between lifecycle states
+type ProductStatusChange struct {
+	ID          string    `json:"id" db:"id"`
+	ProductID   string    `json:"product_id" db:"product_id"`
+	FromStatus  *string   `json:"from_status" db:"from_status"` // nil for the initial state
+	ToStatus    string    `json:"to_status" db:"to_status"`
+	Reason      string    `json:"reason,omitempty" db:"reason"`
+	ActorID     *string   `json:"actor_id,omitempty" db:"actor_id"`
+	SuccessorID *string   `json:"successor_id,omitempty" db:"successor_id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// ScoreCriterion is an assessor's rating of one rubric criterion
+type ScoreCriterion struct {
+	Criterion string   `json:"criterion"`
+	Value     *float64 `json:"value"` // 0 to 1, or null when the criterion does not apply
+	Notes     string   `json:"notes,omitempty"`
+	Evidence  []string `json:"evidence,omitempty"` // URLs backing the rating
+}
+
+// ScoreAssessment is the current assessment behind one of a product's scores
+type ScoreAssessment struct {
+	ProductID  string           `json:"product_id" db:"product_id"`
+	Dimension  string           `json:"dimension" db:"dimension"` // security, ux, decent or vibes
+	Score      float64          `json:"score" db:"score"`         // aggregate of the criteria, see package scoring
+	Criteria   []ScoreCriterion `json:"criteria" db:"criteria"`
+	Note       string           `json:"note,omitempty" db:"note"`
+	AssessorID *string          `json:"assessor_id" db:"assessor_id"`
+	Assessor   *User            `json:"assessor,omitempty" db:"-"`
+	AssessedAt time.Time        `json:"assessed_at" db:"assessed_at"`
+}
+
+// ScoreChange records one assessment of a product score
+type ScoreChange struct {
+	ID         string           `json:"id" db:"id"`
+	ProductID  string           `json:"product_id" db:"product_id"`
+	Dimension  string           `json:"dimension" db:"dimension"`
+	OldScore   float64          `json:"old_score" db:"old_score"`
+	NewScore   float64          `json:"new_score" db:"new_score"`
+	Criteria   []ScoreCriterion `json:"criteria" db:"criteria"`
+	Note       string           `json:"note,omitempty" db:"note"`
+	AssessorID *string          `json:"assessor_id" db:"assessor_id"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+}
+
+// CommunityScore aggregates users' ratings of one score dimension
+type CommunityScore struct {
+	RatingCount int     `json:"rating_count" db:"rating_count"`
+	Mean        float64 `json:"mean" db:"mean"`
+	TrimmedMean float64 `json:"trimmed_mean" db:"trimmed_mean"`
+	Score       float64 `json:"score" db:"score"` // Bayesian average, pulled towards the prior while ratings are few
+}
+
+// RatingBucket counts ratings within [Min, Max), or [Min, Max] for the last bucket
+type RatingBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// DimensionRatings describes the ratings of one score dimension of a product
+type DimensionRatings struct {
+	Dimension    string         `json:"dimension"`
+	CuratorScore float64        `json:"curator_score"`
+	Community    CommunityScore `json:"community"`
+	Distribution []RatingBucket `json:"distribution"`
+}
+
+// ProductRatings describes community ratings of a product
+type ProductRatings struct {
+	ProductID  string             `json:"product_id"`
+	Dimensions []DimensionRatings `json:"dimensions"`
+
+	// The viewer's own ratings by dimension, only set when the request is authenticated
+	ViewerRatings map[string]float64 `json:"viewer_ratings,omitempty"`
+}
+
+// Review is a user's verdict on a product. Each user may review a product once.
+type Review struct {
+	ID               string     `json:"id" db:"id"`
+	ProductID        string     `json:"product_id" db:"product_id"`
+	UserID           string     `json:"user_id" db:"user_id"`
+	Body             string     `json:"body" db:"body"`               // markdown
+	Rating           *int       `json:"rating,omitempty" db:"rating"` // 1-5 stars, optional
+	Status           string     `json:"status" db:"status"`           // "visible", "hidden" or "removed"
+	ModerationReason string     `json:"moderation_reason,omitempty" db:"moderation_reason"`
+	ModeratedBy      *string    `json:"moderated_by,omitempty" db:"moderated_by"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty" db:"moderated_at"`
+	EditedAt         *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	Author           *User      `json:"author,omitempty" db:"-"`
+}
+
+// Comment is a markdown comment on a product, either at the top of a thread
+// or in reply to another comment
+type Comment struct {
+	ID               string     `json:"id" db:"id"`
+	ProductID        string     `json:"product_id" db:"product_id"`
+	ParentID         *string    `json:"parent_id,omitempty" db:"parent_id"`
+	RootID           *string    `json:"root_id,omitempty" db:"root_id"` // top-level comment of the thread
+	Depth            int        `json:"depth" db:"depth"`
+	UserID           *string    `json:"user_id,omitempty" db:"user_id"` // cleared when the author deletes a comment that has replies
+	Body             string     `json:"body" db:"body"`                 // markdown, empty unless visible
+	Status           string     `json:"status" db:"status"`             // "visible", "hidden", "removed" or "deleted"
+	ModerationReason string     `json:"moderation_reason,omitempty" db:"moderation_reason"`
+	ModeratedBy      *string    `json:"moderated_by,omitempty" db:"moderated_by"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty" db:"moderated_at"`
+	EditedAt         *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	Author           *User      `json:"author,omitempty" db:"-"`
+	Replies          []*Comment `json:"replies,omitempty" db:"-"`
+}
+
+// Report is a user's flag on a product, revision, review, comment or user.
+// Reports against the same target are grouped into one moderation case.
+type Report struct {
+	ID         string    `json:"id" db:"id"`
+	CaseID     string    `json:"case_id" db:"case_id"`
+	ReporterID string    `json:"reporter_id" db:"reporter_id"`
+	Reason     string    `json:"reason" db:"reason"` // reason code, see service.ReportReasons
+	Details    string    `json:"details,omitempty" db:"details"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	Reporter   *User     `json:"reporter,omitempty" db:"-"`
+}
+
+// ModerationCase collects the reports against one target until a moderator
+// resolves or dismisses them
+type ModerationCase struct {
+	ID             string     `json:"id" db:"id"`
+	TargetType     string     `json:"target_type" db:"target_type"` // "product", "revision", "review", "comment" or "user"
+	TargetID       string     `json:"target_id" db:"target_id"`
+	ProductID      *string    `json:"product_id,omitempty" db:"product_id"` // product the target belongs to, unless it is a user
+	Status         string     `json:"status" db:"status"`                   // "open", "in_review", "resolved" or "dismissed"
+	AssigneeID     *string    `json:"assignee_id,omitempty" db:"assignee_id"`
+	ReportCount    int        `json:"report_count" db:"report_count"`
+	Reasons        []string   `json:"reasons" db:"-"`                       // distinct reason codes reported
+	Resolution     string     `json:"resolution,omitempty" db:"resolution"` // action taken when resolved
+	ResolutionNote string     `json:"resolution_note,omitempty" db:"resolution_note"`
+	ResolvedBy     *string    `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Relationships, only loaded for a single case
+	Assignee *User           `json:"assignee,omitempty" db:"-"`
+	Reports  []Report        `json:"reports,omitempty" db:"-"`
+	History  []AuditLogEntry `json:"history,omitempty" db:"-"`
+}
+
+// ModerationCaseFilter selects cases from the moderation queue
+type ModerationCaseFilter struct {
+	Statuses   []string `json:"statuses"`    // defaults to open and in_review
+	AssigneeID string   `json:"assignee_id"` // "none" for unassigned cases
+	TargetType string   `json:"target_type"`
+	Page       int      `json:"page"`
+	PerPage    int      `json:"per_page"`
 }
 
 // Category represents a product category
 type Category struct {
 	ID          string    `json:"id" db:"id"`
 	Name        string    `json:"name" db:"name"`
+	Slug        string    `json:"slug" db:"slug"`
 	Description string    `json:"description" db:"description"`
+	ParentID    *string   `json:"parent_id,omitempty" db:"parent_id"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 
 	// Relationships
-	ProductCount int `json:"product_count,omitempty" db:"-"`
+	ProductCount int        `json:"product_count,omitempty" db:"-"`
+	Children     []Category `json:"children,omitempty" db:"-"`
 }
 
 // Chain represents a blockchain network
 type Chain struct {
-	ID        string    `json:"id" db:"id"`
-	Name      string    `json:"name" db:"name"`
-	Icon      string    `json:"icon" db:"icon"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID             string    `json:"id" db:"id"`
+	Name           string    `json:"name" db:"name"`
+	Icon           string    `json:"icon" db:"icon"`
+	EVMChainID     *int64    `json:"evm_chain_id,omitempty" db:"evm_chain_id"` // EIP-155 chain ID, nil for non-EVM chains
+	NativeCurrency string    `json:"native_currency" db:"native_currency"`     // symbol of the gas token, e.g. "ETH"
+	ExplorerURL    string    `json:"explorer_url" db:"explorer_url"`
+	IsTestnet      bool      `json:"is_testnet" db:"is_testnet"`
+	ParentChainID  *string   `json:"parent_chain_id,omitempty" db:"parent_chain_id"` // L1 a rollup settles to
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+
+	// Relationships
+	ProductCount int `json:"product_count,omitempty" db:"-"`
+}
+
+// Tag is a free-form product label, finer grained than a category
+type Tag struct {
+	ID          string    `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Slug        string    `json:"slug" db:"slug"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+
+	// Alternate slugs curators have mapped onto this tag
+	Aliases []string `json:"aliases,omitempty" db:"-"`
+
+	// Relationships
+	ProductCount int `json:"product_count,omitempty" db:"-"`
+}
+
+// TagList renders tags as a sorted, comma-separated list of slugs for diffs
+func TagList(tags []Tag) string {
+	slugs := make([]string, len(tags))
+	for i, tag := range tags {
+		slugs[i] = tag.Slug
+	}
+	sort.Strings(slugs)
+	return strings.Join(slugs, ", ")
 }
 
 // Upvote represents a user's upvote on a product
@@ -73,7 +451,68 @@ type Upvote struct {
 	ID        string    `json:"id" db:"id"`
 	UserID    string    `json:"user_id" db:"user_id"`
 	ProductID string    `json:"product_id" db:"product_id"`
+	Weight    float64   `json:"weight" db:"weight"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Signal scores that produced Weight, keyed by signal name
+	WeightSignals map[string]float64 `json:"weight_signals,omitempty" db:"weight_signals"`
+
+	// Relationships
+	Product *Product `json:"product,omitempty" db:"-"`
+}
+
+// UpvoteDrift describes a product whose stored upvote counter disagrees with the upvotes table
+type UpvoteDrift struct {
+	ProductID   string  `json:"product_id"`
+	Title       string  `json:"title"`
+	StoredCount int     `json:"stored_count"`
+	ActualCount int     `json:"actual_count"`
+	StoredScore float64 `json:"stored_score"`
+	ActualScore float64 `json:"actual_score"`
+}
+
+// UpvoteReconciliation reports the result of recomputing upvote counters
+type UpvoteReconciliation struct {
+	ProductsChecked int           `json:"products_checked"`
+	Drift           []UpvoteDrift `json:"drift"`
+	Repaired        bool          `json:"repaired"`
+}
+
+// VoteActivity is an upvote joined with the timing data vote analysis needs
+type VoteActivity struct {
+	UpvoteID         string    `json:"upvote_id"`
+	UserID           string    `json:"user_id"`
+	ProductID        string    `json:"product_id"`
+	VotedAt          time.Time `json:"voted_at"`
+	UserCreatedAt    time.Time `json:"user_created_at"`
+	ProductCreatedAt time.Time `json:"product_created_at"`
+}
+
+// VoteAnomaly is a suspicious voting pattern flagged for admin review
+type VoteAnomaly struct {
+	ID             string          `json:"id" db:"id"`
+	Kind           string          `json:"kind" db:"kind"`       // "burst", "new_account", "co_voting"
+	Subject        string          `json:"subject" db:"subject"` // product ID, or the sorted user IDs of a co-voting cluster
+	ProductID      *string         `json:"product_id,omitempty" db:"product_id"`
+	UpvoteIDs      []string        `json:"upvote_ids" db:"upvote_ids"`
+	UserIDs        []string        `json:"user_ids" db:"user_ids"`
+	Details        json.RawMessage `json:"details" db:"details"`
+	Status         string          `json:"status" db:"status"` // "open", "voided", "dismissed"
+	DetectedAt     time.Time       `json:"detected_at" db:"detected_at"`
+	ResolvedAt     *time.Time      `json:"resolved_at,omitempty" db:"resolved_at"`
+	ResolvedBy     *string         `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolutionNote *string         `json:"resolution_note,omitempty" db:"resolution_note"`
+}
+
+// AuditLogEntry records an administrative action
+type AuditLogEntry struct {
+	ID         string          `json:"id" db:"id"`
+	ActorID    *string         `json:"actor_id" db:"actor_id"`
+	Action     string          `json:"action" db:"action"`
+	EntityType string          `json:"entity_type" db:"entity_type"`
+	EntityID   string          `json:"entity_id" db:"entity_id"`
+	Details    json.RawMessage `json:"details,omitempty" db:"details"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
 }
 
 // PendingEdit represents a pending edit to a product or category
@@ -91,12 +530,16 @@ type PendingEdit struct {
 
 // ProductFilter holds criteria for filtering products
 type ProductFilter struct {
-	CategoryID  string `json:"category_id"`
-	ChainID     string `json:"chain_id"`
-	SearchQuery string `json:"search_query"`
-	SortBy      string `json:"sort_by"` // "new", "top_day", "top_week", "top_month", "top_year", "top_all"
-	Page        int    `json:"page"`
-	PerPage     int    `json:"per_page"`
+	CategoryID  string   `json:"category_id"`
+	ChainID     string   `json:"chain_id"`
+	Tags        []string `json:"tags"`   // tag slugs or aliases; products must carry all of them
+	Status      []string `json:"status"` // lifecycle states to include, defaults to the public ones
+	SearchQuery string   `json:"search_query"`
+	SubmitterID string   `json:"submitter_id"` // only products this user submitted
+	UpvotedBy   string   `json:"upvoted_by"`   // only products this user upvoted
+	SortBy      string   `json:"sort_by"`      // "new", "top_day", "top_week", "top_month", "top_year", "top_all"
+	Page        int      `json:"page"`
+	PerPage     int      `json:"per_page"`
 }
 
 // AppStats represents application statistics
@@ -151,4 +594,7 @@ type RevisionSummary struct {
 	CreatedAt      time.Time `json:"created_at"`
 	ChangeCount    int       `json:"change_count"`
 	MajorChange    bool      `json:"major_change"`
+
+	// Set when the revision belongs to a duplicate merged into this product
+	MergedFrom *ProductLink `json:"merged_from,omitempty"`
 }
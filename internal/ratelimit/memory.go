@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket as of updatedAt
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time
+}
+
+// MemoryStore keeps buckets in process memory. Each replica of the server
+// counts requests separately, so use PostgresStore when running several.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewMemoryStore returns an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket)}
+}
+
+// Take removes a token from the bucket for key, if it has one
+func (m *MemoryStore) Take(key string, p Policy, now time.Time) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(p.Limit), updatedAt: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = p.refill(b.tokens, now.Sub(b.updatedAt))
+	b.updatedAt = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	b.fullAt = now.Add(secondsToDuration((float64(p.Limit) - b.tokens) / p.rate()))
+
+	return NewResult(p, b.tokens, allowed), nil
+}
+
+// Sweep forgets buckets that have refilled completely
+func (m *MemoryStore) Sweep(now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, b := range m.buckets {
+		if !now.Before(b.fullAt) {
+			delete(m.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package ratelimit
+
+import "time"
+
+// BucketRepository persists token buckets in a database shared by all
+// replicas of the server
+type BucketRepository interface {
+	// TakeRateLimitToken atomically refills the bucket for key at
+	// limit/window tokens per window and removes a token if it has one,
+	// returning the tokens left and whether one was taken
+	TakeRateLimitToken(key string, limit int, window time.Duration) (float64, bool, error)
+	// DeleteFullRateLimitBuckets removes buckets that have refilled completely
+	DeleteFullRateLimitBuckets() error
+}
+
+// PostgresStore keeps buckets in Postgres so that limits hold across every
+// replica. Bucket timing uses the database clock, so replicas need not agree
+// on the time.
+type PostgresStore struct {
+	repo BucketRepository
+}
+
+// NewPostgresStore returns a store backed by repo
+func NewPostgresStore(repo BucketRepository) *PostgresStore {
+	return &PostgresStore{repo: repo}
+}
+
+// Take removes a token from the bucket for key, if it has one
+func (s *PostgresStore) Take(key string, p Policy, now time.Time) (Result, error) {
+	tokens, allowed, err := s.repo.TakeRateLimitToken(key, p.Limit, p.Window)
+	if err != nil {
+		return Result{}, err
+	}
+	return NewResult(p, tokens, allowed), nil
+}
+
+// Sweep forgets buckets that have refilled completely
+func (s *PostgresStore) Sweep(now time.Time) error {
+	return s.repo.DeleteFullRateLimitBuckets()
+}
@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"log"
+	"math"
+	"time"
+)
+
+// Policy allows Limit requests per Window. Requests draw from a token bucket
+// that holds Limit tokens and refills continuously over Window, so short
+// bursts up to Limit are allowed.
+type Policy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// rate returns how many tokens the bucket regains per second
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / p.Window.Seconds()
+}
+
+// refill returns the tokens in a bucket that held tokens elapsed ago
+func (p Policy) refill(tokens float64, elapsed time.Duration) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(p.Limit), tokens+elapsed.Seconds()*p.rate())
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next token, when not allowed
+}
+
+// NewResult describes a bucket left holding tokens after a request that was
+// or was not allowed
+func NewResult(p Policy, tokens float64, allowed bool) Result {
+	res := Result{
+		Allowed:   allowed,
+		Limit:     p.Limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(p.Limit) - tokens) / p.rate()),
+	}
+	if !allowed {
+		res.RetryAfter = secondsToDuration((1 - tokens) / p.rate())
+	}
+	return res
+}
+
+// secondsToDuration converts a non-negative number of seconds to a duration
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// Store keeps token buckets. Take must be atomic per key so that concurrent
+// requests never share a token.
+type Store interface {
+	// Take removes a token from the bucket for key, if it has one
+	Take(key string, p Policy, now time.Time) (Result, error)
+	// Sweep forgets buckets that have refilled completely
+	Sweep(now time.Time) error
+}
+
+// StartSweeper periodically sweeps idle buckets from a store so it does not
+// grow without bound. The returned function stops it.
+func StartSweeper(store Store, interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case now := <-ticker.C:
+				if err := store.Sweep(now); err != nil {
+					log.Printf("Rate limit sweep failed: %v", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// tenPerMinute regains one token every six seconds
+var tenPerMinute = Policy{Name: "test", Limit: 10, Window: time.Minute}
+
+func TestRefill(t *testing.T) {
+	tests := []struct {
+		name    string
+		tokens  float64
+		elapsed time.Duration
+		want    float64
+	}{
+		{"no time passed", 3, 0, 3},
+		{"one token per six seconds", 3, 6 * time.Second, 4},
+		{"partial tokens", 0, 3 * time.Second, 0.5},
+		{"capped at the limit", 9, time.Hour, 10},
+		{"clock going backwards adds nothing", 3, -time.Minute, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tenPerMinute.refill(tt.tokens, tt.elapsed); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("refill(%v, %v) = %v, want %v", tt.tokens, tt.elapsed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		tokens  float64
+		allowed bool
+		want    Result
+	}{
+		{
+			name:    "full bucket",
+			tokens:  10,
+			allowed: true,
+			want:    Result{Allowed: true, Limit: 10, Remaining: 10},
+		},
+		{
+			name:    "partial tokens round down",
+			tokens:  4.5,
+			allowed: true,
+			want:    Result{Allowed: true, Limit: 10, Remaining: 4, Reset: 33 * time.Second},
+		},
+		{
+			name:    "empty bucket waits for the next token",
+			tokens:  0,
+			allowed: false,
+			want:    Result{Allowed: false, Limit: 10, Remaining: 0, Reset: time.Minute, RetryAfter: 6 * time.Second},
+		},
+		{
+			name:    "partly refilled bucket waits for the rest",
+			tokens:  0.5,
+			allowed: false,
+			want:    Result{Allowed: false, Limit: 10, Remaining: 0, Reset: 57 * time.Second, RetryAfter: 3 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewResult(tenPerMinute, tt.tokens, tt.allowed); got != tt.want {
+				t.Errorf("NewResult(%v, %v) = %+v, want %+v", tt.tokens, tt.allowed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	policy := Policy{Name: "test", Limit: 3, Window: 3 * time.Second}
+	store := NewMemoryStore()
+	start := time.Now()
+
+	steps := []struct {
+		name          string
+		key           string
+		after         time.Duration
+		wantAllowed   bool
+		wantRemaining int
+	}{
+		{"first request", "a", 0, true, 2},
+		{"burst continues", "a", 0, true, 1},
+		{"burst uses the last token", "a", 0, true, 0},
+		{"bucket empty", "a", 0, false, 0},
+		{"other keys have their own bucket", "b", 0, true, 2},
+		{"refilled one token", "a", time.Second, true, 0},
+		{"refilled completely", "a", time.Minute, true, 2},
+	}
+
+	for _, step := range steps {
+		res, err := store.Take(step.key, policy, start.Add(step.after))
+		if err != nil {
+			t.Fatalf("%s: Take() error = %v", step.name, err)
+		}
+		if res.Allowed != step.wantAllowed || res.Remaining != step.wantRemaining {
+			t.Errorf("%s: Take() = allowed %v, remaining %d; want allowed %v, remaining %d",
+				step.name, res.Allowed, res.Remaining, step.wantAllowed, step.wantRemaining)
+		}
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	policy := Policy{Name: "test", Limit: 2, Window: 2 * time.Second}
+	store := NewMemoryStore()
+	start := time.Now()
+
+	store.Take("a", policy, start)
+	store.Take("b", policy, start.Add(time.Second))
+
+	store.Sweep(start.Add(1500 * time.Millisecond))
+	if len(store.buckets) != 1 {
+		t.Errorf("after sweep %d buckets left, want only the one still refilling", len(store.buckets))
+	}
+	if _, ok := store.buckets["b"]; !ok {
+		t.Error("sweep removed a bucket that is still refilling")
+	}
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// createAuditLogEntryTx records an administrative action within a transaction
+func (r *PostgresRepository) createAuditLogEntryTx(tx *sql.Tx, entry *models.AuditLogEntry) error {
+	if entry.ID == "" {
+		entry.ID = generateID()
+	}
+	entry.CreatedAt = time.Now()
+
+	_, err := tx.Exec(`
+		INSERT INTO audit_log (id, actor_id, action, entity_type, entity_id, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		entry.ID, entry.ActorID, entry.Action, entry.EntityType, entry.EntityID, nullableJSON(entry.Details), entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	return nil
+}
+
+// GetAuditLog returns the most recent audit log entries, optionally limited to one entity type
+func (r *PostgresRepository) GetAuditLog(entityType string, limit int) ([]models.AuditLogEntry, error) {
+	query := `
+		SELECT id, actor_id, action, entity_type, entity_id, details, created_at
+		FROM audit_log
+		WHERE ($1 = '' OR entity_type = $1)
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, entityType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+
+	return scanAuditLog(rows)
+}
+
+// GetEntityAuditLog returns every audit log entry about one entity, oldest first
+func (r *PostgresRepository) GetEntityAuditLog(entityType, entityID string) ([]models.AuditLogEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT id, actor_id, action, entity_type, entity_id, details, created_at
+		FROM audit_log
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY created_at
+	`, entityType, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+
+	return scanAuditLog(rows)
+}
+
+// scanAuditLog scans and closes a set of audit log rows
+func scanAuditLog(rows *sql.Rows) ([]models.AuditLogEntry, error) {
+	defer rows.Close()
+
+	entries := []models.AuditLogEntry{}
+	for rows.Next() {
+		var entry models.AuditLogEntry
+		var details []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ActorID,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&details,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit log entry: %w", err)
+		}
+		entry.Details = details
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// nullableJSON converts empty JSON to NULL for JSONB columns
+func nullableJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return data
+}
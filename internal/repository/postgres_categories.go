@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// categoryColumns lists the category columns in the order categoryFields expects.
+// Queries must alias the categories table as c.
+const categoryColumns = `c.id, c.name, c.slug, COALESCE(c.description, ''), c.parent_id, c.created_at, c.updated_at`
+
+// categoryFields returns scan destinations for categoryColumns
+func categoryFields(category *models.Category) []interface{} {
+	return []interface{}{
+		&category.ID,
+		&category.Name,
+		&category.Slug,
+		&category.Description,
+		&category.ParentID,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	}
+}
+
+// GetCategories returns all categories with the number of approved products in each
+func (r *PostgresRepository) GetCategories() ([]models.Category, error) {
+	query := `
+		SELECT ` + categoryColumns + `, COALESCE(pc.product_count, 0)
+		FROM categories c
+		LEFT JOIN (
+			SELECT pcat.category_id, COUNT(*) AS product_count
+			FROM product_categories pcat
+			JOIN products p ON p.id = pcat.product_id AND p.approved = true
+			GROUP BY pcat.category_id
+		) pc ON pc.category_id = c.id
+		ORDER BY c.name ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+	for rows.Next() {
+		var category models.Category
+		err := rows.Scan(append(categoryFields(&category), &category.ProductCount)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+// GetCategoryByID returns a category by its ID or slug
+func (r *PostgresRepository) GetCategoryByID(idOrSlug string) (*models.Category, error) {
+	query := `
+		SELECT ` + categoryColumns + `,
+		       (SELECT COUNT(*) FROM product_categories pcat
+		        JOIN products p ON p.id = pcat.product_id AND p.approved = true
+		        WHERE pcat.category_id = c.id)
+		FROM categories c
+		WHERE c.id = $1 OR c.slug = $1
+		ORDER BY c.id = $1 DESC
+		LIMIT 1
+	`
+
+	category := &models.Category{}
+	err := r.db.QueryRow(query, idOrSlug).Scan(append(categoryFields(category), &category.ProductCount)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("category not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	return category, nil
+}
+
+// CategorySlugTaken reports whether another category already uses the slug
+func (r *PostgresRepository) CategorySlugTaken(slug, excludeID string) (bool, error) {
+	var taken bool
+	err := r.db.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM categories WHERE slug = $1 AND id <> $2)",
+		slug, excludeID,
+	).Scan(&taken)
+	if err != nil {
+		return false, fmt.Errorf("failed to check category slug: %w", err)
+	}
+	return taken, nil
+}
+
+// CreateCategory creates a new category
+func (r *PostgresRepository) CreateCategory(category *models.Category) error {
+	if category.ID == "" {
+		category.ID = generateID()
+	}
+	if category.Slug == "" {
+		category.Slug = category.ID
+	}
+	category.CreatedAt = time.Now()
+	category.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO categories (id, name, slug, description, parent_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		category.ID,
+		category.Name,
+		category.Slug,
+		category.Description,
+		category.ParentID,
+		category.CreatedAt,
+		category.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCategory updates a category and records the change in the audit log
+func (r *PostgresRepository) UpdateCategory(category *models.Category, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.Exec(`
+		UPDATE categories
+		SET name = $2, slug = $3, description = $4, parent_id = $5, updated_at = NOW()
+		WHERE id = $1
+	`, category.ID, category.Name, category.Slug, category.Description, category.ParentID)
+	if err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check category update: %w", err)
+	}
+	if affected == 0 {
+		err = errors.New("category not found")
+		return err
+	}
+
+	details, _ := json.Marshal(category)
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "update_category",
+		EntityType: "category",
+		EntityID:   category.ID,
+		Details:    details,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCategory deletes a category that has no products or subcategories
+func (r *PostgresRepository) DeleteCategory(id, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var hasProducts, hasChildren bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM product_categories WHERE category_id = $1),
+		       EXISTS (SELECT 1 FROM categories WHERE parent_id = $1)
+	`, id).Scan(&hasProducts, &hasChildren)
+	if err != nil {
+		return fmt.Errorf("failed to check category usage: %w", err)
+	}
+	if hasProducts {
+		err = errors.New("category has products; merge it into another category instead")
+		return err
+	}
+	if hasChildren {
+		err = errors.New("category has subcategories")
+		return err
+	}
+
+	result, err := tx.Exec("DELETE FROM categories WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check category delete: %w", err)
+	}
+	if affected == 0 {
+		err = errors.New("category not found")
+		return err
+	}
+
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "delete_category",
+		EntityType: "category",
+		EntityID:   id,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// MergeCategories moves every product and subcategory of source into target
+// and deletes source. It returns the number of products re-pointed.
+func (r *PostgresRepository) MergeCategories(sourceID, targetID, actorID string) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Re-point product links, skipping products already in the target
+	result, err := tx.Exec(`
+		INSERT INTO product_categories (product_id, category_id)
+		SELECT product_id, $2 FROM product_categories WHERE category_id = $1
+		ON CONFLICT DO NOTHING
+	`, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move products: %w", err)
+	}
+
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check moved products: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM product_categories WHERE category_id = $1", sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to unlink merged category: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE categories SET parent_id = $2 WHERE parent_id = $1", sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move subcategories: %w", err)
+	}
+
+	result, err = tx.Exec("DELETE FROM categories WHERE id = $1", sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete merged category: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check merged category delete: %w", err)
+	}
+	if affected == 0 {
+		err = errors.New("category not found")
+		return 0, err
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"merged_into":    targetID,
+		"products_moved": moved,
+	})
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "merge_category",
+		EntityType: "category",
+		EntityID:   sourceID,
+		Details:    details,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return int(moved), nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// chainColumns lists the chain columns in the order chainFields expects.
+// Queries must alias the chains table as c.
+const chainColumns = `c.id, c.name, COALESCE(c.icon, ''), c.evm_chain_id, COALESCE(c.native_currency, ''),
+	COALESCE(c.explorer_url, ''), c.is_testnet, c.parent_chain_id, c.created_at, c.updated_at`
+
+// chainFields returns scan destinations for chainColumns
+func chainFields(chain *models.Chain) []interface{} {
+	return []interface{}{
+		&chain.ID,
+		&chain.Name,
+		&chain.Icon,
+		&chain.EVMChainID,
+		&chain.NativeCurrency,
+		&chain.ExplorerURL,
+		&chain.IsTestnet,
+		&chain.ParentChainID,
+		&chain.CreatedAt,
+		&chain.UpdatedAt,
+	}
+}
+
+// GetChains returns all chains with the number of approved products on each
+func (r *PostgresRepository) GetChains() ([]models.Chain, error) {
+	query := `
+		SELECT ` + chainColumns + `, COALESCE(pc.product_count, 0)
+		FROM chains c
+		LEFT JOIN (
+			SELECT pch.chain_id, COUNT(*) AS product_count
+			FROM product_chains pch
+			JOIN products p ON p.id = pch.product_id AND p.approved = true
+			GROUP BY pch.chain_id
+		) pc ON pc.chain_id = c.id
+		ORDER BY c.is_testnet, c.name
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chains: %w", err)
+	}
+	defer rows.Close()
+
+	chains := []models.Chain{}
+	for rows.Next() {
+		var chain models.Chain
+		err := rows.Scan(append(chainFields(&chain), &chain.ProductCount)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chain: %w", err)
+		}
+		chains = append(chains, chain)
+	}
+
+	return chains, rows.Err()
+}
+
+// GetChainByID returns a single chain with its approved product count
+func (r *PostgresRepository) GetChainByID(id string) (*models.Chain, error) {
+	query := `
+		SELECT ` + chainColumns + `,
+		       (SELECT COUNT(*) FROM product_chains pch
+		        JOIN products p ON p.id = pch.product_id AND p.approved = true
+		        WHERE pch.chain_id = c.id)
+		FROM chains c
+		WHERE c.id = $1
+	`
+
+	chain := &models.Chain{}
+	err := r.db.QueryRow(query, id).Scan(append(chainFields(chain), &chain.ProductCount)...)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("chain not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain: %w", err)
+	}
+
+	return chain, nil
+}
+
+// CreateChain creates a new chain
+func (r *PostgresRepository) CreateChain(chain *models.Chain) error {
+	if chain.ID == "" {
+		chain.ID = generateID()
+	}
+	chain.CreatedAt = time.Now()
+	chain.UpdatedAt = time.Now()
+
+	_, err := r.db.Exec(`
+		INSERT INTO chains (id, name, icon, evm_chain_id, native_currency, explorer_url, is_testnet, parent_chain_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		chain.ID,
+		chain.Name,
+		chain.Icon,
+		chain.EVMChainID,
+		chain.NativeCurrency,
+		chain.ExplorerURL,
+		chain.IsTestnet,
+		chain.ParentChainID,
+		chain.CreatedAt,
+		chain.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create chain: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateChain updates an existing chain's metadata
+func (r *PostgresRepository) UpdateChain(chain *models.Chain) error {
+	result, err := r.db.Exec(`
+		UPDATE chains
+		SET name = $2, icon = $3, evm_chain_id = $4, native_currency = $5, explorer_url = $6,
+		    is_testnet = $7, parent_chain_id = $8, updated_at = NOW()
+		WHERE id = $1
+	`,
+		chain.ID,
+		chain.Name,
+		chain.Icon,
+		chain.EVMChainID,
+		chain.NativeCurrency,
+		chain.ExplorerURL,
+		chain.IsTestnet,
+		chain.ParentChainID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update chain: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check chain update: %w", err)
+	}
+	if affected == 0 {
+		return errors.New("chain not found")
+	}
+
+	return nil
+}
+
+// DeleteChain deletes a chain that no product or child chain refers to
+func (r *PostgresRepository) DeleteChain(id string) error {
+	var inUse bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM product_chains WHERE chain_id = $1)
+		    OR EXISTS (SELECT 1 FROM chains WHERE parent_chain_id = $1)
+	`, id).Scan(&inUse)
+	if err != nil {
+		return fmt.Errorf("failed to check chain usage: %w", err)
+	}
+	if inUse {
+		return errors.New("chain is in use")
+	}
+
+	result, err := r.db.Exec("DELETE FROM chains WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete chain: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check chain delete: %w", err)
+	}
+	if affected == 0 {
+		return errors.New("chain not found")
+	}
+
+	return nil
+}
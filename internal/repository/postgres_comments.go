@@ -0,0 +1,369 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+const commentColumns = `c.id, c.product_id, c.parent_id, c.root_id, c.depth, c.user_id, c.body, c.status,
+	COALESCE(c.moderation_reason, ''), c.moderated_by, c.moderated_at, c.edited_at, c.created_at,
+	` + userSummaryColumns
+
+// scanComment scans a row selected with commentColumns, joined to the author
+// as u, into a comment
+func scanComment(row rowScanner) (*models.Comment, error) {
+	comment := &models.Comment{}
+	var author userSummary
+	err := row.Scan(append([]interface{}{
+		&comment.ID,
+		&comment.ProductID,
+		&comment.ParentID,
+		&comment.RootID,
+		&comment.Depth,
+		&comment.UserID,
+		&comment.Body,
+		&comment.Status,
+		&comment.ModerationReason,
+		&comment.ModeratedBy,
+		&comment.ModeratedAt,
+		&comment.EditedAt,
+		&comment.CreatedAt,
+	}, author.dest()...)...)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != nil {
+		comment.Author = author.user(*comment.UserID)
+	}
+	return comment, nil
+}
+
+// CreateComment saves a comment. A reply takes its thread and depth from
+// its parent, which must belong to the same product.
+func (r *PostgresRepository) CreateComment(comment *models.Comment) error {
+	comment.ID = generateID()
+	comment.Status = "visible"
+	comment.CreatedAt = time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = lockProductTx(tx, comment.ProductID); err != nil {
+		return err
+	}
+
+	comment.RootID = nil
+	comment.Depth = 0
+	if comment.ParentID != nil {
+		var parentProductID string
+		var rootID *string
+		var depth int
+		err = tx.QueryRow(
+			"SELECT product_id, root_id, depth FROM product_comments WHERE id = $1",
+			*comment.ParentID,
+		).Scan(&parentProductID, &rootID, &depth)
+		if err == sql.ErrNoRows || (err == nil && parentProductID != comment.ProductID) {
+			err = errors.New("parent comment not found")
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get parent comment: %w", err)
+		}
+
+		if rootID == nil {
+			rootID = comment.ParentID
+		}
+		comment.RootID = rootID
+		comment.Depth = depth + 1
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO product_comments (id, product_id, parent_id, root_id, depth, user_id, body, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		comment.ID,
+		comment.ProductID,
+		comment.ParentID,
+		comment.RootID,
+		comment.Depth,
+		comment.UserID,
+		comment.Body,
+		comment.Status,
+		comment.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	if err = refreshDiscussionCountsTx(tx, comment.ProductID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetComment returns a comment by ID, without its replies
+func (r *PostgresRepository) GetComment(id string) (*models.Comment, error) {
+	comment, err := scanComment(r.db.QueryRow(`
+		SELECT `+commentColumns+`
+		FROM product_comments c
+		LEFT JOIN users u ON c.user_id = u.id
+		WHERE c.id = $1
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("comment not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	return comment, nil
+}
+
+// GetProductComments returns a page of a product's threads, newest first,
+// each with all of its replies, and the total number of threads. Comments
+// that are not visible keep their place in a thread while they have replies
+// but lose their text, unless they are hidden and includeHidden is set.
+func (r *PostgresRepository) GetProductComments(productID string, includeHidden bool, page, perPage int) ([]*models.Comment, int, error) {
+	offset := (page - 1) * perPage
+
+	// A thread is listed while its first comment is visible or anyone replied to it
+	threadFilter := `c.product_id = $1 AND c.parent_id IS NULL
+		AND (c.status = 'visible' OR ($2 AND c.status = 'hidden')
+			OR EXISTS (SELECT 1 FROM product_comments r WHERE r.root_id = c.id))`
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM product_comments c WHERE "+threadFilter, productID, includeHidden).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get thread count: %w", err)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+commentColumns+`
+		FROM product_comments c
+		LEFT JOIN users u ON c.user_id = u.id
+		WHERE `+threadFilter+`
+		ORDER BY c.created_at DESC, c.id
+		LIMIT $3 OFFSET $4
+	`, productID, includeHidden, perPage, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get comments: %w", err)
+	}
+	roots, err := scanComments(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rootIDs := make([]string, len(roots))
+	byID := make(map[string]*models.Comment, len(roots))
+	for i, root := range roots {
+		rootIDs[i] = root.ID
+		byID[root.ID] = root
+	}
+
+	rows, err = r.db.Query(`
+		SELECT `+commentColumns+`
+		FROM product_comments c
+		LEFT JOIN users u ON c.user_id = u.id
+		WHERE c.root_id = ANY($1)
+		ORDER BY c.depth, c.created_at, c.id
+	`, pq.Array(rootIDs))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get replies: %w", err)
+	}
+	replies, err := scanComments(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Replies are ordered by depth, so every parent is placed before its replies
+	for _, reply := range replies {
+		byID[reply.ID] = reply
+		if parent, ok := byID[*reply.ParentID]; ok {
+			parent.Replies = append(parent.Replies, reply)
+		}
+	}
+
+	threads := []*models.Comment{}
+	for _, root := range roots {
+		if pruneComment(root, includeHidden) {
+			threads = append(threads, root)
+		}
+	}
+
+	return threads, total, nil
+}
+
+// scanComments scans and closes a set of comment rows
+func scanComments(rows *sql.Rows) ([]*models.Comment, error) {
+	defer rows.Close()
+
+	comments := []*models.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// pruneComment drops the replies of a comment that nobody may see and
+// clears the text and author of comments that stay only as placeholders. It
+// reports whether the comment itself should be shown.
+func pruneComment(comment *models.Comment, includeHidden bool) bool {
+	replies := comment.Replies[:0]
+	for _, reply := range comment.Replies {
+		if pruneComment(reply, includeHidden) {
+			replies = append(replies, reply)
+		}
+	}
+	comment.Replies = replies
+
+	if comment.Status == "visible" || (includeHidden && comment.Status == "hidden") {
+		return true
+	}
+
+	comment.Body = ""
+	comment.UserID = nil
+	comment.Author = nil
+	return len(comment.Replies) > 0
+}
+
+// UpdateComment replaces the text of a comment
+func (r *PostgresRepository) UpdateComment(id, body string) error {
+	result, err := r.db.Exec("UPDATE product_comments SET body = $2, edited_at = $3 WHERE id = $1", id, body, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("comment not found")
+	}
+	return nil
+}
+
+// DeleteComment deletes a comment. A comment with replies is kept as a
+// placeholder without text or author so its thread stays intact.
+func (r *PostgresRepository) DeleteComment(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var productID string
+	var hasReplies bool
+	err = tx.QueryRow(`
+		SELECT product_id, EXISTS (SELECT 1 FROM product_comments WHERE parent_id = $1)
+		FROM product_comments WHERE id = $1
+		FOR UPDATE
+	`, id).Scan(&productID, &hasReplies)
+	if err == sql.ErrNoRows {
+		err = errors.New("comment not found")
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	if hasReplies {
+		_, err = tx.Exec("UPDATE product_comments SET status = 'deleted', body = '', user_id = NULL WHERE id = $1", id)
+	} else {
+		_, err = tx.Exec("DELETE FROM product_comments WHERE id = $1", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	if err = refreshDiscussionCountsTx(tx, productID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ModerateComment moves a comment to another moderation status, recounts its
+// product's comments and records the action in the audit log. Removing a
+// comment also discards its text.
+func (r *PostgresRepository) ModerateComment(id, status, reason, actorID, action string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var productID string
+	err = tx.QueryRow(`
+		UPDATE product_comments SET
+			status = $2,
+			body = CASE WHEN $2 = 'removed' THEN '' ELSE body END,
+			moderation_reason = NULLIF($3, ''),
+			moderated_by = $4,
+			moderated_at = $5
+		WHERE id = $1
+		RETURNING product_id
+	`, id, status, reason, actorID, time.Now()).Scan(&productID)
+	if err == sql.ErrNoRows {
+		err = errors.New("comment not found")
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to moderate comment: %w", err)
+	}
+
+	if err = refreshDiscussionCountsTx(tx, productID); err != nil {
+		return err
+	}
+
+	details, _ := json.Marshal(map[string]string{"product_id": productID, "reason": reason})
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     action + "_comment",
+		EntityType: "comment",
+		EntityID:   id,
+		Details:    details,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,446 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+const caseColumns = `mc.id, mc.target_type, mc.target_id, mc.product_id, mc.status, mc.assignee_id, mc.report_count,
+	COALESCE(mc.resolution, ''), COALESCE(mc.resolution_note, ''), mc.resolved_by, mc.resolved_at, mc.created_at, mc.updated_at,
+	ARRAY(SELECT DISTINCT rp.reason FROM reports rp WHERE rp.case_id = mc.id ORDER BY rp.reason)`
+
+// scanCase scans a row selected with caseColumns into a moderation case
+func scanCase(row rowScanner) (*models.ModerationCase, error) {
+	c := &models.ModerationCase{}
+	err := row.Scan(
+		&c.ID,
+		&c.TargetType,
+		&c.TargetID,
+		&c.ProductID,
+		&c.Status,
+		&c.AssigneeID,
+		&c.ReportCount,
+		&c.Resolution,
+		&c.ResolutionNote,
+		&c.ResolvedBy,
+		&c.ResolvedAt,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+		pq.Array(&c.Reasons),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// lockCaseTx locks a moderation case for update within a transaction and
+// returns its status
+func lockCaseTx(tx *sql.Tx, caseID string) (string, error) {
+	var status string
+	err := tx.QueryRow("SELECT status FROM moderation_cases WHERE id = $1 FOR UPDATE", caseID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", errors.New("case not found")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to lock case: %w", err)
+	}
+	return status, nil
+}
+
+// CreateReport files a report, adding it to the target's open case or
+// opening a new one. It returns the case the report joined.
+func (r *PostgresRepository) CreateReport(report *models.Report, targetType, targetID string, productID *string) (string, error) {
+	report.ID = generateID()
+	report.CreatedAt = time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Concurrent reports against the same target meet on the partial unique index
+	err = tx.QueryRow(`
+		INSERT INTO moderation_cases (id, target_type, target_id, product_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 'open', $5, $5)
+		ON CONFLICT (target_type, target_id) WHERE status IN ('open', 'in_review')
+		DO UPDATE SET updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`, generateID(), targetType, targetID, productID, report.CreatedAt).Scan(&report.CaseID)
+	if err != nil {
+		return "", fmt.Errorf("failed to open case: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO reports (id, case_id, reporter_id, reason, details, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+	`, report.ID, report.CaseID, report.ReporterID, report.Reason, report.Details, report.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		err = errors.New("you have already reported this")
+		return "", err
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create report: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE moderation_cases SET report_count = report_count + 1 WHERE id = $1", report.CaseID)
+	if err != nil {
+		return "", fmt.Errorf("failed to count report: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return report.CaseID, nil
+}
+
+// GetModerationCases returns a page of the moderation queue, oldest first,
+// and the total number of matching cases
+func (r *PostgresRepository) GetModerationCases(filter models.ModerationCaseFilter) ([]models.ModerationCase, int, error) {
+	conditions := []string{"mc.status = ANY($1)"}
+	args := []interface{}{pq.Array(filter.Statuses)}
+
+	switch filter.AssigneeID {
+	case "":
+	case "none":
+		conditions = append(conditions, "mc.assignee_id IS NULL")
+	default:
+		args = append(args, filter.AssigneeID)
+		conditions = append(conditions, fmt.Sprintf("mc.assignee_id = $%d", len(args)))
+	}
+	if filter.TargetType != "" {
+		args = append(args, filter.TargetType)
+		conditions = append(conditions, fmt.Sprintf("mc.target_type = $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM moderation_cases mc WHERE "+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count cases: %w", err)
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT `+caseColumns+`
+		FROM moderation_cases mc
+		WHERE %s
+		ORDER BY mc.created_at, mc.id
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get cases: %w", err)
+	}
+	defer rows.Close()
+
+	cases := []models.ModerationCase{}
+	for rows.Next() {
+		c, err := scanCase(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan case: %w", err)
+		}
+		cases = append(cases, *c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate cases: %w", err)
+	}
+
+	return cases, total, nil
+}
+
+// GetModerationCase returns a case with its reports, oldest first
+func (r *PostgresRepository) GetModerationCase(id string) (*models.ModerationCase, error) {
+	c, err := scanCase(r.db.QueryRow("SELECT "+caseColumns+" FROM moderation_cases mc WHERE mc.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("case not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get case: %w", err)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT rp.id, rp.case_id, rp.reporter_id, rp.reason, COALESCE(rp.details, ''), rp.created_at,
+			`+userSummaryColumns+`
+		FROM reports rp
+		LEFT JOIN users u ON rp.reporter_id = u.id
+		WHERE rp.case_id = $1
+		ORDER BY rp.created_at, rp.id
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reports: %w", err)
+	}
+	defer rows.Close()
+
+	c.Reports = []models.Report{}
+	for rows.Next() {
+		var report models.Report
+		var reporter userSummary
+		err := rows.Scan(append([]interface{}{
+			&report.ID,
+			&report.CaseID,
+			&report.ReporterID,
+			&report.Reason,
+			&report.Details,
+			&report.CreatedAt,
+		}, reporter.dest()...)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+		report.Reporter = reporter.user(report.ReporterID)
+		c.Reports = append(c.Reports, report)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reports: %w", err)
+	}
+
+	return c, nil
+}
+
+// AssignModerationCase hands an open case to a moderator, or back to the
+// queue when assigneeID is nil. Assigned cases are in review.
+func (r *PostgresRepository) AssignModerationCase(caseID string, assigneeID *string, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	status, err := lockCaseTx(tx, caseID)
+	if err != nil {
+		return err
+	}
+	if status != "open" && status != "in_review" {
+		err = errors.New("case is already closed")
+		return err
+	}
+
+	status = "open"
+	if assigneeID != nil {
+		status = "in_review"
+	}
+	_, err = tx.Exec(
+		"UPDATE moderation_cases SET assignee_id = $2, status = $3, updated_at = $4 WHERE id = $1",
+		caseID, assigneeID, status, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to assign case: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{"assignee_id": assigneeID})
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "assign_case",
+		EntityType: "moderation_case",
+		EntityID:   caseID,
+		Details:    details,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// caseClaimTimeout is how long a claim on a case holds before another
+// moderator may take over a resolve that never finished
+const caseClaimTimeout = 5 * time.Minute
+
+// ClaimModerationCase marks an open case as being resolved. Only one claim
+// holds at a time, so the action a case is resolved with is applied once.
+func (r *PostgresRepository) ClaimModerationCase(caseID string) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		UPDATE moderation_cases SET resolving_at = $2
+		WHERE id = $1 AND status IN ('open', 'in_review')
+			AND (resolving_at IS NULL OR resolving_at < $3)
+	`, caseID, now, now.Add(-caseClaimTimeout))
+	if err != nil {
+		return fmt.Errorf("failed to claim case: %w", err)
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check case claim: %w", err)
+	}
+	if claimed > 0 {
+		return nil
+	}
+
+	var status string
+	err = r.db.QueryRow("SELECT status FROM moderation_cases WHERE id = $1", caseID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return errors.New("case not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get case: %w", err)
+	}
+	if status != "open" && status != "in_review" {
+		return errors.New("case is already closed")
+	}
+	return errors.New("case is already being resolved")
+}
+
+// ReleaseModerationCase drops the claim on a case whose resolve failed
+func (r *PostgresRepository) ReleaseModerationCase(caseID string) error {
+	_, err := r.db.Exec("UPDATE moderation_cases SET resolving_at = NULL WHERE id = $1", caseID)
+	if err != nil {
+		return fmt.Errorf("failed to release case: %w", err)
+	}
+	return nil
+}
+
+// CloseModerationCase marks a case resolved with the action taken, or
+// dismissed, and records the decision in the audit log
+func (r *PostgresRepository) CloseModerationCase(caseID, status, resolution, note, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	current, err := lockCaseTx(tx, caseID)
+	if err != nil {
+		return err
+	}
+	if current != "open" && current != "in_review" {
+		err = errors.New("case is already closed")
+		return err
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(`
+		UPDATE moderation_cases
+		SET status = $2, resolution = $3, resolution_note = NULLIF($4, ''), resolved_by = $5, resolved_at = $6, updated_at = $6,
+			resolving_at = NULL
+		WHERE id = $1
+	`, caseID, status, resolution, note, actorID, now)
+	if err != nil {
+		return fmt.Errorf("failed to close case: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]string{"status": status, "resolution": resolution, "note": note})
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "close_case",
+		EntityType: "moderation_case",
+		EntityID:   caseID,
+		Details:    details,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ReopenModerationCase returns a closed case to the queue. Actions taken when
+// it was resolved are not undone. It fails if the target already has another
+// open case.
+func (r *PostgresRepository) ReopenModerationCase(caseID, note, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	status, err := lockCaseTx(tx, caseID)
+	if err != nil {
+		return err
+	}
+	if status == "open" || status == "in_review" {
+		err = errors.New("case is not closed")
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE moderation_cases
+		SET status = 'open', assignee_id = NULL, resolution = NULL, resolution_note = NULL,
+			resolved_by = NULL, resolved_at = NULL, updated_at = $2
+		WHERE id = $1
+	`, caseID, time.Now())
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		err = errors.New("another case about this target is already open")
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reopen case: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]string{"previous_status": status, "note": note})
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "reopen_case",
+		EntityType: "moderation_case",
+		EntityID:   caseID,
+		Details:    details,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevisionByID returns a product revision by its ID, without its product
+// data or field changes
+func (r *PostgresRepository) GetRevisionByID(id string) (*models.ProductRevision, error) {
+	revision := &models.ProductRevision{}
+	err := r.db.QueryRow(`
+		SELECT id, product_id, revision_number, editor_id, edit_summary, created_at
+		FROM product_revisions
+		WHERE id = $1
+	`, id).Scan(
+		&revision.ID,
+		&revision.ProductID,
+		&revision.RevisionNumber,
+		&revision.EditorID,
+		&revision.EditSummary,
+		&revision.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("revision not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+	return revision, nil
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// setProductLinksTx replaces a product's external links within a transaction
+func setProductLinksTx(tx *sql.Tx, productID string, links []models.ExternalLink) error {
+	_, err := tx.Exec("DELETE FROM product_links WHERE product_id = $1", productID)
+	if err != nil {
+		return fmt.Errorf("failed to clear product links: %w", err)
+	}
+
+	for i, link := range links {
+		_, err = tx.Exec(`
+			INSERT INTO product_links (product_id, position, type, url, label)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		`, productID, i, link.Type, link.URL, link.Label)
+		if err != nil {
+			return fmt.Errorf("failed to insert product link: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// setProductContractsTx replaces a product's contract addresses within a
+// transaction, linking the product to any chain it gains a contract on
+func setProductContractsTx(tx *sql.Tx, productID string, contracts []models.Contract) error {
+	_, err := tx.Exec("DELETE FROM product_contracts WHERE product_id = $1", productID)
+	if err != nil {
+		return fmt.Errorf("failed to clear product contracts: %w", err)
+	}
+
+	for _, contract := range contracts {
+		_, err = tx.Exec(
+			"INSERT INTO product_chains (product_id, chain_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			productID, contract.ChainID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to link product to chain: %w", err)
+		}
+
+		// A delisted product does not keep a contract from a product that
+		// claims it, so a rejected submission cannot block the real one
+		_, err = tx.Exec(`
+			DELETE FROM product_contracts pc
+			USING products p
+			WHERE p.id = pc.product_id AND p.status = 'delisted' AND pc.product_id <> $1
+				AND pc.chain_id = $2 AND LOWER(pc.address) = LOWER($3)
+		`, productID, contract.ChainID, contract.Address)
+		if err != nil {
+			return fmt.Errorf("failed to release contract from delisted product: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO product_contracts (product_id, chain_id, address, label)
+			VALUES ($1, $2, $3, NULLIF($4, ''))
+		`, productID, contract.ChainID, contract.Address, contract.Label)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return fmt.Errorf("contract %s is already listed under another product", contract.Address)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to insert product contract: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// loadProductLinks populates the external links of a set of products
+func (r *PostgresRepository) loadProductLinks(q queryer, ids []string, byID map[string]*models.Product) error {
+	query := `
+		SELECT product_id, type, url, COALESCE(label, '')
+		FROM product_links
+		WHERE product_id = ANY($1)
+		ORDER BY product_id, position
+	`
+
+	rows, err := q.Query(query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get product links: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID string
+		var link models.ExternalLink
+		if err := rows.Scan(&productID, &link.Type, &link.URL, &link.Label); err != nil {
+			return fmt.Errorf("failed to scan product link: %w", err)
+		}
+		if product, ok := byID[productID]; ok {
+			product.Links = append(product.Links, link)
+		}
+	}
+
+	return rows.Err()
+}
+
+// loadProductContracts populates the contract addresses of a set of products
+func (r *PostgresRepository) loadProductContracts(q queryer, ids []string, byID map[string]*models.Product) error {
+	query := `
+		SELECT product_id, chain_id, address, COALESCE(label, '')
+		FROM product_contracts
+		WHERE product_id = ANY($1)
+		ORDER BY product_id, chain_id, address
+	`
+
+	rows, err := q.Query(query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get product contracts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID string
+		var contract models.Contract
+		if err := rows.Scan(&productID, &contract.ChainID, &contract.Address, &contract.Label); err != nil {
+			return fmt.Errorf("failed to scan product contract: %w", err)
+		}
+		if product, ok := byID[productID]; ok {
+			product.Contracts = append(product.Contracts, contract)
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetProductByContract returns the product that deployed address on the
+// given chain. Addresses are matched case-insensitively.
+func (r *PostgresRepository) GetProductByContract(chainID, address string) (*models.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products p
+		JOIN product_contracts pc ON pc.product_id = p.id
+		WHERE pc.chain_id = $1 AND LOWER(pc.address) = LOWER($2)
+	`
+
+	product, err := scanProduct(r.db.QueryRow(query, chainID, address))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("product not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product by contract: %w", err)
+	}
+
+	if err = r.loadProductRelations(r.db, []*models.Product{product}); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// GetContractMatches returns every contract on an EVM chain belonging to a
+// product in one of the given states, for building the lookup index
+func (r *PostgresRepository) GetContractMatches(statuses []string) ([]models.ContractMatch, error) {
+	query := `
+		SELECT c.evm_chain_id, pc.chain_id, pc.address, COALESCE(pc.label, ''),
+		       p.id, p.slug, p.title, p.status, COALESCE(p.logo_url, '')
+		FROM product_contracts pc
+		JOIN chains c ON c.id = pc.chain_id AND c.evm_chain_id IS NOT NULL
+		JOIN products p ON p.id = pc.product_id
+		WHERE p.status = ANY($1)
+	`
+
+	rows, err := r.db.Query(query, pq.Array(statuses))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract matches: %w", err)
+	}
+	defer rows.Close()
+
+	matches := []models.ContractMatch{}
+	for rows.Next() {
+		var match models.ContractMatch
+		err := rows.Scan(
+			&match.EVMChainID,
+			&match.ChainID,
+			&match.Address,
+			&match.Label,
+			&match.Product.ID,
+			&match.Product.Slug,
+			&match.Product.Title,
+			&match.ProductStatus,
+			&match.LogoURL,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan contract match: %w", err)
+		}
+		matches = append(matches, match)
+	}
+
+	return matches, rows.Err()
+}
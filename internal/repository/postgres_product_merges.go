@@ -0,0 +1,373 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// FindDuplicateProducts returns existing products that look like the same
+// product as a submission: a title at least minSimilarity alike by trigram
+// similarity, a website on one of the given hosts, or one of the given
+// contracts, keyed as "chainID:lowercased address". Only products in one of
+// the given states are considered, and merged products are ignored. The
+// strongest matches come first.
+func (r *PostgresRepository) FindDuplicateProducts(title string, hosts, contractKeys, statuses []string, minSimilarity float64, limit int) ([]models.DuplicateCandidate, error) {
+	rows, err := r.db.Query(`
+		SELECT id, slug, title, status, title_similarity, same_website, same_contract
+		FROM (
+			SELECT p.id, p.slug, p.title, p.status, p.created_at,
+				similarity(LOWER(p.title), LOWER($1)) AS title_similarity,
+				EXISTS (
+					SELECT 1 FROM product_links pl
+					WHERE pl.product_id = p.id AND pl.type = 'website' AND product_link_host(pl.url) = ANY($2)
+				) AS same_website,
+				EXISTS (
+					SELECT 1 FROM product_contracts pc
+					WHERE pc.product_id = p.id AND pc.chain_id || ':' || LOWER(pc.address) = ANY($3)
+				) AS same_contract
+			FROM products p
+			WHERE p.merged_into IS NULL AND p.status = ANY($4)
+		) candidates
+		WHERE title_similarity >= $5 OR same_website OR same_contract
+		ORDER BY same_contract DESC, same_website DESC, title_similarity DESC, created_at
+		LIMIT $6
+	`, title, pq.Array(hosts), pq.Array(contractKeys), pq.Array(statuses), minSimilarity, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate products: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []models.DuplicateCandidate{}
+	for rows.Next() {
+		var candidate models.DuplicateCandidate
+		var sameWebsite, sameContract bool
+		err := rows.Scan(
+			&candidate.Product.ID,
+			&candidate.Product.Slug,
+			&candidate.Product.Title,
+			&candidate.Status,
+			&candidate.TitleSimilarity,
+			&sameWebsite,
+			&sameContract,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate product: %w", err)
+		}
+
+		if candidate.TitleSimilarity >= minSimilarity {
+			candidate.Reasons = append(candidate.Reasons, "similar_title")
+		}
+		if sameWebsite {
+			candidate.Reasons = append(candidate.Reasons, "same_website")
+		}
+		if sameContract {
+			candidate.Reasons = append(candidate.Reasons, "same_contract")
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, rows.Err()
+}
+
+// ResolveProductID returns the ID a product ID now refers to: the survivor
+// for a merged duplicate, otherwise the ID itself
+func (r *PostgresRepository) ResolveProductID(id string) (string, error) {
+	var resolved string
+	err := r.db.QueryRow("SELECT COALESCE(merged_into, id) FROM products WHERE id = $1", id).Scan(&resolved)
+	if err == sql.ErrNoRows {
+		return "", errors.New("product not found")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve product: %w", err)
+	}
+	return resolved, nil
+}
+
+// MergeProducts folds a duplicate product into the survivor. Upvotes,
+// ratings and reviews move unless the user already upvoted, rated or
+// reviewed the survivor, comments move with their threads, and categories,
+// chains, tags, links and contracts move unless the survivor already has
+// them. The duplicate is delisted and keeps its revision history, which the
+// survivor's history then includes, and its ID redirects to the survivor.
+// Both products get a revision recording the merge. It returns the number of
+// upvotes moved.
+func (r *PostgresRepository) MergeProducts(sourceID, targetID, reason, actorID string) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock both products so concurrent edits or merges cannot interleave
+	var fromStatus string
+	err = tx.QueryRow(
+		"SELECT status FROM products WHERE id = $1 AND merged_into IS NULL FOR UPDATE",
+		sourceID,
+	).Scan(&fromStatus)
+	if err == sql.ErrNoRows {
+		err = errors.New("product not found")
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock product: %w", err)
+	}
+
+	var targetStatus string
+	err = tx.QueryRow(
+		"SELECT status FROM products WHERE id = $1 AND merged_into IS NULL FOR UPDATE",
+		targetID,
+	).Scan(&targetStatus)
+	if err == sql.ErrNoRows {
+		err = errors.New("target product not found")
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock target product: %w", err)
+	}
+
+	// Snapshot both products as they stood for the merge revisions
+	source, err := r.getProductTx(tx, sourceID)
+	if err != nil {
+		return 0, err
+	}
+	target, err := r.getProductTx(tx, targetID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Upvotes: one per user, so votes for both products collapse into one
+	result, err := tx.Exec(`
+		UPDATE upvotes u SET product_id = $2
+		WHERE u.product_id = $1
+			AND NOT EXISTS (SELECT 1 FROM upvotes t WHERE t.product_id = $2 AND t.user_id = u.user_id)
+	`, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move upvotes: %w", err)
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check moved upvotes: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM upvotes WHERE product_id = $1", sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove duplicate upvotes: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE products p
+		SET upvote_count = (SELECT COUNT(*) FROM upvotes u WHERE u.product_id = p.id),
+			weighted_score = (SELECT COALESCE(SUM(u.weight), 0) FROM upvotes u WHERE u.product_id = p.id)
+		WHERE p.id = ANY($1)
+	`, pq.Array([]string{sourceID, targetID}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to recount upvotes: %w", err)
+	}
+
+	// Ratings likewise keep one per user and dimension
+	_, err = tx.Exec(`
+		UPDATE product_ratings pr SET product_id = $2
+		WHERE pr.product_id = $1
+			AND NOT EXISTS (
+				SELECT 1 FROM product_ratings t
+				WHERE t.product_id = $2 AND t.user_id = pr.user_id AND t.dimension = pr.dimension
+			)
+	`, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move ratings: %w", err)
+	}
+	_, err = tx.Exec("DELETE FROM product_ratings WHERE product_id = $1", sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove duplicate ratings: %w", err)
+	}
+	for _, id := range []string{sourceID, targetID} {
+		if err = r.refreshCommunityScoresTx(tx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	// Reviews keep one per user; a duplicate's review by someone who also
+	// reviewed the survivor stays behind with the delisted duplicate.
+	// Comment threads all move.
+	_, err = tx.Exec(`
+		UPDATE product_reviews rv SET product_id = $2
+		WHERE rv.product_id = $1
+			AND NOT EXISTS (SELECT 1 FROM product_reviews t WHERE t.product_id = $2 AND t.user_id = rv.user_id)
+	`, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move reviews: %w", err)
+	}
+	_, err = tx.Exec("UPDATE product_comments SET product_id = $2 WHERE product_id = $1", sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move comments: %w", err)
+	}
+	for _, id := range []string{sourceID, targetID} {
+		if err = refreshDiscussionCountsTx(tx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	// Categories, chains and tags
+	for _, rel := range []struct{ table, column string }{
+		{"product_categories", "category_id"},
+		{"product_chains", "chain_id"},
+		{"product_tags", "tag_id"},
+	} {
+		_, err = tx.Exec(fmt.Sprintf(`
+			INSERT INTO %[1]s (product_id, %[2]s)
+			SELECT $2, %[2]s FROM %[1]s WHERE product_id = $1
+			ON CONFLICT DO NOTHING
+		`, rel.table, rel.column), sourceID, targetID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to move %s: %w", rel.table, err)
+		}
+	}
+
+	// Contracts follow their chains, and each contract belongs to one product
+	_, err = tx.Exec("UPDATE product_contracts SET product_id = $2 WHERE product_id = $1", sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move contracts: %w", err)
+	}
+
+	// Links the survivor lacks go after its own
+	_, err = tx.Exec(`
+		INSERT INTO product_links (product_id, position, type, url, label)
+		SELECT $2,
+			(SELECT COALESCE(MAX(t.position), -1) FROM product_links t WHERE t.product_id = $2)
+				+ ROW_NUMBER() OVER (ORDER BY s.position),
+			s.type, s.url, s.label
+		FROM product_links s
+		WHERE s.product_id = $1
+			AND NOT EXISTS (SELECT 1 FROM product_links t WHERE t.product_id = $2 AND t.url = s.url)
+	`, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move links: %w", err)
+	}
+
+	for _, table := range []string{"product_links", "product_tags", "product_chains", "product_categories"} {
+		_, err = tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE product_id = $1", table), sourceID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to clear merged %s: %w", table, err)
+		}
+	}
+
+	// Products deprecated in favour of the duplicate now point at the survivor
+	_, err = tx.Exec("UPDATE products SET successor_id = $2 WHERE successor_id = $1", sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move successor links: %w", err)
+	}
+
+	// Earlier duplicates of the duplicate redirect straight to the survivor
+	_, err = tx.Exec("UPDATE products SET merged_into = $2 WHERE merged_into = $1", sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move merge redirects: %w", err)
+	}
+
+	// Delist the duplicate; merges skip the lifecycle rules since any state may be merged
+	_, err = tx.Exec(`
+		UPDATE products
+		SET status = $3, status_reason = $4, approved = FALSE, successor_id = NULL, merged_into = $2
+		WHERE id = $1
+	`, sourceID, targetID, lifecycle.Delisted, reason)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delist merged product: %w", err)
+	}
+
+	err = insertProductStatusChangeTx(tx, &models.ProductStatusChange{
+		ProductID:  sourceID,
+		FromStatus: &fromStatus,
+		ToStatus:   lifecycle.Delisted,
+		Reason:     reason,
+		ActorID:    &actorID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Record the merge in both histories
+	summary := fmt.Sprintf("Merged %q into this product: %s", source.Title, reason)
+	if err = r.createMergeRevisionTx(tx, target, actorID, summary); err != nil {
+		return 0, err
+	}
+	summary = fmt.Sprintf("Merged into %q: %s", target.Title, reason)
+	if err = r.createMergeRevisionTx(tx, source, actorID, summary); err != nil {
+		return 0, err
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"merged_into":   targetID,
+		"upvotes_moved": moved,
+		"reason":        reason,
+	})
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "merge_product",
+		EntityType: "product",
+		EntityID:   sourceID,
+		Details:    details,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return int(moved), nil
+}
+
+// getProductTx reads a product and its relations within a transaction
+func (r *PostgresRepository) getProductTx(tx *sql.Tx, id string) (*models.Product, error) {
+	product, err := scanProduct(tx.QueryRow(`SELECT `+productColumns+` FROM products p WHERE p.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("product not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if err = r.loadProductRelations(tx, []*models.Product{product}); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// createMergeRevisionTx records a merge as the next revision of a product,
+// diffing its state within the merge transaction against before
+func (r *PostgresRepository) createMergeRevisionTx(tx *sql.Tx, before *models.Product, actorID, summary string) error {
+	after, err := r.getProductTx(tx, before.ID)
+	if err != nil {
+		return err
+	}
+
+	revision := after.CurrentRevisionNumber + 1
+	_, err = tx.Exec(
+		"UPDATE products SET current_revision_number = $2, last_editor_id = $3, updated_at = $4 WHERE id = $1",
+		before.ID, revision, actorID, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update revision number: %w", err)
+	}
+	after.CurrentRevisionNumber = revision
+	after.LastEditorID = &actorID
+
+	changes := r.calculateProductDifferences(before, after)
+	err = r.createProductRevisionTx(tx, before.ID, revision, &actorID, &summary, &changes, after)
+	if err != nil {
+		return fmt.Errorf("failed to create revision: %w", err)
+	}
+	return nil
+}
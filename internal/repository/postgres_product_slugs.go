@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/slug"
+)
+
+// maxProductSlugLength caps the title-derived part of a product slug
+const maxProductSlugLength = 60
+
+// productSlugBase derives the slug a product with this title would prefer
+func productSlugBase(title string) string {
+	base := slug.Make(title)
+	if len(base) > maxProductSlugLength {
+		base = strings.TrimRight(base[:maxProductSlugLength], "-")
+	}
+	if base == "" {
+		base = "product"
+	}
+	return base
+}
+
+// slugHasBase reports whether s is base or base followed by a numeric suffix
+func slugHasBase(s, base string) bool {
+	if s == base {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(s, base+"-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// maxProductSlugSuffix caps the numeric suffixes tried for one title
+const maxProductSlugSuffix = 1000
+
+// uniqueProductSlugTx returns the first free slug for a title, appending -2,
+// -3, ... when needed, starting from suffix from. Slugs held by another
+// product, either as its current slug or as a redirect, are taken; the
+// product's own old slugs are not. It also returns the suffix used.
+func uniqueProductSlugTx(tx *sql.Tx, productID, title string, from int) (string, int, error) {
+	base := productSlugBase(title)
+	for n := from; n <= maxProductSlugSuffix; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, n)
+		}
+
+		var taken bool
+		err := tx.QueryRow(`
+			SELECT EXISTS (SELECT 1 FROM products WHERE slug = $1 AND id <> $2)
+			    OR EXISTS (SELECT 1 FROM product_slug_redirects WHERE slug = $1 AND product_id <> $2)
+		`, candidate, productID).Scan(&taken)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to check product slug: %w", err)
+		}
+		if !taken {
+			return candidate, n, nil
+		}
+	}
+
+	return "", 0, fmt.Errorf("no free slug for %q", base)
+}
+
+// writeProductSlugTx stores a product under the first free slug for its title
+// by calling write with it. A concurrent transaction may claim the same slug
+// between the check and the write; the write is then undone to a savepoint
+// and retried with the next suffix. It returns the slug written.
+func writeProductSlugTx(tx *sql.Tx, productID, title string, write func(productSlug string) error) (string, error) {
+	for from := 1; from <= maxProductSlugSuffix; {
+		candidate, n, err := uniqueProductSlugTx(tx, productID, title, from)
+		if err != nil {
+			return "", err
+		}
+
+		if _, err := tx.Exec("SAVEPOINT product_slug"); err != nil {
+			return "", fmt.Errorf("failed to set savepoint: %w", err)
+		}
+
+		err = write(candidate)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "idx_products_slug" {
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT product_slug"); err != nil {
+				return "", fmt.Errorf("failed to roll back to savepoint: %w", err)
+			}
+			from = n + 1
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if _, err := tx.Exec("RELEASE SAVEPOINT product_slug"); err != nil {
+			return "", fmt.Errorf("failed to release savepoint: %w", err)
+		}
+		return candidate, nil
+	}
+
+	return "", fmt.Errorf("no free slug for %q", productSlugBase(title))
+}
+
+// updateProductSlugTx re-derives a product's slug after a title change. The
+// old slug is kept as a redirect so existing links keep working. It returns
+// the product's slug, which is unchanged when the title maps to the same base.
+func updateProductSlugTx(tx *sql.Tx, productID, title string) (string, error) {
+	var current string
+	err := tx.QueryRow("SELECT slug FROM products WHERE id = $1", productID).Scan(&current)
+	if err != nil {
+		return "", fmt.Errorf("failed to get product slug: %w", err)
+	}
+
+	if slugHasBase(current, productSlugBase(title)) {
+		return current, nil
+	}
+
+	newSlug, err := writeProductSlugTx(tx, productID, title, func(productSlug string) error {
+		_, err := tx.Exec("UPDATE products SET slug = $2 WHERE id = $1", productID, productSlug)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to update product slug: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO product_slug_redirects (slug, product_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (slug) DO UPDATE SET product_id = EXCLUDED.product_id, created_at = EXCLUDED.created_at
+	`, current, productID, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to record slug redirect: %w", err)
+	}
+
+	// A product returning to an earlier title reclaims its old slug
+	_, err = tx.Exec("DELETE FROM product_slug_redirects WHERE slug = $1", newSlug)
+	if err != nil {
+		return "", fmt.Errorf("failed to clear slug redirect: %w", err)
+	}
+
+	return newSlug, nil
+}
+
+// GetProductBySlug returns the product with the given slug. When the slug is
+// an old one kept as a redirect, the product is returned with redirected set,
+// and product.Slug holds the current slug.
+func (r *PostgresRepository) GetProductBySlug(productSlug string) (*models.Product, bool, error) {
+	query := `SELECT ` + productColumns + ` FROM products p WHERE p.slug = $1`
+
+	product, err := scanProduct(r.db.QueryRow(query, productSlug))
+	if err == nil && product.MergedInto != nil {
+		// A merged duplicate redirects to the product it was merged into
+		product, err = r.GetProductByID(*product.MergedInto)
+		if err != nil {
+			return nil, false, err
+		}
+		return product, true, nil
+	}
+	if err == nil {
+		if err = r.loadProductRelations(r.db, []*models.Product{product}); err != nil {
+			return nil, false, err
+		}
+		return product, false, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	var productID string
+	err = r.db.QueryRow("SELECT product_id FROM product_slug_redirects WHERE slug = $1", productSlug).Scan(&productID)
+	if err == sql.ErrNoRows {
+		return nil, false, errors.New("product not found")
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get slug redirect: %w", err)
+	}
+
+	product, err = r.GetProductByID(productID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return product, true, nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// insertProductStatusChangeTx records a lifecycle move within a transaction
+func insertProductStatusChangeTx(tx *sql.Tx, change *models.ProductStatusChange) error {
+	if change.ID == "" {
+		change.ID = generateID()
+	}
+	change.CreatedAt = time.Now()
+
+	_, err := tx.Exec(`
+		INSERT INTO product_status_changes (id, product_id, from_status, to_status, reason, actor_id, successor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+		change.ID,
+		change.ProductID,
+		change.FromStatus,
+		change.ToStatus,
+		change.Reason,
+		change.ActorID,
+		change.SuccessorID,
+		change.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+
+	return nil
+}
+
+// ChangeProductStatus moves a product to change.ToStatus and records the move.
+// The product must still be in change.FromStatus, so two curators acting at
+// once cannot both apply a transition checked against the same state.
+func (r *PostgresRepository) ChangeProductStatus(change *models.ProductStatusChange, listed bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.Exec(`
+		UPDATE products
+		SET status = $3, status_reason = NULLIF($4, ''), successor_id = $5, approved = $6
+		WHERE id = $1 AND status = $2
+	`, change.ProductID, change.FromStatus, change.ToStatus, change.Reason, change.SuccessorID, listed)
+	if err != nil {
+		return fmt.Errorf("failed to update product status: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check status update: %w", err)
+	}
+	if affected == 0 {
+		err = errors.New("product status changed concurrently, reload and try again")
+		return err
+	}
+
+	if err = insertProductStatusChangeTx(tx, change); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetProductStatusHistory returns every lifecycle move of a product, oldest first
+func (r *PostgresRepository) GetProductStatusHistory(productID string) ([]models.ProductStatusChange, error) {
+	query := `
+		SELECT id, product_id, from_status, to_status, COALESCE(reason, ''), actor_id, successor_id, created_at
+		FROM product_status_changes
+		WHERE product_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(query, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status history: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []models.ProductStatusChange{}
+	for rows.Next() {
+		var change models.ProductStatusChange
+		err := rows.Scan(
+			&change.ID,
+			&change.ProductID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.Reason,
+			&change.ActorID,
+			&change.SuccessorID,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// loadProductSuccessors links deprecated products to their successors
+func (r *PostgresRepository) loadProductSuccessors(q queryer, products []*models.Product) error {
+	var successorIDs []string
+	for _, product := range products {
+		if product.SuccessorID != nil {
+			successorIDs = append(successorIDs, *product.SuccessorID)
+		}
+	}
+	if len(successorIDs) == 0 {
+		return nil
+	}
+
+	rows, err := q.Query("SELECT id, slug, title FROM products WHERE id = ANY($1)", pq.Array(successorIDs))
+	if err != nil {
+		return fmt.Errorf("failed to get product successors: %w", err)
+	}
+	defer rows.Close()
+
+	links := make(map[string]*models.ProductLink)
+	for rows.Next() {
+		link := &models.ProductLink{}
+		if err := rows.Scan(&link.ID, &link.Slug, &link.Title); err != nil {
+			return fmt.Errorf("failed to scan product successor: %w", err)
+		}
+		links[link.ID] = link
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		if product.SuccessorID != nil {
+			product.Successor = links[*product.SuccessorID]
+		}
+	}
+
+	return nil
+}
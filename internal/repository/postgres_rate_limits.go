@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// refilledTokens is the SQL for the tokens an existing bucket holds now,
+// given its capacity ($2) and refill rate per second ($3)
+const refilledTokens = `LEAST($2::double precision, b.tokens + GREATEST(0, EXTRACT(EPOCH FROM NOW() - b.updated_at))::double precision * $3::double precision)`
+
+// TakeRateLimitToken atomically refills the bucket for key at limit tokens
+// per window and removes a token if it has one. It returns the tokens left
+// and whether one was taken. A new bucket starts full.
+func (r *PostgresRepository) TakeRateLimitToken(key string, limit int, window time.Duration) (float64, bool, error) {
+	query := strings.ReplaceAll(`
+		INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at, full_at)
+		VALUES ($1, $2::double precision - 1, TRUE, NOW(), NOW() + make_interval(secs => 1 / $3::double precision))
+		ON CONFLICT (key) DO UPDATE SET
+			tokens = REFILLED - CASE WHEN REFILLED >= 1 THEN 1 ELSE 0 END,
+			allowed = REFILLED >= 1,
+			updated_at = NOW(),
+			full_at = NOW() + make_interval(secs => ($2::double precision - REFILLED + CASE WHEN REFILLED >= 1 THEN 1 ELSE 0 END) / $3::double precision)
+		RETURNING b.tokens, b.allowed
+	`, "REFILLED", refilledTokens)
+
+	var tokens float64
+	var allowed bool
+	capacity := float64(limit)
+	err := r.db.QueryRow(query, key, capacity, capacity/window.Seconds()).Scan(&tokens, &allowed)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+
+	return tokens, allowed, nil
+}
+
+// DeleteFullRateLimitBuckets removes buckets that have refilled completely
+func (r *PostgresRepository) DeleteFullRateLimitBuckets() error {
+	if _, err := r.db.Exec(`DELETE FROM rate_limit_buckets WHERE full_at <= NOW()`); err != nil {
+		return fmt.Errorf("failed to delete rate limit buckets: %w", err)
+	}
+	return nil
+}
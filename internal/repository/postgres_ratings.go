@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/scoring"
+)
+
+// ratingBuckets is how many equal-width buckets rating distributions use
+const ratingBuckets = 10
+
+// RateProduct sets a user's ratings of a product by dimension, removing the
+// rating of any dimension given as nil, and refreshes the product's
+// community scores
+func (r *PostgresRepository) RateProduct(userID, productID string, ratings map[string]*float64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = lockProductTx(tx, productID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for dimension, value := range ratings {
+		if value == nil {
+			_, err = tx.Exec(
+				"DELETE FROM product_ratings WHERE product_id = $1 AND user_id = $2 AND dimension = $3",
+				productID, userID, dimension,
+			)
+		} else {
+			_, err = tx.Exec(`
+				INSERT INTO product_ratings (product_id, user_id, dimension, value, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $5)
+				ON CONFLICT (product_id, user_id, dimension) DO UPDATE
+				SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at
+			`, productID, userID, dimension, *value, now)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save rating: %w", err)
+		}
+	}
+
+	if err = r.refreshCommunityScoresTx(tx, productID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveProductRatings removes all of a user's ratings of a product
+func (r *PostgresRepository) RemoveProductRatings(userID, productID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = lockProductTx(tx, productID); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("DELETE FROM product_ratings WHERE product_id = $1 AND user_id = $2", productID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove ratings: %w", err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check removed ratings: %w", err)
+	}
+	if removed == 0 {
+		err = errors.New("rating not found")
+		return err
+	}
+
+	if err = r.refreshCommunityScoresTx(tx, productID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// lockProductTx locks a product row so concurrent changes to its ratings
+// rebuild the community scores one at a time
+func lockProductTx(tx *sql.Tx, productID string) error {
+	var id string
+	err := tx.QueryRow("SELECT id FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errors.New("product not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock product: %w", err)
+	}
+	return nil
+}
+
+// refreshCommunityScoresTx rebuilds a product's cached community scores from
+// its ratings within a transaction
+func (r *PostgresRepository) refreshCommunityScoresTx(tx *sql.Tx, productID string) error {
+	rows, err := tx.Query("SELECT dimension, value FROM product_ratings WHERE product_id = $1", productID)
+	if err != nil {
+		return fmt.Errorf("failed to get ratings: %w", err)
+	}
+
+	values := make(map[string][]float64)
+	for rows.Next() {
+		var dimension string
+		var value float64
+		if err := rows.Scan(&dimension, &value); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan rating: %w", err)
+		}
+		values[dimension] = append(values[dimension], value)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate ratings: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM product_community_scores WHERE product_id = $1", productID)
+	if err != nil {
+		return fmt.Errorf("failed to clear community scores: %w", err)
+	}
+
+	params := scoring.CommunityParams{
+		PriorMean:   r.cfg.RatingPriorMean,
+		PriorWeight: r.cfg.RatingPriorWeight,
+		Trim:        r.cfg.RatingTrim,
+	}
+	for dimension, dimensionValues := range values {
+		summary := scoring.Summarize(dimensionValues, params)
+		_, err = tx.Exec(`
+			INSERT INTO product_community_scores (product_id, dimension, rating_count, mean, trimmed_mean, score, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, productID, dimension, summary.Count, summary.Mean, summary.TrimmedMean, summary.Bayesian, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to save community score: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// loadProductCommunityScores populates the community scores of a set of products
+func (r *PostgresRepository) loadProductCommunityScores(q queryer, ids []string, byID map[string]*models.Product) error {
+	rows, err := q.Query(`
+		SELECT product_id, dimension, rating_count, mean, trimmed_mean, score
+		FROM product_community_scores
+		WHERE product_id = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get community scores: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID, dimension string
+		var score models.CommunityScore
+		err := rows.Scan(&productID, &dimension, &score.RatingCount, &score.Mean, &score.TrimmedMean, &score.Score)
+		if err != nil {
+			return fmt.Errorf("failed to scan community score: %w", err)
+		}
+		if product, ok := byID[productID]; ok {
+			if product.CommunityScores == nil {
+				product.CommunityScores = make(map[string]models.CommunityScore)
+			}
+			product.CommunityScores[dimension] = score
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetRatingDistributions returns, for each dimension of a product, how its
+// ratings spread over equal-width buckets between 0 and 1
+func (r *PostgresRepository) GetRatingDistributions(productID string) (map[string][]models.RatingBucket, error) {
+	distributions := make(map[string][]models.RatingBucket, len(scoring.Dimensions))
+	for _, dimension := range scoring.Dimensions {
+		buckets := make([]models.RatingBucket, ratingBuckets)
+		for i := range buckets {
+			buckets[i].Min = float64(i) / ratingBuckets
+			buckets[i].Max = float64(i+1) / ratingBuckets
+		}
+		distributions[dimension] = buckets
+	}
+
+	// A rating of exactly 1 falls in the last bucket rather than past it
+	rows, err := r.db.Query(`
+		SELECT dimension, LEAST(width_bucket(value, 0, 1, $2), $2) AS bucket, COUNT(*)
+		FROM product_ratings
+		WHERE product_id = $1
+		GROUP BY dimension, bucket
+	`, productID, ratingBuckets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating distribution: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dimension string
+		var bucket, count int
+		if err := rows.Scan(&dimension, &bucket, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan rating bucket: %w", err)
+		}
+		if buckets, ok := distributions[dimension]; ok && bucket >= 1 && bucket <= ratingBuckets {
+			buckets[bucket-1].Count = count
+		}
+	}
+
+	return distributions, rows.Err()
+}
+
+// GetUserRatings returns a user's ratings of a product by dimension
+func (r *PostgresRepository) GetUserRatings(userID, productID string) (map[string]float64, error) {
+	rows, err := r.db.Query(
+		"SELECT dimension, value FROM product_ratings WHERE product_id = $1 AND user_id = $2",
+		productID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user ratings: %w", err)
+	}
+	defer rows.Close()
+
+	ratings := make(map[string]float64)
+	for rows.Next() {
+		var dimension string
+		var value float64
+		if err := rows.Scan(&dimension, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan rating: %w", err)
+		}
+		ratings[dimension] = value
+	}
+
+	return ratings, rows.Err()
+}
@@ -8,9 +8,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/wesjorgensen/EthAppList/backend/internal/config"
 	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/scoring"
 )
 
 // PostgresRepository handles all database interactions using direct PostgreSQL connection
@@ -49,7 +51,7 @@ func (r *PostgresRepository) Close() error {
 	return r.db.Close()
 }
 
-// CreateUser creates a new user in the database
+// CreateUser creates a new user in the database, holding their wallet
 func (r *PostgresRepository) CreateUser(user *models.User) error {
 	if user.ID == "" {
 		user.ID = generateID()
@@ -57,13 +59,23 @@ func (r *PostgresRepository) CreateUser(user *models.User) error {
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
 	query := `
 		INSERT INTO users (id, wallet_address, twitter_handle, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, wallet_address, twitter_handle, created_at, updated_at
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+		RETURNING id, wallet_address, COALESCE(twitter_handle, ''), created_at, updated_at
 	`
 
-	err := r.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		user.ID,
 		user.WalletAddress,
@@ -82,37 +94,145 @@ func (r *PostgresRepository) CreateUser(user *models.User) error {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
+	_, err = tx.Exec(
+		"INSERT INTO user_wallets (address, user_id, linked_at) VALUES ($1, $2, $3)",
+		user.WalletAddress, user.ID, user.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record user wallet: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
-// GetUserByWallet gets a user by their wallet address
-func (r *PostgresRepository) GetUserByWallet(walletAddress string) (*models.User, error) {
-	query := `
-		SELECT id, wallet_address, twitter_handle, created_at, updated_at
-		FROM users
-		WHERE wallet_address = $1
-	`
-
-	user := &models.User{}
-	err := r.db.QueryRow(query, walletAddress).Scan(
+// userColumns lists the columns scanUser reads, for queries that alias users as u
+const userColumns = `u.id, u.wallet_address, COALESCE(u.twitter_handle, ''), u.twitter_verified_at,
+	COALESCE(u.display_name, ''), COALESCE(u.avatar_url, ''), COALESCE(u.bio, ''),
+	u.show_submissions, u.show_edits, u.show_upvotes,
+	u.banned_at, COALESCE(u.ban_reason, ''), u.ban_expires_at, u.probation_until, COALESCE(u.probation_reason, ''),
+	COALESCE(u.ens_name, ''), COALESCE(u.ens_avatar, ''), u.ens_checked_at,
+	u.merged_into, u.merged_at, u.created_at, u.updated_at`
+
+// scanUser scans a row selected with userColumns
+func scanUser(row rowScanner) (*models.User, error) {
+	user := &models.User{Privacy: &models.ProfilePrivacy{}}
+	err := row.Scan(
 		&user.ID,
 		&user.WalletAddress,
 		&user.TwitterHandle,
+		&user.TwitterVerifiedAt,
+		&user.DisplayName,
+		&user.AvatarURL,
+		&user.Bio,
+		&user.Privacy.ShowSubmissions,
+		&user.Privacy.ShowEdits,
+		&user.Privacy.ShowUpvotes,
+		&user.BannedAt,
+		&user.BanReason,
+		&user.BanExpiresAt,
+		&user.ProbationUntil,
+		&user.ProbationReason,
+		&user.ENSName,
+		&user.ENSAvatar,
+		&user.ENSCheckedAt,
+		&user.MergedInto,
+		&user.MergedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// userSummaryColumns lists the columns a userSummary reads, for queries that
+// show another user, such as an editor or author, joined as u. The display
+// name and avatar fall back to the user's ENS name and avatar.
+const userSummaryColumns = `u.wallet_address, u.twitter_handle,
+	COALESCE(NULLIF(u.display_name, ''), u.ens_name), COALESCE(NULLIF(u.avatar_url, ''), u.ens_avatar), u.ens_name`
 
+// userSummary receives a user selected with userSummaryColumns through a
+// LEFT JOIN, where every column may be NULL
+type userSummary struct {
+	walletAddress, twitterHandle, displayName, avatarURL, ensName sql.NullString
+}
+
+// dest returns the scan destinations for userSummaryColumns
+func (s *userSummary) dest() []interface{} {
+	return []interface{}{&s.walletAddress, &s.twitterHandle, &s.displayName, &s.avatarURL, &s.ensName}
+}
+
+// user returns the scanned user with the given ID, or nil if the join found none
+func (s *userSummary) user(id string) *models.User {
+	if !s.walletAddress.Valid {
+		return nil
+	}
+	return &models.User{
+		ID:            id,
+		WalletAddress: s.walletAddress.String,
+		TwitterHandle: s.twitterHandle.String,
+		DisplayName:   s.displayName.String,
+		AvatarURL:     s.avatarURL.String,
+		ENSName:       s.ensName.String,
+	}
+}
+
+// getUser returns the single user matching a condition on users u
+func (r *PostgresRepository) getUser(condition string, arg interface{}) (*models.User, error) {
+	user, err := scanUser(r.db.QueryRow(`SELECT `+userColumns+` FROM users u WHERE `+condition, arg))
 	if err == sql.ErrNoRows {
 		return nil, errors.New("user not found")
 	}
-
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
-
 	return user, nil
 }
 
+// walletOwner selects the ID of the account holding the wallet $1, in any letter case
+const walletOwner = `(SELECT w.user_id FROM user_wallets w WHERE LOWER(w.address) = LOWER($1))`
+
+// GetUserByWallet gets the user holding a wallet, which need not be their
+// primary wallet
+func (r *PostgresRepository) GetUserByWallet(walletAddress string) (*models.User, error) {
+	return r.getUser("u.id = "+walletOwner, walletAddress)
+}
+
+// GetUserByID gets a user by their ID. Merged accounts are returned as they
+// are, with MergedInto set.
+func (r *PostgresRepository) GetUserByID(id string) (*models.User, error) {
+	return r.getUser("u.id = $1", id)
+}
+
+// FindUser gets a user by ID, following merges to the surviving account, or
+// by any of their wallets in any letter case
+func (r *PostgresRepository) FindUser(idOrWallet string) (*models.User, error) {
+	return r.getUser(`u.id = (SELECT COALESCE(m.merged_into, m.id) FROM users m WHERE m.id = $1)
+		OR u.id = `+walletOwner, idOrWallet)
+}
+
+// CountUserContributions counts a user's approved submissions and the edits
+// they have made to existing products
+func (r *PostgresRepository) CountUserContributions(userID string) (int, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM products WHERE submitter_id = $1 AND approved = true) +
+			(SELECT COUNT(*) FROM product_revisions WHERE editor_id = $1 AND revision_number > 1)
+	`
+
+	var count int
+	if err := r.db.QueryRow(query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count user contributions: %w", err)
+	}
+
+	return count, nil
+}
+
 // CreateProduct creates a new product in the database
 func (r *PostgresRepository) CreateProduct(product *models.Product) error {
 	if product.ID == "" {
@@ -133,62 +253,74 @@ func (r *PostgresRepository) CreateProduct(product *models.Product) error {
 		}
 	}()
 
+	// Create new tags with the product, so a failed submission leaves none behind
+	product.Tags, err = ensureTagsTx(tx, product.Tags, &product.SubmitterID)
+	if err != nil {
+		return err
+	}
+
 	// Insert product
 	query := `
 		INSERT INTO products (
 			id, title, short_desc, long_desc, logo_url, markdown_content, submitter_id, 
 			approved, is_verified, analytics_list, security_score, ux_score, decent_score, vibes_score,
-			current_revision_number, last_editor_id, created_at, updated_at
+			current_revision_number, last_editor_id, created_at, updated_at, slug, status
 		)
 		VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
 		)
 		RETURNING id, title, short_desc, long_desc, logo_url, markdown_content, submitter_id, 
 			approved, is_verified, analytics_list, security_score, ux_score, decent_score, vibes_score,
 			current_revision_number, last_editor_id, created_at, updated_at
 	`
 
-	err = tx.QueryRow(
-		query,
-		product.ID,
-		product.Title,
-		product.ShortDesc,
-		product.LongDesc,
-		product.LogoURL,
-		product.MarkdownContent,
-		product.SubmitterID,
-		product.Approved,
-		product.IsVerified,
-		pq.Array(product.AnalyticsList),
-		product.SecurityScore,
-		product.UXScore,
-		product.DecentScore,
-		product.VibesScore,
-		product.CurrentRevisionNumber,
-		product.SubmitterID, // last_editor_id is initially the submitter
-		product.CreatedAt,
-		product.UpdatedAt,
-	).Scan(
-		&product.ID,
-		&product.Title,
-		&product.ShortDesc,
-		&product.LongDesc,
-		&product.LogoURL,
-		&product.MarkdownContent,
-		&product.SubmitterID,
-		&product.Approved,
-		&product.IsVerified,
-		pq.Array(&product.AnalyticsList),
-		&product.SecurityScore,
-		&product.UXScore,
-		&product.DecentScore,
-		&product.VibesScore,
-		&product.CurrentRevisionNumber,
-		&product.LastEditorID,
-		&product.CreatedAt,
-		&product.UpdatedAt,
-	)
-
+	// Derive a unique slug from the title, taking the next one if a concurrent
+	// submission claims it first
+	product.Slug, err = writeProductSlugTx(tx, product.ID, product.Title, func(productSlug string) error {
+		product.Slug = productSlug
+		return tx.QueryRow(
+			query,
+			product.ID,
+			product.Title,
+			product.ShortDesc,
+			product.LongDesc,
+			product.LogoURL,
+			product.MarkdownContent,
+			product.SubmitterID,
+			product.Approved,
+			product.IsVerified,
+			pq.Array(product.AnalyticsList),
+			product.SecurityScore,
+			product.UXScore,
+			product.DecentScore,
+			product.VibesScore,
+			product.CurrentRevisionNumber,
+			product.SubmitterID, // last_editor_id is initially the submitter
+			product.CreatedAt,
+			product.UpdatedAt,
+			product.Slug,
+			product.Status,
+		).Scan(
+			&product.ID,
+			&product.Title,
+			&product.ShortDesc,
+			&product.LongDesc,
+			&product.LogoURL,
+			&product.MarkdownContent,
+			&product.SubmitterID,
+			&product.Approved,
+			&product.IsVerified,
+			pq.Array(&product.AnalyticsList),
+			&product.SecurityScore,
+			&product.UXScore,
+			&product.DecentScore,
+			&product.VibesScore,
+			&product.CurrentRevisionNumber,
+			&product.LastEditorID,
+			&product.CreatedAt,
+			&product.UpdatedAt,
+		)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create product: %w", err)
 	}
@@ -200,6 +332,16 @@ func (r *PostgresRepository) CreateProduct(product *models.Product) error {
 		return fmt.Errorf("failed to create initial revision: %w", err)
 	}
 
+	// Record the starting lifecycle state
+	err = insertProductStatusChangeTx(tx, &models.ProductStatusChange{
+		ProductID: product.ID,
+		ToStatus:  product.Status,
+		ActorID:   &product.SubmitterID,
+	})
+	if err != nil {
+		return err
+	}
+
 	// Insert category relationships
 	if len(product.Categories) > 0 {
 		for _, category := range product.Categories {
@@ -226,6 +368,28 @@ func (r *PostgresRepository) CreateProduct(product *models.Product) error {
 		}
 	}
 
+	// Insert tag relationships
+	if len(product.Tags) > 0 {
+		err = setProductTagsTx(tx, product.ID, product.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Insert links and contract addresses
+	if len(product.Links) > 0 {
+		err = setProductLinksTx(tx, product.ID, product.Links)
+		if err != nil {
+			return err
+		}
+	}
+	if len(product.Contracts) > 0 {
+		err = setProductContractsTx(tx, product.ID, product.Contracts)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -234,38 +398,12 @@ func (r *PostgresRepository) CreateProduct(product *models.Product) error {
 	return nil
 }
 
-// GetProductByID gets a product by its ID
+// GetProductByID gets a product by its ID. The ID of a duplicate merged into
+// another product returns the surviving product.
 func (r *PostgresRepository) GetProductByID(id string) (*models.Product, error) {
-	query := `
-		SELECT id, title, short_desc, long_desc, logo_url, markdown_content, submitter_id, 
-		       approved, is_verified, analytics_list, security_score, ux_score, decent_score, vibes_score,
-		       current_revision_number, last_editor_id, created_at, updated_at
-		FROM products
-		WHERE id = $1
-	`
-
-	product := &models.Product{}
-	err := r.db.QueryRow(query, id).Scan(
-		&product.ID,
-		&product.Title,
-		&product.ShortDesc,
-		&product.LongDesc,
-		&product.LogoURL,
-		&product.MarkdownContent,
-		&product.SubmitterID,
-		&product.Approved,
-		&product.IsVerified,
-		pq.Array(&product.AnalyticsList),
-		&product.SecurityScore,
-		&product.UXScore,
-		&product.DecentScore,
-		&product.VibesScore,
-		&product.CurrentRevisionNumber,
-		&product.LastEditorID,
-		&product.CreatedAt,
-		&product.UpdatedAt,
-	)
+	query := `SELECT ` + productColumns + ` FROM products p WHERE p.id = $1`
 
+	product, err := scanProduct(r.db.QueryRow(query, id))
 	if err == sql.ErrNoRows {
 		return nil, errors.New("product not found")
 	}
@@ -274,72 +412,67 @@ func (r *PostgresRepository) GetProductByID(id string) (*models.Product, error)
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
-	// Load categories
-	if err = r.loadProductCategories(product); err != nil {
-		return nil, err
+	if product.MergedInto != nil {
+		return r.GetProductByID(*product.MergedInto)
 	}
 
-	// Load chains
-	if err = r.loadProductChains(product); err != nil {
+	// Load categories and chains
+	if err = r.loadProductRelations(r.db, []*models.Product{product}); err != nil {
 		return nil, err
 	}
 
-	// Get upvote count
-	upvoteCount, err := r.getProductUpvoteCount(product.ID)
-	if err != nil {
-		return nil, err
-	}
-	product.UpvoteCount = upvoteCount
-
 	return product, nil
 }
 
 // GetProducts gets a list of products with optional filters
-func (r *PostgresRepository) GetProducts(
-	categoryID, chainID, searchTerm, sortOption string,
-	page, perPage int,
-) ([]*models.Product, int, error) {
+func (r *PostgresRepository) GetProducts(filter models.ProductFilter) ([]*models.Product, int, error) {
 	// Base query for counting total
-	countQuery := `
-		SELECT COUNT(DISTINCT p.id) 
-		FROM products p
-	`
+	countQuery := `SELECT COUNT(*) FROM products p`
 
 	// Base query for fetching products
-	query := `
-		SELECT DISTINCT p.id, p.title, p.short_desc, p.long_desc, p.logo_url, 
-               p.markdown_content, p.submitter_id, p.approved, p.is_verified, 
-               p.analytics_list, p.security_score, p.ux_score, p.decent_score, p.vibes_score,
-               p.current_revision_number, p.last_editor_id, p.created_at, p.updated_at
-		FROM products p
-	`
+	query := `SELECT ` + productColumns + ` FROM products p`
 
 	// Build where clause and arguments
-	whereClause := "WHERE p.approved = true"
-	args := []interface{}{}
-	argIndex := 1
+	whereClause := "WHERE p.status = ANY($1)"
+	args := []interface{}{pq.Array(filter.Status)}
+	argIndex := 2
 
 	// Add category filter if provided
-	if categoryID != "" {
-		countQuery += " JOIN product_categories pc ON p.id = pc.product_id"
-		query += " JOIN product_categories pc ON p.id = pc.product_id"
-		whereClause += fmt.Sprintf(" AND pc.category_id = $%d", argIndex)
-		args = append(args, categoryID)
+	if filter.CategoryID != "" {
+		whereClause += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM product_categories pc WHERE pc.product_id = p.id AND pc.category_id = $%d)", argIndex)
+		args = append(args, filter.CategoryID)
 		argIndex++
 	}
 
 	// Add chain filter if provided
-	if chainID != "" {
-		countQuery += " JOIN product_chains pch ON p.id = pch.product_id"
-		query += " JOIN product_chains pch ON p.id = pch.product_id"
-		whereClause += fmt.Sprintf(" AND pch.chain_id = $%d", argIndex)
-		args = append(args, chainID)
+	if filter.ChainID != "" {
+		whereClause += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM product_chains pch WHERE pch.product_id = p.id AND pch.chain_id = $%d)", argIndex)
+		args = append(args, filter.ChainID)
+		argIndex++
+	}
+
+	// Add tag filters if provided; each tag may be given by ID, slug or alias
+	for _, tag := range filter.Tags {
+		whereClause += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM product_tags ptg JOIN tags t ON t.id = ptg.tag_id WHERE ptg.product_id = p.id AND "+tagMatch+")", fmt.Sprintf("$%d", argIndex))
+		args = append(args, tag)
+		argIndex++
+	}
+
+	// Add contributor filters if provided
+	if filter.SubmitterID != "" {
+		whereClause += fmt.Sprintf(" AND p.submitter_id = $%d", argIndex)
+		args = append(args, filter.SubmitterID)
+		argIndex++
+	}
+	if filter.UpvotedBy != "" {
+		whereClause += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM upvotes uv WHERE uv.product_id = p.id AND uv.user_id = $%d)", argIndex)
+		args = append(args, filter.UpvotedBy)
 		argIndex++
 	}
 
 	// Add search filter if provided
-	if searchTerm != "" {
-		searchPattern := "%" + searchTerm + "%"
+	if filter.SearchQuery != "" {
+		searchPattern := "%" + filter.SearchQuery + "%"
 		whereClause += fmt.Sprintf(" AND (p.title ILIKE $%d OR p.short_desc ILIKE $%d)", argIndex, argIndex)
 		args = append(args, searchPattern)
 		argIndex++
@@ -350,17 +483,16 @@ func (r *PostgresRepository) GetProducts(
 	query += " " + whereClause
 
 	// Add sorting
-	switch sortOption {
+	switch filter.SortBy {
 	case "new":
 		query += " ORDER BY p.created_at DESC"
-	case "top_day", "top_week", "top_month", "top_year", "top_all":
-		// For simplicity, we'll implement a basic version here
-		// In a production app, you might use window functions or more complex queries
-		query += " LEFT JOIN upvotes u ON p.id = u.product_id"
-
-		// Define time window based on sort option
+	case "top_all":
+		// All-time ranking reads the denormalized score and can use its index
+		query += " ORDER BY p.weighted_score DESC, p.created_at DESC"
+	case "top_day", "top_week", "top_month", "top_year":
+		// Windowed rankings only sum the weights of upvotes cast within the period
 		var timeWindow string
-		switch sortOption {
+		switch filter.SortBy {
 		case "top_day":
 			timeWindow = "1 day"
 		case "top_week":
@@ -369,22 +501,20 @@ func (r *PostgresRepository) GetProducts(
 			timeWindow = "1 month"
 		case "top_year":
 			timeWindow = "1 year"
-		case "top_all":
-			timeWindow = "100 years" // practically all time
-		}
-
-		if sortOption != "top_all" {
-			query += fmt.Sprintf(" WHERE u.created_at > NOW() - INTERVAL '%s'", timeWindow)
 		}
 
-		query += " GROUP BY p.id ORDER BY COUNT(u.id) DESC"
+		query += fmt.Sprintf(`
+			ORDER BY (
+				SELECT COALESCE(SUM(u.weight), 0) FROM upvotes u
+				WHERE u.product_id = p.id AND u.created_at > NOW() - INTERVAL '%s'
+			) DESC, p.created_at DESC`, timeWindow)
 	default:
 		query += " ORDER BY p.created_at DESC" // Default to newest
 	}
 
 	// Add pagination
-	offset := (page - 1) * perPage
-	query += fmt.Sprintf(" LIMIT %d OFFSET %d", perPage, offset)
+	offset := (filter.Page - 1) * filter.PerPage
+	query += fmt.Sprintf(" LIMIT %d OFFSET %d", filter.PerPage, offset)
 
 	// Get total count
 	var total int
@@ -403,239 +533,447 @@ func (r *PostgresRepository) GetProducts(
 	// Process results
 	products := []*models.Product{}
 	for rows.Next() {
-		product := &models.Product{}
-		err := rows.Scan(
-			&product.ID,
-			&product.Title,
-			&product.ShortDesc,
-			&product.LongDesc,
-			&product.LogoURL,
-			&product.MarkdownContent,
-			&product.SubmitterID,
-			&product.Approved,
-			&product.IsVerified,
-			pq.Array(&product.AnalyticsList),
-			&product.SecurityScore,
-			&product.UXScore,
-			&product.DecentScore,
-			&product.VibesScore,
-			&product.CurrentRevisionNumber,
-			&product.LastEditorID,
-			&product.CreatedAt,
-			&product.UpdatedAt,
-		)
+		product, err := scanProduct(rows)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan product: %w", err)
 		}
-
-		// Load categories and chains
-		if err = r.loadProductCategories(product); err != nil {
-			return nil, 0, err
-		}
-		if err = r.loadProductChains(product); err != nil {
-			return nil, 0, err
-		}
-
-		// Get upvote count
-		upvoteCount, err := r.getProductUpvoteCount(product.ID)
-		if err != nil {
-			return nil, 0, err
-		}
-		product.UpvoteCount = upvoteCount
-
 		products = append(products, product)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate products: %w", err)
+	}
+
+	// Load categories and chains for the whole page at once
+	if err = r.loadProductRelations(r.db, products); err != nil {
+		return nil, 0, err
+	}
 
 	return products, total, nil
 }
 
 // Helper functions
 
-func (r *PostgresRepository) loadProductCategories(product *models.Product) error {
+// productColumns lists the product columns in the order scanProduct expects.
+// Queries must alias the products table as p.
+const productColumns = `p.id, p.slug, p.title, p.short_desc, p.long_desc, p.logo_url,
+	p.markdown_content, p.submitter_id, p.approved, p.status, COALESCE(p.status_reason, ''), p.successor_id, p.merged_into, p.is_verified,
+	p.analytics_list, p.security_score, p.ux_score, p.decent_score, p.vibes_score,
+	p.current_revision_number, p.last_editor_id, p.upvote_count, p.weighted_score,
+	p.review_count, p.comment_count, p.created_at, p.updated_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx, so relations can be
+// loaded inside a transaction that has not committed yet
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// scanProduct scans a row selected with productColumns into a product
+func scanProduct(row rowScanner) (*models.Product, error) {
+	product := &models.Product{}
+	if err := row.Scan(productFields(product)...); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// productFields returns scan destinations for productColumns, for queries that
+// select additional columns alongside a product
+func productFields(product *models.Product) []interface{} {
+	return []interface{}{
+		&product.ID,
+		&product.Slug,
+		&product.Title,
+		&product.ShortDesc,
+		&product.LongDesc,
+		&product.LogoURL,
+		&product.MarkdownContent,
+		&product.SubmitterID,
+		&product.Approved,
+		&product.Status,
+		&product.StatusReason,
+		&product.SuccessorID,
+		&product.MergedInto,
+		&product.IsVerified,
+		pq.Array(&product.AnalyticsList),
+		&product.SecurityScore,
+		&product.UXScore,
+		&product.DecentScore,
+		&product.VibesScore,
+		&product.CurrentRevisionNumber,
+		&product.LastEditorID,
+		&product.UpvoteCount,
+		&product.WeightedScore,
+		&product.ReviewCount,
+		&product.CommentCount,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	}
+}
+
+// loadProductRelations populates categories, chains, tags, links, contracts,
+// community scores and successor links for a set of products. Each
+// relationship is fetched with a single ANY($1) lookup, so a page costs at
+// most seven queries no matter how many products it holds.
+func (r *PostgresRepository) loadProductRelations(q queryer, products []*models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(products))
+	byID := make(map[string]*models.Product, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+		byID[product.ID] = product
+		product.Categories = []models.Category{}
+		product.Chains = []models.Chain{}
+		product.Tags = []models.Tag{}
+		product.Links = []models.ExternalLink{}
+		product.Contracts = []models.Contract{}
+	}
+
+	if err := r.loadProductCategories(q, ids, byID); err != nil {
+		return err
+	}
+	if err := r.loadProductChains(q, ids, byID); err != nil {
+		return err
+	}
+	if err := r.loadProductTags(q, ids, byID); err != nil {
+		return err
+	}
+	if err := r.loadProductLinks(q, ids, byID); err != nil {
+		return err
+	}
+	if err := r.loadProductContracts(q, ids, byID); err != nil {
+		return err
+	}
+	if err := r.loadProductCommunityScores(q, ids, byID); err != nil {
+		return err
+	}
+	return r.loadProductSuccessors(q, products)
+}
+
+func (r *PostgresRepository) loadProductCategories(q queryer, ids []string, byID map[string]*models.Product) error {
 	query := `
-		SELECT c.id, c.name, c.description, c.created_at, c.updated_at
+		SELECT pc.product_id, ` + categoryColumns + `
 		FROM categories c
 		JOIN product_categories pc ON c.id = pc.category_id
-		WHERE pc.product_id = $1
+		WHERE pc.product_id = ANY($1)
+		ORDER BY c.name
 	`
 
-	rows, err := r.db.Query(query, product.ID)
+	rows, err := q.Query(query, pq.Array(ids))
 	if err != nil {
 		return fmt.Errorf("failed to get product categories: %w", err)
 	}
 	defer rows.Close()
 
-	categories := []models.Category{}
 	for rows.Next() {
+		var productID string
 		var category models.Category
-		err := rows.Scan(
-			&category.ID,
-			&category.Name,
-			&category.Description,
-			&category.CreatedAt,
-			&category.UpdatedAt,
-		)
+		err := rows.Scan(append([]interface{}{&productID}, categoryFields(&category)...)...)
 		if err != nil {
 			return fmt.Errorf("failed to scan category: %w", err)
 		}
-		categories = append(categories, category)
+		if product, ok := byID[productID]; ok {
+			product.Categories = append(product.Categories, category)
+		}
 	}
 
-	product.Categories = categories
-	return nil
+	return rows.Err()
 }
 
-func (r *PostgresRepository) loadProductChains(product *models.Product) error {
+func (r *PostgresRepository) loadProductChains(q queryer, ids []string, byID map[string]*models.Product) error {
 	query := `
-		SELECT c.id, c.name, c.icon, c.created_at, c.updated_at
+		SELECT pc.product_id, ` + chainColumns + `
 		FROM chains c
 		JOIN product_chains pc ON c.id = pc.chain_id
-		WHERE pc.product_id = $1
+		WHERE pc.product_id = ANY($1)
+		ORDER BY c.name
 	`
 
-	rows, err := r.db.Query(query, product.ID)
+	rows, err := q.Query(query, pq.Array(ids))
 	if err != nil {
 		return fmt.Errorf("failed to get product chains: %w", err)
 	}
 	defer rows.Close()
 
-	chains := []models.Chain{}
 	for rows.Next() {
+		var productID string
 		var chain models.Chain
-		err := rows.Scan(
-			&chain.ID,
-			&chain.Name,
-			&chain.Icon,
-			&chain.CreatedAt,
-			&chain.UpdatedAt,
-		)
+		err := rows.Scan(append([]interface{}{&productID}, chainFields(&chain)...)...)
 		if err != nil {
 			return fmt.Errorf("failed to scan chain: %w", err)
 		}
-		chains = append(chains, chain)
+		if product, ok := byID[productID]; ok {
+			product.Chains = append(product.Chains, chain)
+		}
 	}
 
-	product.Chains = chains
-	return nil
-}
-
-func (r *PostgresRepository) getProductUpvoteCount(productID string) (int, error) {
-	query := `SELECT COUNT(*) FROM upvotes WHERE product_id = $1`
-	var count int
-	err := r.db.QueryRow(query, productID).Scan(&count)
-	if err != nil {
-		return 0, fmt.Errorf("failed to count upvotes: %w", err)
-	}
-	return count, nil
+	return rows.Err()
 }
 
 // Add the rest of the repository methods as needed
-// (UpvoteProduct, GetCategories, CreateCategory, etc.)
+// (UpvoteProduct, pending edits, etc.)
 
-// GetCategories returns all categories
-func (r *PostgresRepository) GetCategories() ([]models.Category, error) {
-	query := `SELECT id, name, description, created_at, updated_at FROM categories ORDER BY name ASC`
+// UpvoteProduct records a weighted upvote and updates the product's counters
+func (r *PostgresRepository) UpvoteProduct(upvote *models.Upvote) error {
+	if upvote.ID == "" {
+		upvote.ID = generateID()
+	}
+	upvote.CreatedAt = time.Now()
 
-	rows, err := r.db.Query(query)
+	signals, err := json.Marshal(upvote.WeightSignals)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get categories: %w", err)
+		return fmt.Errorf("failed to marshal weight signals: %w", err)
 	}
-	defer rows.Close()
 
-	categories := []models.Category{}
-	for rows.Next() {
-		var category models.Category
-		err := rows.Scan(
-			&category.ID,
-			&category.Name,
-			&category.Description,
-			&category.CreatedAt,
-			&category.UpdatedAt,
-		)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan category: %w", err)
+			tx.Rollback()
 		}
+	}()
+
+	// The (user_id, product_id) unique constraint rejects duplicate votes
+	result, err := tx.Exec(`
+		INSERT INTO upvotes (id, user_id, product_id, weight, weight_signals, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, product_id) DO NOTHING
+	`, upvote.ID, upvote.UserID, upvote.ProductID, upvote.Weight, signals, upvote.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add upvote: %w", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check upvote result: %w", err)
+	}
+	if inserted == 0 {
+		err = errors.New("already upvoted")
+		return err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE products SET upvote_count = upvote_count + 1, weighted_score = weighted_score + $2 WHERE id = $1",
+		upvote.ProductID, upvote.Weight,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to increment upvote count: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
 
-		// Get product count
-		var count int
-		countQuery := `SELECT COUNT(*) FROM product_categories WHERE category_id = $1`
-		err = r.db.QueryRow(countQuery, category.ID).Scan(&count)
+// RemoveUpvote removes a user's upvote from a product and updates its counters
+func (r *PostgresRepository) RemoveUpvote(userID, productID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
 		if err != nil {
-			return nil, fmt.Errorf("failed to count products for category: %w", err)
+			tx.Rollback()
 		}
-		category.ProductCount = count
+	}()
+
+	var weight float64
+	err = tx.QueryRow(
+		"DELETE FROM upvotes WHERE user_id = $1 AND product_id = $2 RETURNING weight",
+		userID, productID,
+	).Scan(&weight)
+	if err == sql.ErrNoRows {
+		err = errors.New("upvote not found")
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove upvote: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE products
+		SET upvote_count = GREATEST(upvote_count - 1, 0), weighted_score = GREATEST(weighted_score - $2, 0)
+		WHERE id = $1
+	`, productID, weight)
+	if err != nil {
+		return fmt.Errorf("failed to decrement upvote count: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetUpvotedProductIDs returns which of the given products the user has upvoted
+func (r *PostgresRepository) GetUpvotedProductIDs(userID string, productIDs []string) (map[string]bool, error) {
+	upvoted := make(map[string]bool)
+	if len(productIDs) == 0 {
+		return upvoted, nil
+	}
 
-		categories = append(categories, category)
+	rows, err := r.db.Query(
+		"SELECT product_id FROM upvotes WHERE user_id = $1 AND product_id = ANY($2)",
+		userID, pq.Array(productIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user upvotes: %w", err)
 	}
+	defer rows.Close()
 
-	return categories, nil
+	for rows.Next() {
+		var productID string
+		if err := rows.Scan(&productID); err != nil {
+			return nil, fmt.Errorf("failed to scan upvote: %w", err)
+		}
+		upvoted[productID] = true
+	}
+
+	return upvoted, rows.Err()
 }
 
-// CreateCategory creates a new category
-func (r *PostgresRepository) CreateCategory(category *models.Category) error {
-	if category.ID == "" {
-		category.ID = generateID()
+// GetUserUpvotes returns a user's upvotes, newest first, with the upvoted products
+func (r *PostgresRepository) GetUserUpvotes(userID string, page, perPage int) ([]models.Upvote, int, error) {
+	offset := (page - 1) * perPage
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM upvotes WHERE user_id = $1", userID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count user upvotes: %w", err)
 	}
-	category.CreatedAt = time.Now()
-	category.UpdatedAt = time.Now()
 
 	query := `
-		INSERT INTO categories (id, name, description, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, name, description, created_at, updated_at
+		SELECT u.id, u.user_id, u.product_id, u.weight, u.created_at, ` + productColumns + `
+		FROM upvotes u
+		JOIN products p ON p.id = u.product_id
+		WHERE u.user_id = $1
+		ORDER BY u.created_at DESC
+		LIMIT $2 OFFSET $3
 	`
 
-	err := r.db.QueryRow(
-		query,
-		category.ID,
-		category.Name,
-		category.Description,
-		category.CreatedAt,
-		category.UpdatedAt,
-	).Scan(
-		&category.ID,
-		&category.Name,
-		&category.Description,
-		&category.CreatedAt,
-		&category.UpdatedAt,
-	)
-
+	rows, err := r.db.Query(query, userID, perPage, offset)
 	if err != nil {
-		return fmt.Errorf("failed to create category: %w", err)
+		return nil, 0, fmt.Errorf("failed to get user upvotes: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
-}
+	upvotes := []models.Upvote{}
+	products := []*models.Product{}
+	for rows.Next() {
+		var upvote models.Upvote
+		product := &models.Product{}
+		dest := append([]interface{}{
+			&upvote.ID,
+			&upvote.UserID,
+			&upvote.ProductID,
+			&upvote.Weight,
+			&upvote.CreatedAt,
+		}, productFields(product)...)
+		err := rows.Scan(dest...)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user upvote: %w", err)
+		}
+		upvote.Product = product
+		upvotes = append(upvotes, upvote)
+		products = append(products, product)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate user upvotes: %w", err)
+	}
 
-// UpvoteProduct adds an upvote to a product
-func (r *PostgresRepository) UpvoteProduct(userID, productID string) error {
-	// Check if user already upvoted this product
-	var count int
-	err := r.db.QueryRow(
-		"SELECT COUNT(*) FROM upvotes WHERE user_id = $1 AND product_id = $2",
-		userID, productID,
-	).Scan(&count)
+	if err = r.loadProductRelations(r.db, products); err != nil {
+		return nil, 0, err
+	}
+
+	return upvotes, total, nil
+}
 
+// ReconcileUpvoteCounts recomputes products.upvote_count and weighted_score from
+// the upvotes table and reports every product whose stored values had drifted. When repair is
+// false the report is produced without modifying any rows.
+func (r *PostgresRepository) ReconcileUpvoteCounts(repair bool) (*models.UpvoteReconciliation, error) {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to check existing upvote: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	if count > 0 {
-		return errors.New("already upvoted")
+	report := &models.UpvoteReconciliation{
+		Drift:    []models.UpvoteDrift{},
+		Repaired: repair,
 	}
 
-	// Add upvote
-	upvoteID := generateID()
-	_, err = r.db.Exec(
-		"INSERT INTO upvotes (id, user_id, product_id, created_at) VALUES ($1, $2, $3, $4)",
-		upvoteID, userID, productID, time.Now(),
-	)
+	err = tx.QueryRow("SELECT COUNT(*) FROM products").Scan(&report.ProductsChecked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count products: %w", err)
+	}
 
+	rows, err := tx.Query(`
+		SELECT p.id, p.title, p.upvote_count, COALESCE(u.actual, 0), p.weighted_score, COALESCE(u.actual_score, 0)
+		FROM products p
+		LEFT JOIN (
+			SELECT product_id, COUNT(*) AS actual, SUM(weight) AS actual_score
+			FROM upvotes GROUP BY product_id
+		) u ON u.product_id = p.id
+		WHERE p.upvote_count <> COALESCE(u.actual, 0)
+		   OR ABS(p.weighted_score - COALESCE(u.actual_score, 0)) > 0.0001
+		ORDER BY p.id
+	`)
 	if err != nil {
-		return fmt.Errorf("failed to add upvote: %w", err)
+		return nil, fmt.Errorf("failed to compute upvote drift: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
+	for rows.Next() {
+		var drift models.UpvoteDrift
+		err := rows.Scan(
+			&drift.ProductID,
+			&drift.Title,
+			&drift.StoredCount,
+			&drift.ActualCount,
+			&drift.StoredScore,
+			&drift.ActualScore,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan upvote drift: %w", err)
+		}
+		report.Drift = append(report.Drift, drift)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate upvote drift: %w", err)
+	}
+
+	if !repair || len(report.Drift) == 0 {
+		return report, nil
+	}
+
+	for _, drift := range report.Drift {
+		_, err = tx.Exec(
+			"UPDATE products SET upvote_count = $2, weighted_score = $3 WHERE id = $1",
+			drift.ProductID, drift.ActualCount, drift.ActualScore,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to repair upvote count for %s: %w", drift.ProductID, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return report, nil
 }
 
 // GetPendingEdits returns all pending edits
@@ -685,6 +1023,37 @@ func (r *PostgresRepository) GetPendingEdits() ([]models.PendingEdit, error) {
 	return pendingEdits, nil
 }
 
+// CreatePendingEdit queues a change for moderator review
+func (r *PostgresRepository) CreatePendingEdit(edit *models.PendingEdit) error {
+	if edit.ID == "" {
+		edit.ID = generateID()
+	}
+	if edit.EntityID == "" {
+		edit.EntityID = generateID()
+	}
+	edit.Status = "pending"
+	edit.CreatedAt = time.Now()
+
+	_, err := r.db.Exec(`
+		INSERT INTO pending_edits (id, user_id, entity_type, entity_id, change_type, change_data, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+		edit.ID,
+		edit.UserID,
+		edit.EntityType,
+		edit.EntityID,
+		edit.ChangeType,
+		edit.ChangeData,
+		edit.Status,
+		edit.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pending edit: %w", err)
+	}
+
+	return nil
+}
+
 // ApproveEdit approves a pending edit
 func (r *PostgresRepository) ApproveEdit(editID string) error {
 	// Begin transaction
@@ -743,46 +1112,59 @@ func (r *PostgresRepository) ApproveEdit(editID string) error {
 				return fmt.Errorf("failed to unmarshal product data: %w", err)
 			}
 
-			// Set approved flag and revision tracking
+			// Approving a submission publishes it, with scores left for assessment
 			product.Approved = true
+			product.Status = "published"
 			product.CurrentRevisionNumber = 1
+			product.SecurityScore = scoring.Unassessed
+			product.UXScore = scoring.Unassessed
+			product.DecentScore = scoring.Unassessed
+			product.VibesScore = scoring.Unassessed
 
 			// If entity ID exists, use it; otherwise generate a new one
 			if edit.EntityID != "" {
 				product.ID = edit.EntityID
 			}
+			if product.ID == "" {
+				product.ID = generateID()
+			}
 
-			// Insert the product using the main logic
-			_, err = tx.Exec(`
+			// Insert the product using the main logic, under the first free slug
+			product.Slug, err = writeProductSlugTx(tx, product.ID, product.Title, func(productSlug string) error {
+				product.Slug = productSlug
+				_, err := tx.Exec(`
 				INSERT INTO products (
 					id, title, short_desc, long_desc, logo_url, markdown_content, submitter_id, 
 					approved, is_verified, analytics_list, security_score, ux_score, decent_score, vibes_score,
-					current_revision_number, last_editor_id, created_at, updated_at
+					current_revision_number, last_editor_id, created_at, updated_at, slug, status
 				)
 				VALUES (
-					$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
+					$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
 				)
 			`,
-				product.ID,
-				product.Title,
-				product.ShortDesc,
-				product.LongDesc,
-				product.LogoURL,
-				product.MarkdownContent,
-				product.SubmitterID,
-				product.Approved,
-				product.IsVerified,
-				pq.Array(product.AnalyticsList),
-				product.SecurityScore,
-				product.UXScore,
-				product.DecentScore,
-				product.VibesScore,
-				product.CurrentRevisionNumber,
-				product.SubmitterID, // last_editor_id is initially the submitter
-				time.Now(),
-				time.Now(),
-			)
-
+					product.ID,
+					product.Title,
+					product.ShortDesc,
+					product.LongDesc,
+					product.LogoURL,
+					product.MarkdownContent,
+					product.SubmitterID,
+					product.Approved,
+					product.IsVerified,
+					pq.Array(product.AnalyticsList),
+					product.SecurityScore,
+					product.UXScore,
+					product.DecentScore,
+					product.VibesScore,
+					product.CurrentRevisionNumber,
+					product.SubmitterID, // last_editor_id is initially the submitter
+					time.Now(),
+					time.Now(),
+					product.Slug,
+					product.Status,
+				)
+				return err
+			})
 			if err != nil {
 				return fmt.Errorf("failed to insert approved product: %w", err)
 			}
@@ -794,34 +1176,22 @@ func (r *PostgresRepository) ApproveEdit(editID string) error {
 				return fmt.Errorf("failed to create initial revision: %w", err)
 			}
 
+			err = insertProductStatusChangeTx(tx, &models.ProductStatusChange{
+				ProductID: product.ID,
+				ToStatus:  product.Status,
+				Reason:    "approved edit",
+				ActorID:   &product.SubmitterID,
+			})
+			if err != nil {
+				return err
+			}
+
 		} else if edit.ChangeType == "update" {
 			// Get current product state for diff calculation
-			var currentProduct models.Product
-			err = tx.QueryRow(`
-				SELECT id, title, short_desc, long_desc, logo_url, markdown_content, submitter_id, 
-					   approved, is_verified, analytics_list, security_score, ux_score, decent_score, vibes_score,
-					   current_revision_number, last_editor_id, created_at, updated_at
-				FROM products WHERE id = $1
-			`, edit.EntityID).Scan(
-				&currentProduct.ID,
-				&currentProduct.Title,
-				&currentProduct.ShortDesc,
-				&currentProduct.LongDesc,
-				&currentProduct.LogoURL,
-				&currentProduct.MarkdownContent,
-				&currentProduct.SubmitterID,
-				&currentProduct.Approved,
-				&currentProduct.IsVerified,
-				pq.Array(&currentProduct.AnalyticsList),
-				&currentProduct.SecurityScore,
-				&currentProduct.UXScore,
-				&currentProduct.DecentScore,
-				&currentProduct.VibesScore,
-				&currentProduct.CurrentRevisionNumber,
-				&currentProduct.LastEditorID,
-				&currentProduct.CreatedAt,
-				&currentProduct.UpdatedAt,
-			)
+			var currentProduct *models.Product
+			currentProduct, err = scanProduct(tx.QueryRow(
+				`SELECT `+productColumns+` FROM products p WHERE p.id = $1`, edit.EntityID,
+			))
 			if err != nil {
 				return fmt.Errorf("failed to get current product: %w", err)
 			}
@@ -833,15 +1203,36 @@ func (r *PostgresRepository) ApproveEdit(editID string) error {
 				return fmt.Errorf("failed to unmarshal product data: %w", err)
 			}
 
-			// Ensure we keep the product ID and mark as approved
+			// Keep the product ID; the lifecycle state and scores are not part of an edit
 			newProduct.ID = edit.EntityID
-			newProduct.Approved = true
+			newProduct.Approved = currentProduct.Approved
+			newProduct.Status = currentProduct.Status
+			newProduct.StatusReason = currentProduct.StatusReason
+			newProduct.SuccessorID = currentProduct.SuccessorID
+			newProduct.SecurityScore = currentProduct.SecurityScore
+			newProduct.UXScore = currentProduct.UXScore
+			newProduct.DecentScore = currentProduct.DecentScore
+			newProduct.VibesScore = currentProduct.VibesScore
 			newProduct.CurrentRevisionNumber = currentProduct.CurrentRevisionNumber + 1
 			newProduct.LastEditorID = &edit.UserID
 			newProduct.UpdatedAt = time.Now()
 
+			// A new title may move the product to a new slug
+			newProduct.Slug, err = updateProductSlugTx(tx, edit.EntityID, newProduct.Title)
+			if err != nil {
+				return err
+			}
+
+			// Tags new to the site are only created once the edit is approved
+			if newProduct.Tags != nil {
+				newProduct.Tags, err = ensureTagsTx(tx, newProduct.Tags, &edit.UserID)
+				if err != nil {
+					return err
+				}
+			}
+
 			// Calculate differences for the revision
-			changes := r.calculateProductDifferences(&currentProduct, &newProduct)
+			changes := r.calculateProductDifferences(currentProduct, &newProduct)
 
 			// Create edit summary from pending edit or generate default
 			editSummary := "Product update (approved edit)"
@@ -863,10 +1254,9 @@ func (r *PostgresRepository) ApproveEdit(editID string) error {
 			_, err = tx.Exec(`
 				UPDATE products
 				SET title = $1, short_desc = $2, long_desc = $3, logo_url = $4, markdown_content = $5, 
-					is_verified = $6, analytics_list = $7, security_score = $8, ux_score = $9, 
-					decent_score = $10, vibes_score = $11, current_revision_number = $12, 
-					last_editor_id = $13, updated_at = $14, approved = true
-				WHERE id = $15
+					is_verified = $6, analytics_list = $7, current_revision_number = $8, 
+					last_editor_id = $9, updated_at = $10
+				WHERE id = $11
 			`,
 				newProduct.Title,
 				newProduct.ShortDesc,
@@ -875,10 +1265,6 @@ func (r *PostgresRepository) ApproveEdit(editID string) error {
 				newProduct.MarkdownContent,
 				newProduct.IsVerified,
 				pq.Array(newProduct.AnalyticsList),
-				newProduct.SecurityScore,
-				newProduct.UXScore,
-				newProduct.DecentScore,
-				newProduct.VibesScore,
 				newProduct.CurrentRevisionNumber,
 				edit.UserID,
 				time.Now(),
@@ -888,6 +1274,23 @@ func (r *PostgresRepository) ApproveEdit(editID string) error {
 			if err != nil {
 				return fmt.Errorf("failed to update product: %w", err)
 			}
+
+			// Queued edits carry tags, links and contracts like direct ones
+			if newProduct.Tags != nil {
+				if err = setProductTagsTx(tx, edit.EntityID, newProduct.Tags); err != nil {
+					return err
+				}
+			}
+			if newProduct.Links != nil {
+				if err = setProductLinksTx(tx, edit.EntityID, newProduct.Links); err != nil {
+					return err
+				}
+			}
+			if newProduct.Contracts != nil {
+				if err = setProductContractsTx(tx, edit.EntityID, newProduct.Contracts); err != nil {
+					return err
+				}
+			}
 		}
 	case "category":
 		// Similar logic for category changes
@@ -899,8 +1302,15 @@ func (r *PostgresRepository) ApproveEdit(editID string) error {
 			}
 
 			if edit.ChangeType == "create" {
-				// Categories are auto-approved, so they should already be in the database
-				// This is just a safety check
+				// Categories submitted by curators are created directly, so
+				// check whether this one already exists
+				if edit.EntityID != "" {
+					category.ID = edit.EntityID
+				}
+				if category.Slug == "" {
+					category.Slug = category.ID
+				}
+
 				var count int
 				err = tx.QueryRow("SELECT COUNT(*) FROM categories WHERE id = $1", category.ID).Scan(&count)
 				if err != nil {
@@ -909,12 +1319,14 @@ func (r *PostgresRepository) ApproveEdit(editID string) error {
 
 				if count == 0 {
 					_, err = tx.Exec(`
-						INSERT INTO categories (id, name, description, created_at, updated_at)
-						VALUES ($1, $2, $3, $4, $5)
+						INSERT INTO categories (id, name, slug, description, parent_id, created_at, updated_at)
+						VALUES ($1, $2, $3, $4, $5, $6, $7)
 					`,
 						category.ID,
 						category.Name,
+						category.Slug,
 						category.Description,
+						category.ParentID,
 						time.Now(),
 						time.Now(),
 					)
@@ -979,9 +1391,10 @@ func (r *PostgresRepository) RejectEdit(editID string) error {
 	return nil
 }
 
-// generateID generates a unique ID for database entities
+// generateID generates a unique ID for database entities. Random UUIDs stay
+// unique across concurrent requests and server instances, unlike timestamps.
 func generateID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
+	return uuid.New().String()
 }
 
 // DeleteAllProducts deletes all products from the database
@@ -1058,6 +1471,20 @@ func (r *PostgresRepository) CreateProductRevision(productID string, editorID *s
 
 	newRevision := currentRevision + 1
 
+	// A new title may move the product to a new slug
+	newProductData.Slug, err = updateProductSlugTx(tx, productID, newProductData.Title)
+	if err != nil {
+		return err
+	}
+
+	// Create tags new to the site with the edit that introduces them
+	if newProductData.Tags != nil {
+		newProductData.Tags, err = ensureTagsTx(tx, newProductData.Tags, editorID)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create the revision
 	err = r.createProductRevisionTx(tx, productID, newRevision, editorID, editSummary, &changes, newProductData)
 	if err != nil {
@@ -1068,10 +1495,9 @@ func (r *PostgresRepository) CreateProductRevision(productID string, editorID *s
 	_, err = tx.Exec(`
 		UPDATE products
 		SET title = $1, short_desc = $2, long_desc = $3, logo_url = $4, markdown_content = $5, 
-			is_verified = $6, analytics_list = $7, security_score = $8, ux_score = $9, 
-			decent_score = $10, vibes_score = $11, current_revision_number = $12, 
-			last_editor_id = $13, updated_at = $14
-		WHERE id = $15
+			is_verified = $6, analytics_list = $7, current_revision_number = $8, 
+			last_editor_id = $9, updated_at = $10
+		WHERE id = $11
 	`,
 		newProductData.Title,
 		newProductData.ShortDesc,
@@ -1080,10 +1506,6 @@ func (r *PostgresRepository) CreateProductRevision(productID string, editorID *s
 		newProductData.MarkdownContent,
 		newProductData.IsVerified,
 		pq.Array(newProductData.AnalyticsList),
-		newProductData.SecurityScore,
-		newProductData.UXScore,
-		newProductData.DecentScore,
-		newProductData.VibesScore,
 		newRevision,
 		editorID,
 		time.Now(),
@@ -1094,6 +1516,26 @@ func (r *PostgresRepository) CreateProductRevision(productID string, editorID *s
 		return fmt.Errorf("failed to update product: %w", err)
 	}
 
+	// Tags, links and contracts are part of the revision; nil leaves them untouched
+	if newProductData.Tags != nil {
+		err = setProductTagsTx(tx, productID, newProductData.Tags)
+		if err != nil {
+			return err
+		}
+	}
+	if newProductData.Links != nil {
+		err = setProductLinksTx(tx, productID, newProductData.Links)
+		if err != nil {
+			return err
+		}
+	}
+	if newProductData.Contracts != nil {
+		err = setProductContractsTx(tx, productID, newProductData.Contracts)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -1155,20 +1597,26 @@ func (r *PostgresRepository) GetProductRevisions(productID string, page, perPage
 
 	// Get total count
 	var total int
-	err := r.db.QueryRow("SELECT COUNT(*) FROM product_revisions WHERE product_id = $1", productID).Scan(&total)
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM product_revisions pr
+		JOIN products p ON pr.product_id = p.id
+		WHERE pr.product_id = $1 OR p.merged_into = $1
+	`, productID).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get revision count: %w", err)
 	}
 
-	// Get revisions with editor info
+	// Get revisions with editor info, including those of duplicates merged into this product
 	query := `
 		SELECT pr.revision_number, pr.edit_summary, pr.editor_id, pr.created_at,
-			   u.wallet_address, u.twitter_handle,
-			   COALESCE((SELECT COUNT(*) FROM product_field_changes pfc WHERE pfc.revision_id = pr.id), 0) as change_count
+			   COALESCE((SELECT COUNT(*) FROM product_field_changes pfc WHERE pfc.revision_id = pr.id), 0) as change_count,
+			   p.id, p.slug, p.title,
+			   ` + userSummaryColumns + `
 		FROM product_revisions pr
+		JOIN products p ON pr.product_id = p.id
 		LEFT JOIN users u ON pr.editor_id = u.id
-		WHERE pr.product_id = $1
-		ORDER BY pr.revision_number DESC
+		WHERE pr.product_id = $1 OR p.merged_into = $1
+		ORDER BY pr.created_at DESC, pr.revision_number DESC
 		LIMIT $2 OFFSET $3
 	`
 
@@ -1181,28 +1629,31 @@ func (r *PostgresRepository) GetProductRevisions(productID string, page, perPage
 	var revisions []models.RevisionSummary
 	for rows.Next() {
 		var rev models.RevisionSummary
-		var walletAddress, twitterHandle sql.NullString
+		var editor userSummary
+		var origin models.ProductLink
 
-		err := rows.Scan(
+		err := rows.Scan(append([]interface{}{
 			&rev.RevisionNumber,
 			&rev.EditSummary,
 			&rev.EditorID,
 			&rev.CreatedAt,
-			&walletAddress,
-			&twitterHandle,
 			&rev.ChangeCount,
-		)
+			&origin.ID,
+			&origin.Slug,
+			&origin.Title,
+		}, editor.dest()...)...)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan revision: %w", err)
 		}
 
+		// Revisions of a merged duplicate are numbered within that product
+		if origin.ID != productID {
+			rev.MergedFrom = &origin
+		}
+
 		// Add editor info if available
-		if walletAddress.Valid {
-			rev.Editor = &models.User{
-				ID:            *rev.EditorID,
-				WalletAddress: walletAddress.String,
-				TwitterHandle: twitterHandle.String,
-			}
+		if rev.EditorID != nil {
+			rev.Editor = editor.user(*rev.EditorID)
 		}
 
 		// Determine if this is a major change (more than 2 field changes)
@@ -1219,16 +1670,16 @@ func (r *PostgresRepository) GetProductRevision(productID string, revisionNumber
 	query := `
 		SELECT pr.id, pr.product_id, pr.revision_number, pr.editor_id, pr.edit_summary, 
 			   pr.diff_data, pr.product_data, pr.created_at,
-			   u.wallet_address, u.twitter_handle
+			   ` + userSummaryColumns + `
 		FROM product_revisions pr
 		LEFT JOIN users u ON pr.editor_id = u.id
 		WHERE pr.product_id = $1 AND pr.revision_number = $2
 	`
 
 	var revision models.ProductRevision
-	var walletAddress, twitterHandle sql.NullString
+	var editor userSummary
 
-	err := r.db.QueryRow(query, productID, revisionNumber).Scan(
+	err := r.db.QueryRow(query, productID, revisionNumber).Scan(append([]interface{}{
 		&revision.ID,
 		&revision.ProductID,
 		&revision.RevisionNumber,
@@ -1237,9 +1688,7 @@ func (r *PostgresRepository) GetProductRevision(productID string, revisionNumber
 		&revision.DiffData,
 		&revision.ProductData,
 		&revision.CreatedAt,
-		&walletAddress,
-		&twitterHandle,
-	)
+	}, editor.dest()...)...)
 
 	if err == sql.ErrNoRows {
 		return nil, errors.New("revision not found")
@@ -1249,12 +1698,8 @@ func (r *PostgresRepository) GetProductRevision(productID string, revisionNumber
 	}
 
 	// Add editor info if available
-	if walletAddress.Valid {
-		revision.Editor = &models.User{
-			ID:            *revision.EditorID,
-			WalletAddress: walletAddress.String,
-			TwitterHandle: twitterHandle.String,
-		}
+	if revision.EditorID != nil {
+		revision.Editor = editor.user(*revision.EditorID)
 	}
 
 	// Load field changes
@@ -1382,10 +1827,28 @@ func (r *PostgresRepository) calculateProductDifferences(from, to *models.Produc
 	fromAnalytics, _ := json.Marshal(from.AnalyticsList)
 	toAnalytics, _ := json.Marshal(to.AnalyticsList)
 	addChange("analytics_list", string(fromAnalytics), string(toAnalytics))
+	addChange("tags", models.TagList(from.Tags), models.TagList(to.Tags))
+
+	// Snapshots taken before links and contracts existed leave them untouched
+	if to.Links != nil {
+		addChange("links", listJSON(len(from.Links), from.Links), listJSON(len(to.Links), to.Links))
+	}
+	if to.Contracts != nil {
+		addChange("contracts", listJSON(len(from.Contracts), from.Contracts), listJSON(len(to.Contracts), to.Contracts))
+	}
 
 	return changes
 }
 
+// listJSON renders a list for a field change, with empty lists as ""
+func listJSON(n int, list interface{}) string {
+	if n == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(list)
+	return string(data)
+}
+
 // RevertProductToRevision reverts a product to a specific revision
 func (r *PostgresRepository) RevertProductToRevision(productID string, revisionNumber int, editorID *string, reason string) error {
 	// Get the target revision
@@ -1407,6 +1870,12 @@ func (r *PostgresRepository) RevertProductToRevision(productID string, revisionN
 		return fmt.Errorf("failed to get current product: %w", err)
 	}
 
+	// Scores are kept by assessments, not revisions
+	targetProduct.SecurityScore = currentProduct.SecurityScore
+	targetProduct.UXScore = currentProduct.UXScore
+	targetProduct.DecentScore = currentProduct.DecentScore
+	targetProduct.VibesScore = currentProduct.VibesScore
+
 	// Calculate changes (from current to target)
 	changes := r.calculateProductDifferences(currentProduct, &targetProduct)
 
@@ -1419,6 +1888,16 @@ func (r *PostgresRepository) RevertProductToRevision(productID string, revisionN
 		return fmt.Errorf("failed to create revert revision: %w", err)
 	}
 
+	// Record which revisions the revert undid
+	_, err = r.db.Exec(`
+		UPDATE product_revisions
+		SET reverted_in = $3, reverted_by = $4
+		WHERE product_id = $1 AND revision_number > $2 AND revision_number < $3 AND reverted_in IS NULL
+	`, productID, revisionNumber, currentProduct.CurrentRevisionNumber+1, editorID)
+	if err != nil {
+		return fmt.Errorf("failed to mark reverted revisions: %w", err)
+	}
+
 	return nil
 }
 
@@ -1426,8 +1905,9 @@ func (r *PostgresRepository) RevertProductToRevision(productID string, revisionN
 func (r *PostgresRepository) GetRecentEdits(limit int) ([]models.RevisionSummary, error) {
 	query := `
 		SELECT pr.product_id, pr.revision_number, pr.edit_summary, pr.editor_id, pr.created_at,
-			   u.wallet_address, u.twitter_handle, p.title,
-			   COALESCE((SELECT COUNT(*) FROM product_field_changes pfc WHERE pfc.revision_id = pr.id), 0) as change_count
+			   p.title,
+			   COALESCE((SELECT COUNT(*) FROM product_field_changes pfc WHERE pfc.revision_id = pr.id), 0) as change_count,
+			   ` + userSummaryColumns + `
 		FROM product_revisions pr
 		LEFT JOIN users u ON pr.editor_id = u.id
 		LEFT JOIN products p ON pr.product_id = p.id
@@ -1444,31 +1924,26 @@ func (r *PostgresRepository) GetRecentEdits(limit int) ([]models.RevisionSummary
 	var edits []models.RevisionSummary
 	for rows.Next() {
 		var edit models.RevisionSummary
-		var walletAddress, twitterHandle, productTitle sql.NullString
+		var editor userSummary
+		var productTitle sql.NullString
 		var productID string
 
-		err := rows.Scan(
+		err := rows.Scan(append([]interface{}{
 			&productID,
 			&edit.RevisionNumber,
 			&edit.EditSummary,
 			&edit.EditorID,
 			&edit.CreatedAt,
-			&walletAddress,
-			&twitterHandle,
 			&productTitle,
 			&edit.ChangeCount,
-		)
+		}, editor.dest()...)...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan recent edit: %w", err)
 		}
 
 		// Add editor info if available
-		if walletAddress.Valid {
-			edit.Editor = &models.User{
-				ID:            *edit.EditorID,
-				WalletAddress: walletAddress.String,
-				TwitterHandle: twitterHandle.String,
-			}
+		if edit.EditorID != nil {
+			edit.Editor = editor.user(*edit.EditorID)
 		}
 
 		edit.MajorChange = edit.ChangeCount > 2
@@ -1485,9 +1960,8 @@ func (r *PostgresRepository) UpdateProduct(product *models.Product) error {
 		UPDATE products 
 		SET title = $2, short_desc = $3, long_desc = $4, logo_url = $5, 
 		    markdown_content = $6, approved = $7, is_verified = $8, 
-		    analytics_list = $9, security_score = $10, ux_score = $11, 
-		    decent_score = $12, vibes_score = $13, current_revision_number = $14,
-		    last_editor_id = $15, updated_at = NOW()
+		    analytics_list = $9, current_revision_number = $10,
+		    last_editor_id = $11, updated_at = NOW()
 		WHERE id = $1
 	`
 
@@ -1502,10 +1976,6 @@ func (r *PostgresRepository) UpdateProduct(product *models.Product) error {
 		product.Approved,
 		product.IsVerified,
 		pq.Array(product.AnalyticsList),
-		product.SecurityScore,
-		product.UXScore,
-		product.DecentScore,
-		product.VibesScore,
 		product.CurrentRevisionNumber,
 		product.LastEditorID,
 	)
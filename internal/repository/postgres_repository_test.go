@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// countingDriver is a database/sql driver that answers product listing
+// queries with canned rows and counts every query it is sent. A product page
+// query returns pageSize products; relation queries return no rows.
+type countingDriver struct {
+	mu       sync.Mutex
+	queries  int
+	pageSize int
+}
+
+func (d *countingDriver) reset(pageSize int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = 0
+	d.pageSize = pageSize
+}
+
+func (d *countingDriver) count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.queries
+}
+
+func (d *countingDriver) Open(string) (driver.Conn, error) { return &countingConn{d: d}, nil }
+
+type countingConn struct{ d *countingDriver }
+
+func (c *countingConn) Prepare(query string) (driver.Stmt, error) {
+	return &countingStmt{d: c.d, query: query}, nil
+}
+func (c *countingConn) Close() error { return nil }
+func (c *countingConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transactions not supported")
+}
+
+type countingStmt struct {
+	d     *countingDriver
+	query string
+}
+
+func (s *countingStmt) Close() error  { return nil }
+func (s *countingStmt) NumInput() int { return -1 }
+func (s *countingStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("exec not supported")
+}
+
+func (s *countingStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	s.d.queries++
+	pageSize := s.d.pageSize
+	s.d.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(s.query), "SELECT COUNT("):
+		return &cannedRows{columns: []string{"count"}, rows: [][]driver.Value{{int64(pageSize)}}}, nil
+	case strings.Contains(s.query, productColumns):
+		columns := splitColumns(productColumns)
+		rows := make([][]driver.Value, pageSize)
+		for i := range rows {
+			rows[i] = productRow(fmt.Sprintf("product-%d", i), columns)
+		}
+		return &cannedRows{columns: columns, rows: rows}, nil
+	default:
+		return &cannedRows{}, nil
+	}
+}
+
+// splitColumns splits a column list at the commas between columns, leaving
+// those inside function calls alone
+func splitColumns(list string) []string {
+	var columns []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				columns = append(columns, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(columns, list[start:])
+}
+
+// productRow returns a value of the right type for each product column
+func productRow(id string, columns []string) []driver.Value {
+	row := make([]driver.Value, len(columns))
+	for i, column := range columns {
+		column = strings.TrimSpace(column)
+		switch {
+		case column == "p.id":
+			row[i] = id
+		case column == "p.approved", column == "p.is_verified":
+			row[i] = true
+		case column == "p.analytics_list":
+			row[i] = []byte("{}")
+		case strings.HasSuffix(column, "_count"), strings.HasSuffix(column, "_number"):
+			row[i] = int64(1)
+		case strings.HasSuffix(column, "_score"):
+			row[i] = 0.5
+		case strings.HasSuffix(column, "_at"):
+			row[i] = time.Now()
+		default:
+			row[i] = column
+		}
+	}
+	return row
+}
+
+type cannedRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *cannedRows) Columns() []string { return r.columns }
+func (r *cannedRows) Close() error      { return nil }
+
+func (r *cannedRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
+
+var (
+	countingSQL     = &countingDriver{}
+	registerCounter sync.Once
+)
+
+func newCountingRepository(tb testing.TB) *PostgresRepository {
+	tb.Helper()
+	registerCounter.Do(func() { sql.Register("counting", countingSQL) })
+
+	db, err := sql.Open("counting", "")
+	if err != nil {
+		tb.Fatalf("failed to open counting database: %v", err)
+	}
+	tb.Cleanup(func() { db.Close() })
+
+	return &PostgresRepository{db: db}
+}
+
+// getProductPage lists the first page of products with the given page size
+func getProductPage(repo *PostgresRepository, pageSize int) (int, error) {
+	products, _, err := repo.GetProducts(models.ProductFilter{SortBy: "new", Page: 1, PerPage: pageSize})
+	return len(products), err
+}
+
+// TestGetProductsQueryCount checks that listing products costs the same
+// number of queries however many products the page holds
+func TestGetProductsQueryCount(t *testing.T) {
+	repo := newCountingRepository(t)
+
+	queries := make(map[int]int)
+	for _, pageSize := range []int{1, 10, 100} {
+		countingSQL.reset(pageSize)
+		n, err := getProductPage(repo, pageSize)
+		if err != nil {
+			t.Fatalf("GetProducts() error = %v", err)
+		}
+		if n != pageSize {
+			t.Fatalf("GetProducts() returned %d products, want %d", n, pageSize)
+		}
+		queries[pageSize] = countingSQL.count()
+	}
+
+	if queries[1] != queries[10] || queries[1] != queries[100] {
+		t.Errorf("GetProducts() queries grow with page size: %v", queries)
+	}
+}
+
+// BenchmarkGetProducts reports the queries run to list a page of products
+func BenchmarkGetProducts(b *testing.B) {
+	repo := newCountingRepository(b)
+
+	for _, pageSize := range []int{20, 100} {
+		b.Run(fmt.Sprintf("%d products", pageSize), func(b *testing.B) {
+			countingSQL.reset(pageSize)
+			for i := 0; i < b.N; i++ {
+				if _, err := getProductPage(repo, pageSize); err != nil {
+					b.Fatalf("GetProducts() error = %v", err)
+				}
+			}
+			b.ReportMetric(float64(countingSQL.count())/float64(b.N), "queries/page")
+		})
+	}
+}
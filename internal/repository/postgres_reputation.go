@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// GetReputationCounts tallies the contributions a user's reputation is
+// earned from. Reverts of a user's own edits do not count against them.
+// Upvotes received count by weight, and only while the product is listed,
+// so unlisted products and light sockpuppet votes earn little or nothing.
+// Voided votes have already been moved out of upvotes.
+func (r *PostgresRepository) GetReputationCounts(userID string) (*models.ReputationCounts, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM products WHERE submitter_id = $1 AND merged_into IS NULL),
+			(SELECT COUNT(*) FROM products WHERE submitter_id = $1 AND approved = true),
+			(SELECT COUNT(*) FROM product_revisions
+				WHERE editor_id = $1 AND revision_number > 1 AND reverted_in IS NULL),
+			(SELECT COUNT(*) FROM product_revisions
+				WHERE editor_id = $1 AND reverted_in IS NOT NULL AND reverted_by IS DISTINCT FROM $1),
+			(SELECT COUNT(*) FROM pending_edits WHERE user_id = $1 AND status = 'rejected'),
+			(SELECT COALESCE(ROUND(SUM(u.weight)), 0)::int FROM upvotes u JOIN products p ON p.id = u.product_id
+				WHERE p.submitter_id = $1 AND p.approved = true AND p.merged_into IS NULL AND u.user_id <> $1),
+			(SELECT COUNT(*) FROM reports rp JOIN moderation_cases c ON c.id = rp.case_id
+				WHERE rp.reporter_id = $1 AND c.status = 'resolved')
+	`
+
+	var counts models.ReputationCounts
+	err := r.db.QueryRow(query, userID).Scan(
+		&counts.SubmittedProducts,
+		&counts.ApprovedSubmissions,
+		&counts.AcceptedEdits,
+		&counts.RevertedEdits,
+		&counts.RejectedEdits,
+		&counts.UpvotesReceived,
+		&counts.UsefulReports,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reputation: %w", err)
+	}
+
+	return &counts, nil
+}
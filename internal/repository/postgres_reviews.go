@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+const reviewColumns = `rv.id, rv.product_id, rv.user_id, rv.body, rv.rating, rv.status, COALESCE(rv.moderation_reason, ''),
+	rv.moderated_by, rv.moderated_at, rv.edited_at, rv.created_at, ` + userSummaryColumns
+
+// scanReview scans a row selected with reviewColumns, joined to the author
+// as u, into a review
+func scanReview(row rowScanner) (*models.Review, error) {
+	review := &models.Review{}
+	var rating sql.NullInt64
+	var author userSummary
+	err := row.Scan(append([]interface{}{
+		&review.ID,
+		&review.ProductID,
+		&review.UserID,
+		&review.Body,
+		&rating,
+		&review.Status,
+		&review.ModerationReason,
+		&review.ModeratedBy,
+		&review.ModeratedAt,
+		&review.EditedAt,
+		&review.CreatedAt,
+	}, author.dest()...)...)
+	if err != nil {
+		return nil, err
+	}
+	if rating.Valid {
+		value := int(rating.Int64)
+		review.Rating = &value
+	}
+	review.Author = author.user(review.UserID)
+	return review, nil
+}
+
+// refreshDiscussionCountsTx recounts a product's visible reviews and comments
+// within a transaction
+func refreshDiscussionCountsTx(tx *sql.Tx, productID string) error {
+	_, err := tx.Exec(`
+		UPDATE products SET
+			review_count = (SELECT COUNT(*) FROM product_reviews WHERE product_id = $1 AND status = 'visible'),
+			comment_count = (SELECT COUNT(*) FROM product_comments WHERE product_id = $1 AND status = 'visible')
+		WHERE id = $1
+	`, productID)
+	if err != nil {
+		return fmt.Errorf("failed to update discussion counts: %w", err)
+	}
+	return nil
+}
+
+// CreateReview saves a user's review of a product
+func (r *PostgresRepository) CreateReview(review *models.Review) error {
+	review.ID = generateID()
+	review.Status = "visible"
+	review.CreatedAt = time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = lockProductTx(tx, review.ProductID); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO product_reviews (id, product_id, user_id, body, rating, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, review.ID, review.ProductID, review.UserID, review.Body, review.Rating, review.Status, review.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		err = errors.New("you have already reviewed this product")
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create review: %w", err)
+	}
+
+	if err = refreshDiscussionCountsTx(tx, review.ProductID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetReview returns a review by ID
+func (r *PostgresRepository) GetReview(id string) (*models.Review, error) {
+	review, err := scanReview(r.db.QueryRow(`
+		SELECT `+reviewColumns+`
+		FROM product_reviews rv
+		LEFT JOIN users u ON rv.user_id = u.id
+		WHERE rv.id = $1
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("review not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	return review, nil
+}
+
+// GetUserReview returns a user's review of a product
+func (r *PostgresRepository) GetUserReview(productID, userID string) (*models.Review, error) {
+	review, err := scanReview(r.db.QueryRow(`
+		SELECT `+reviewColumns+`
+		FROM product_reviews rv
+		LEFT JOIN users u ON rv.user_id = u.id
+		WHERE rv.product_id = $1 AND rv.user_id = $2
+	`, productID, userID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("review not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	return review, nil
+}
+
+// GetProductReviews returns a page of a product's reviews, newest first, and
+// the total number of them. Hidden reviews are only included when
+// includeHidden is set; removed reviews never are.
+func (r *PostgresRepository) GetProductReviews(productID string, includeHidden bool, page, perPage int) ([]models.Review, int, error) {
+	offset := (page - 1) * perPage
+
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM product_reviews
+		WHERE product_id = $1 AND (status = 'visible' OR ($2 AND status = 'hidden'))
+	`, productID, includeHidden).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get review count: %w", err)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+reviewColumns+`
+		FROM product_reviews rv
+		LEFT JOIN users u ON rv.user_id = u.id
+		WHERE rv.product_id = $1 AND (rv.status = 'visible' OR ($2 AND rv.status = 'hidden'))
+		ORDER BY rv.created_at DESC, rv.id
+		LIMIT $3 OFFSET $4
+	`, productID, includeHidden, perPage, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, *review)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate reviews: %w", err)
+	}
+
+	return reviews, total, nil
+}
+
+// UpdateReview replaces the text and rating of a review
+func (r *PostgresRepository) UpdateReview(id, body string, rating *int) error {
+	result, err := r.db.Exec(`
+		UPDATE product_reviews SET body = $2, rating = $3, edited_at = $4
+		WHERE id = $1
+	`, id, body, rating, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update review: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("review not found")
+	}
+	return nil
+}
+
+// DeleteReview deletes a review and recounts its product's reviews
+func (r *PostgresRepository) DeleteReview(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var productID string
+	err = tx.QueryRow("DELETE FROM product_reviews WHERE id = $1 RETURNING product_id", id).Scan(&productID)
+	if err == sql.ErrNoRows {
+		err = errors.New("review not found")
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete review: %w", err)
+	}
+
+	if err = refreshDiscussionCountsTx(tx, productID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ModerateReview moves a review to another moderation status, recounts its
+// product's reviews and records the action in the audit log. Removing a
+// review also discards its text.
+func (r *PostgresRepository) ModerateReview(id, status, reason, actorID, action string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var productID string
+	err = tx.QueryRow(`
+		UPDATE product_reviews SET
+			status = $2,
+			body = CASE WHEN $2 = 'removed' THEN '' ELSE body END,
+			moderation_reason = NULLIF($3, ''),
+			moderated_by = $4,
+			moderated_at = $5
+		WHERE id = $1
+		RETURNING product_id
+	`, id, status, reason, actorID, time.Now()).Scan(&productID)
+	if err == sql.ErrNoRows {
+		err = errors.New("review not found")
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to moderate review: %w", err)
+	}
+
+	if err = refreshDiscussionCountsTx(tx, productID); err != nil {
+		return err
+	}
+
+	details, _ := json.Marshal(map[string]string{"product_id": productID, "reason": reason})
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     action + "_review",
+		EntityType: "review",
+		EntityID:   id,
+		Details:    details,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
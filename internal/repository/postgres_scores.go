@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/scoring"
+)
+
+// scoreColumns maps each score dimension to the products column caching it
+var scoreColumns = map[string]string{
+	scoring.Security:         "security_score",
+	scoring.UX:               "ux_score",
+	scoring.Decentralization: "decent_score",
+	scoring.Vibes:            "vibes_score",
+}
+
+// GetProductScores returns the current assessments of a product's scores.
+// Dimensions that were never assessed are left out.
+func (r *PostgresRepository) GetProductScores(productID string) ([]models.ScoreAssessment, error) {
+	rows, err := r.db.Query(`
+		SELECT a.product_id, a.dimension, a.score, a.criteria, COALESCE(a.note, ''), a.assessor_id, a.assessed_at,
+			`+userSummaryColumns+`
+		FROM product_score_assessments a
+		LEFT JOIN users u ON a.assessor_id = u.id
+		WHERE a.product_id = $1
+		ORDER BY a.dimension
+	`, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product scores: %w", err)
+	}
+	defer rows.Close()
+
+	assessments := []models.ScoreAssessment{}
+	for rows.Next() {
+		var assessment models.ScoreAssessment
+		var criteria []byte
+		var assessor userSummary
+		err := rows.Scan(append([]interface{}{
+			&assessment.ProductID,
+			&assessment.Dimension,
+			&assessment.Score,
+			&criteria,
+			&assessment.Note,
+			&assessment.AssessorID,
+			&assessment.AssessedAt,
+		}, assessor.dest()...)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan score assessment: %w", err)
+		}
+		if err := json.Unmarshal(criteria, &assessment.Criteria); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal score criteria: %w", err)
+		}
+		if assessment.AssessorID != nil {
+			assessment.Assessor = assessor.user(*assessment.AssessorID)
+		}
+		assessments = append(assessments, assessment)
+	}
+
+	return assessments, rows.Err()
+}
+
+// SaveScoreAssessment replaces the assessment of one product score, updates
+// the cached score on the product and records the change in score history
+func (r *PostgresRepository) SaveScoreAssessment(assessment *models.ScoreAssessment) error {
+	column, ok := scoreColumns[assessment.Dimension]
+	if !ok {
+		return fmt.Errorf("unknown score dimension %q", assessment.Dimension)
+	}
+
+	criteria, err := json.Marshal(assessment.Criteria)
+	if err != nil {
+		return fmt.Errorf("failed to marshal score criteria: %w", err)
+	}
+	assessment.AssessedAt = time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock the product so concurrent assessments record a consistent history
+	var oldScore float64
+	err = tx.QueryRow(
+		fmt.Sprintf("SELECT %s FROM products WHERE id = $1 FOR UPDATE", column),
+		assessment.ProductID,
+	).Scan(&oldScore)
+	if err == sql.ErrNoRows {
+		err = errors.New("product not found")
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get current score: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO product_score_assessments (product_id, dimension, score, criteria, note, assessor_id, assessed_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)
+		ON CONFLICT (product_id, dimension) DO UPDATE
+		SET score = EXCLUDED.score, criteria = EXCLUDED.criteria, note = EXCLUDED.note,
+			assessor_id = EXCLUDED.assessor_id, assessed_at = EXCLUDED.assessed_at
+	`,
+		assessment.ProductID,
+		assessment.Dimension,
+		assessment.Score,
+		criteria,
+		assessment.Note,
+		assessment.AssessorID,
+		assessment.AssessedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save score assessment: %w", err)
+	}
+
+	_, err = tx.Exec(fmt.Sprintf("UPDATE products SET %s = $2 WHERE id = $1", column), assessment.ProductID, assessment.Score)
+	if err != nil {
+		return fmt.Errorf("failed to update product score: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO product_score_changes (id, product_id, dimension, old_score, new_score, criteria, note, assessor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9)
+	`,
+		generateID(),
+		assessment.ProductID,
+		assessment.Dimension,
+		oldScore,
+		assessment.Score,
+		criteria,
+		assessment.Note,
+		assessment.AssessorID,
+		assessment.AssessedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record score change: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetScoreHistory returns a product's score changes, newest first, optionally
+// limited to one dimension
+func (r *PostgresRepository) GetScoreHistory(productID, dimension string, limit int) ([]models.ScoreChange, error) {
+	rows, err := r.db.Query(`
+		SELECT id, product_id, dimension, old_score, new_score, criteria, COALESCE(note, ''), assessor_id, created_at
+		FROM product_score_changes
+		WHERE product_id = $1 AND ($2 = '' OR dimension = $2)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, productID, dimension, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get score history: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []models.ScoreChange{}
+	for rows.Next() {
+		var change models.ScoreChange
+		var criteria []byte
+		err := rows.Scan(
+			&change.ID,
+			&change.ProductID,
+			&change.Dimension,
+			&change.OldScore,
+			&change.NewScore,
+			&criteria,
+			&change.Note,
+			&change.AssessorID,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan score change: %w", err)
+		}
+		if err := json.Unmarshal(criteria, &change.Criteria); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal score criteria: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
@@ -0,0 +1,490 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// tagColumns lists the tag columns in the order tagFields expects.
+// Queries must alias the tags table as t.
+const tagColumns = `t.id, t.name, t.slug, COALESCE(t.description, ''), t.created_at, t.updated_at`
+
+// tagFields returns scan destinations for tagColumns
+func tagFields(tag *models.Tag) []interface{} {
+	return []interface{}{
+		&tag.ID,
+		&tag.Name,
+		&tag.Slug,
+		&tag.Description,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	}
+}
+
+// tagMatch matches a tag by ID, slug or alias against the given placeholder
+const tagMatch = `(t.id = %[1]s OR t.slug = %[1]s OR t.id = (SELECT ta.tag_id FROM tag_aliases ta WHERE ta.alias = %[1]s))`
+
+// GetTags returns all tags with their aliases and the number of approved products carrying each
+func (r *PostgresRepository) GetTags() ([]models.Tag, error) {
+	query := `
+		SELECT ` + tagColumns + `,
+		       COALESCE(pt.product_count, 0),
+		       COALESCE((SELECT ARRAY_AGG(ta.alias ORDER BY ta.alias) FROM tag_aliases ta WHERE ta.tag_id = t.id), '{}')
+		FROM tags t
+		LEFT JOIN (
+			SELECT ptag.tag_id, COUNT(*) AS product_count
+			FROM product_tags ptag
+			JOIN products p ON p.id = ptag.product_id AND p.approved = true
+			GROUP BY ptag.tag_id
+		) pt ON pt.tag_id = t.id
+		ORDER BY COALESCE(pt.product_count, 0) DESC, t.name ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		err := rows.Scan(append(tagFields(&tag), &tag.ProductCount, pq.Array(&tag.Aliases))...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// GetTag returns a tag by its ID, slug or one of its aliases
+func (r *PostgresRepository) GetTag(idOrSlug string) (*models.Tag, error) {
+	query := `
+		SELECT ` + tagColumns + `,
+		       (SELECT COUNT(*) FROM product_tags ptag
+		        JOIN products p ON p.id = ptag.product_id AND p.approved = true
+		        WHERE ptag.tag_id = t.id),
+		       COALESCE((SELECT ARRAY_AGG(ta.alias ORDER BY ta.alias) FROM tag_aliases ta WHERE ta.tag_id = t.id), '{}')
+		FROM tags t
+		WHERE ` + fmt.Sprintf(tagMatch, "$1") + `
+		LIMIT 1
+	`
+
+	tag := &models.Tag{}
+	err := r.db.QueryRow(query, idOrSlug).Scan(append(tagFields(tag), &tag.ProductCount, pq.Array(&tag.Aliases))...)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("tag not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// SearchTags returns tags whose slug, name or an alias starts with prefix,
+// most used first
+func (r *PostgresRepository) SearchTags(prefix string, limit int) ([]models.Tag, error) {
+	query := `
+		SELECT ` + tagColumns + `,
+		       (SELECT COUNT(*) FROM product_tags ptag WHERE ptag.tag_id = t.id) AS usage
+		FROM tags t
+		WHERE t.slug LIKE $1 || '%'
+		   OR t.name ILIKE $1 || '%'
+		   OR EXISTS (SELECT 1 FROM tag_aliases ta WHERE ta.tag_id = t.id AND ta.alias LIKE $1 || '%')
+		ORDER BY usage DESC, t.name ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		err := rows.Scan(append(tagFields(&tag), &tag.ProductCount)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// ensureTagsTx resolves each new tag, one without an ID, by slug or alias
+// within a product transaction, creating tags that do not exist yet, and
+// returns the canonical tags in input order without duplicates. Tags that
+// already have an ID, such as those of a stored snapshot, are kept as they
+// are, so tags deleted since are not brought back.
+func ensureTagsTx(tx *sql.Tx, tags []models.Tag, createdBy *string) ([]models.Tag, error) {
+	resolved := []models.Tag{}
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if tag.ID != "" {
+			if !seen[tag.ID] {
+				seen[tag.ID] = true
+				resolved = append(resolved, tag)
+			}
+			continue
+		}
+
+		// Create the tag unless its slug is taken by a tag or an alias
+		_, err := tx.Exec(`
+			INSERT INTO tags (id, name, slug, created_by, created_at, updated_at)
+			SELECT $1, $2, $3, $4, $5, $5
+			WHERE NOT EXISTS (SELECT 1 FROM tag_aliases WHERE alias = $3)
+			ON CONFLICT (slug) DO NOTHING
+		`, generateID(), tag.Name, tag.Slug, createdBy, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tag: %w", err)
+		}
+
+		var canonical models.Tag
+		err = tx.QueryRow(`
+			SELECT `+tagColumns+` FROM tags t WHERE `+fmt.Sprintf(tagMatch, "$1")+` LIMIT 1
+		`, tag.Slug).Scan(tagFields(&canonical)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tag %s: %w", tag.Slug, err)
+		}
+
+		if !seen[canonical.ID] {
+			seen[canonical.ID] = true
+			resolved = append(resolved, canonical)
+		}
+	}
+
+	return resolved, nil
+}
+
+// setProductTagsTx replaces a product's tags within a transaction. Tags are
+// matched by ID, slug or alias so that snapshots taken before a tag was
+// merged into another still resolve; tags that no longer exist are dropped.
+func setProductTagsTx(tx *sql.Tx, productID string, tags []models.Tag) error {
+	_, err := tx.Exec("DELETE FROM product_tags WHERE product_id = $1", productID)
+	if err != nil {
+		return fmt.Errorf("failed to clear product tags: %w", err)
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(tags))
+	slugs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		ids = append(ids, tag.ID)
+		slugs = append(slugs, tag.Slug)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO product_tags (product_id, tag_id)
+		SELECT DISTINCT $1, t.id FROM tags t
+		WHERE t.id = ANY($2) OR t.slug = ANY($3)
+		   OR t.id IN (SELECT ta.tag_id FROM tag_aliases ta WHERE ta.alias = ANY($3))
+		ON CONFLICT DO NOTHING
+	`, productID, pq.Array(ids), pq.Array(slugs))
+	if err != nil {
+		return fmt.Errorf("failed to link product to tags: %w", err)
+	}
+
+	return nil
+}
+
+// loadProductTags populates the tags of a set of products
+func (r *PostgresRepository) loadProductTags(q queryer, ids []string, byID map[string]*models.Product) error {
+	query := `
+		SELECT pt.product_id, ` + tagColumns + `
+		FROM tags t
+		JOIN product_tags pt ON t.id = pt.tag_id
+		WHERE pt.product_id = ANY($1)
+		ORDER BY t.name
+	`
+
+	rows, err := q.Query(query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get product tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID string
+		var tag models.Tag
+		err := rows.Scan(append([]interface{}{&productID}, tagFields(&tag)...)...)
+		if err != nil {
+			return fmt.Errorf("failed to scan tag: %w", err)
+		}
+		if product, ok := byID[productID]; ok {
+			product.Tags = append(product.Tags, tag)
+		}
+	}
+
+	return rows.Err()
+}
+
+// UpdateTag updates a tag's name, slug and description
+func (r *PostgresRepository) UpdateTag(tag *models.Tag, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var aliased bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM tag_aliases WHERE alias = $1 AND tag_id <> $2)", tag.Slug, tag.ID).Scan(&aliased)
+	if err != nil {
+		return fmt.Errorf("failed to check tag aliases: %w", err)
+	}
+	if aliased {
+		err = errors.New("tag slug is already an alias of another tag")
+		return err
+	}
+
+	// A tag may take over one of its own aliases as its slug
+	_, err = tx.Exec("DELETE FROM tag_aliases WHERE alias = $1 AND tag_id = $2", tag.Slug, tag.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update tag aliases: %w", err)
+	}
+
+	result, err := tx.Exec(`
+		UPDATE tags SET name = $2, slug = $3, description = $4, updated_at = NOW()
+		WHERE id = $1
+	`, tag.ID, tag.Name, tag.Slug, tag.Description)
+	if err != nil {
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check tag update: %w", err)
+	}
+	if affected == 0 {
+		err = errors.New("tag not found")
+		return err
+	}
+
+	details, _ := json.Marshal(tag)
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "update_tag",
+		EntityType: "tag",
+		EntityID:   tag.ID,
+		Details:    details,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTag deletes a tag, removing it from every product
+func (r *PostgresRepository) DeleteTag(id, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.Exec("DELETE FROM tags WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check tag delete: %w", err)
+	}
+	if affected == 0 {
+		err = errors.New("tag not found")
+		return err
+	}
+
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "delete_tag",
+		EntityType: "tag",
+		EntityID:   id,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AddTagAlias maps alias onto a tag. If a separate tag already uses the alias
+// as its slug, that tag is folded in: its products and aliases move to the
+// canonical tag and it is deleted. It returns the number of products re-tagged.
+func (r *PostgresRepository) AddTagAlias(tagID, alias, actorID string) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var canonicalSlug string
+	err = tx.QueryRow("SELECT slug FROM tags WHERE id = $1 FOR UPDATE", tagID).Scan(&canonicalSlug)
+	if err == sql.ErrNoRows {
+		err = errors.New("tag not found")
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tag: %w", err)
+	}
+	if canonicalSlug == alias {
+		err = errors.New("alias is already the tag's slug")
+		return 0, err
+	}
+
+	var existingTagID string
+	err = tx.QueryRow("SELECT tag_id FROM tag_aliases WHERE alias = $1", alias).Scan(&existingTagID)
+	if err == nil {
+		err = errors.New("alias is already taken")
+		return 0, err
+	}
+	if err != sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to check tag aliases: %w", err)
+	}
+
+	var moved int64
+	var duplicateID string
+	err = tx.QueryRow("SELECT id FROM tags WHERE slug = $1", alias).Scan(&duplicateID)
+	switch {
+	case err == sql.ErrNoRows:
+		// No competing tag, just record the alias
+	case err != nil:
+		return 0, fmt.Errorf("failed to check duplicate tag: %w", err)
+	default:
+		var result sql.Result
+		result, err = tx.Exec(`
+			INSERT INTO product_tags (product_id, tag_id)
+			SELECT product_id, $2 FROM product_tags WHERE tag_id = $1
+			ON CONFLICT DO NOTHING
+		`, duplicateID, tagID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to move tagged products: %w", err)
+		}
+		moved, err = result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to check moved products: %w", err)
+		}
+
+		_, err = tx.Exec("UPDATE tag_aliases SET tag_id = $2 WHERE tag_id = $1", duplicateID, tagID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to move tag aliases: %w", err)
+		}
+
+		_, err = tx.Exec("DELETE FROM tags WHERE id = $1", duplicateID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete merged tag: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO tag_aliases (alias, tag_id, created_by, created_at)
+		VALUES ($1, $2, $3, $4)
+	`, alias, tagID, actorID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to create tag alias: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"alias":          alias,
+		"merged_tag_id":  duplicateID,
+		"products_moved": moved,
+	})
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "add_tag_alias",
+		EntityType: "tag",
+		EntityID:   tagID,
+		Details:    details,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return int(moved), nil
+}
+
+// RemoveTagAlias removes an alias from a tag
+func (r *PostgresRepository) RemoveTagAlias(tagID, alias, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.Exec("DELETE FROM tag_aliases WHERE tag_id = $1 AND alias = $2", tagID, alias)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag alias: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check tag alias delete: %w", err)
+	}
+	if affected == 0 {
+		err = errors.New("tag alias not found")
+		return err
+	}
+
+	details, _ := json.Marshal(map[string]string{"alias": alias})
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "remove_tag_alias",
+		EntityType: "tag",
+		EntityID:   tagID,
+		Details:    details,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// SaveTwitterChallenge stores a user's Twitter link challenge, replacing any
+// they already had
+func (r *PostgresRepository) SaveTwitterChallenge(challenge *models.TwitterChallenge) error {
+	challenge.CreatedAt = time.Now()
+
+	_, err := r.db.Exec(`
+		INSERT INTO twitter_challenges (user_id, handle, code, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET handle = EXCLUDED.handle, code = EXCLUDED.code,
+			expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at
+	`, challenge.UserID, challenge.Handle, challenge.Code, challenge.ExpiresAt, challenge.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save Twitter challenge: %w", err)
+	}
+
+	return nil
+}
+
+// GetTwitterChallenge returns a user's Twitter link challenge
+func (r *PostgresRepository) GetTwitterChallenge(userID string) (*models.TwitterChallenge, error) {
+	challenge := &models.TwitterChallenge{}
+	err := r.db.QueryRow(`
+		SELECT user_id, handle, code, expires_at, created_at
+		FROM twitter_challenges
+		WHERE user_id = $1
+	`, userID).Scan(
+		&challenge.UserID,
+		&challenge.Handle,
+		&challenge.Code,
+		&challenge.ExpiresAt,
+		&challenge.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("no Twitter link challenge is pending")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Twitter challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// LinkTwitterHandle records a handle the user has proven they control and
+// uses up their challenge. The handle is taken from any other user it was
+// linked to, since the proof is newer than theirs.
+func (r *PostgresRepository) LinkTwitterHandle(userID, handle string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(`
+		UPDATE users
+		SET twitter_handle = NULL, twitter_verified_at = NULL, updated_at = NOW()
+		WHERE LOWER(twitter_handle) = LOWER($1) AND id <> $2
+	`, handle, userID)
+	if err != nil {
+		return fmt.Errorf("failed to release Twitter handle: %w", err)
+	}
+
+	var result sql.Result
+	result, err = tx.Exec(`
+		UPDATE users
+		SET twitter_handle = $2, twitter_verified_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, userID, handle)
+	if err != nil {
+		return fmt.Errorf("failed to link Twitter handle: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		err = errors.New("user not found")
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM twitter_challenges WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to clear Twitter challenge: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UnlinkTwitterHandle removes a user's Twitter handle and any pending challenge
+func (r *PostgresRepository) UnlinkTwitterHandle(userID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var result sql.Result
+	result, err = tx.Exec(`
+		UPDATE users
+		SET twitter_handle = NULL, twitter_verified_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to unlink Twitter handle: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		err = errors.New("user not found")
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM twitter_challenges WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to clear Twitter challenge: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// GetUsersForENSRefresh returns up to limit users whose ENS name was last
+// looked up before checkedBefore and who were not last attempted after
+// attemptedBefore, those attempted longest ago first. Merged accounts are
+// skipped.
+func (r *PostgresRepository) GetUsersForENSRefresh(checkedBefore, attemptedBefore time.Time, limit int) ([]models.User, error) {
+	rows, err := r.db.Query(`
+		SELECT `+userColumns+`
+		FROM users u
+		WHERE u.merged_into IS NULL
+			AND (u.ens_checked_at IS NULL OR u.ens_checked_at < $1)
+			AND (u.ens_attempted_at IS NULL OR u.ens_attempted_at < $2)
+		ORDER BY u.ens_attempted_at NULLS FIRST
+		LIMIT $3
+	`, checkedBefore, attemptedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users for ENS refresh: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, *user)
+	}
+
+	return users, rows.Err()
+}
+
+// SetUserENS caches the ENS name and avatar found for a user, either of which
+// may be empty, and records when they were looked up
+func (r *PostgresRepository) SetUserENS(userID, name, avatar string) error {
+	result, err := r.db.Exec(`
+		UPDATE users
+		SET ens_name = NULLIF($2, ''), ens_avatar = NULLIF($3, ''), ens_checked_at = $4, ens_attempted_at = $4
+		WHERE id = $1
+	`, userID, name, avatar, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save user ENS: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// SetUserENSAttempt records a failed ENS lookup for a user, leaving any
+// cached name and avatar in place
+func (r *PostgresRepository) SetUserENSAttempt(userID string) error {
+	_, err := r.db.Exec("UPDATE users SET ens_attempted_at = $2 WHERE id = $1", userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record ENS lookup: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// UpdateUserProfile saves a user's display name, avatar, bio and privacy settings
+func (r *PostgresRepository) UpdateUserProfile(user *models.User) error {
+	result, err := r.db.Exec(`
+		UPDATE users
+		SET display_name = NULLIF($2, ''), avatar_url = NULLIF($3, ''), bio = NULLIF($4, ''),
+			show_submissions = $5, show_edits = $6, show_upvotes = $7, updated_at = $8
+		WHERE id = $1
+	`,
+		user.ID,
+		user.DisplayName,
+		user.AvatarURL,
+		user.Bio,
+		user.Privacy.ShowSubmissions,
+		user.Privacy.ShowEdits,
+		user.Privacy.ShowUpvotes,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update user profile: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// GetUserEdits returns the edits a user has made to products in the given
+// lifecycle states, newest first. Initial versions count as submissions, not edits.
+func (r *PostgresRepository) GetUserEdits(userID string, statuses []string, page, perPage int) ([]models.UserEdit, int, error) {
+	offset := (page - 1) * perPage
+
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM product_revisions pr
+		JOIN products p ON p.id = pr.product_id
+		WHERE pr.editor_id = $1 AND pr.revision_number > 1 AND p.status = ANY($2)
+	`, userID, pq.Array(statuses)).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count user edits: %w", err)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT p.id, p.title, p.slug, pr.revision_number, pr.edit_summary, pr.reverted_in IS NOT NULL, pr.created_at
+		FROM product_revisions pr
+		JOIN products p ON p.id = pr.product_id
+		WHERE pr.editor_id = $1 AND pr.revision_number > 1 AND p.status = ANY($2)
+		ORDER BY pr.created_at DESC
+		LIMIT $3 OFFSET $4
+	`, userID, pq.Array(statuses), perPage, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get user edits: %w", err)
+	}
+	defer rows.Close()
+
+	edits := []models.UserEdit{}
+	for rows.Next() {
+		var edit models.UserEdit
+		err := rows.Scan(
+			&edit.ProductID,
+			&edit.ProductTitle,
+			&edit.ProductSlug,
+			&edit.RevisionNumber,
+			&edit.EditSummary,
+			&edit.Reverted,
+			&edit.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user edit: %w", err)
+		}
+		edits = append(edits, edit)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate user edits: %w", err)
+	}
+
+	return edits, total, nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// recordSanctionTx checks that a sanction update matched a user and writes
+// it to the audit log within a transaction
+func (r *PostgresRepository) recordSanctionTx(tx *sql.Tx, result sql.Result, userID, action, actorID string, details map[string]interface{}) error {
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("user not found")
+	}
+
+	data, _ := json.Marshal(details)
+	return r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     action,
+		EntityType: "user",
+		EntityID:   userID,
+		Details:    data,
+	})
+}
+
+// BanUser bans a user, until expiresAt for a suspension or for good when it
+// is nil, and writes it to the audit log. probationUntil, when set, puts the
+// user on probation after a suspension.
+func (r *PostgresRepository) BanUser(userID, reason string, expiresAt, probationUntil *time.Time, actorID string) error {
+	action := "ban_user"
+	if expiresAt != nil {
+		action = "suspend_user"
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.Exec(`
+		UPDATE users SET banned_at = $2, ban_reason = NULLIF($3, ''), banned_by = $4, ban_expires_at = $5,
+			probation_until = COALESCE($6, probation_until),
+			probation_reason = CASE WHEN $6::timestamptz IS NULL THEN probation_reason ELSE NULLIF($3, '') END,
+			updated_at = $2
+		WHERE id = $1
+	`, userID, time.Now(), reason, actorID, expiresAt, probationUntil)
+	if err != nil {
+		return fmt.Errorf("failed to ban user: %w", err)
+	}
+
+	err = r.recordSanctionTx(tx, result, userID, action, actorID, map[string]interface{}{
+		"reason":          reason,
+		"expires_at":      expiresAt,
+		"probation_until": probationUntil,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// LiftUserBan ends a user's ban or suspension early and writes it to the
+// audit log. probationUntil, when set, puts the user on probation.
+func (r *PostgresRepository) LiftUserBan(userID, reason string, probationUntil *time.Time, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.Exec(`
+		UPDATE users SET banned_at = NULL, ban_reason = NULL, banned_by = NULL, ban_expires_at = NULL,
+			probation_until = COALESCE($2, probation_until),
+			probation_reason = CASE WHEN $2::timestamptz IS NULL THEN probation_reason ELSE NULLIF($3, '') END,
+			updated_at = $4
+		WHERE id = $1
+	`, userID, probationUntil, reason, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to reinstate user: %w", err)
+	}
+
+	err = r.recordSanctionTx(tx, result, userID, "reinstate_user", actorID, map[string]interface{}{
+		"reason":          reason,
+		"probation_until": probationUntil,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SetUserProbation puts a user on probation until the given time, or ends
+// their probation when until is nil, and writes it to the audit log
+func (r *PostgresRepository) SetUserProbation(userID string, until *time.Time, reason, actorID string) error {
+	action := "start_probation"
+	if until == nil {
+		action = "end_probation"
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.Exec(`
+		UPDATE users SET probation_until = $2,
+			probation_reason = CASE WHEN $2::timestamptz IS NULL THEN NULL ELSE NULLIF($3, '') END,
+			updated_at = $4
+		WHERE id = $1
+	`, userID, until, reason, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update probation: %w", err)
+	}
+
+	err = r.recordSanctionTx(tx, result, userID, action, actorID, map[string]interface{}{
+		"reason": reason,
+		"until":  until,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetSanctionedUsers returns users who are banned, suspended or on
+// probation, most recently changed first
+func (r *PostgresRepository) GetSanctionedUsers() ([]models.User, error) {
+	rows, err := r.db.Query(`
+		SELECT ` + userColumns + `
+		FROM users u
+		WHERE u.merged_into IS NULL
+			AND ((u.banned_at IS NOT NULL AND (u.ban_expires_at IS NULL OR u.ban_expires_at > NOW()))
+				OR u.probation_until > NOW())
+		ORDER BY u.updated_at DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sanctioned users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, *user)
+	}
+
+	return users, rows.Err()
+}
@@ -0,0 +1,426 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// GetUserWallets returns the wallets that sign in to a user's account, the
+// primary wallet first
+func (r *PostgresRepository) GetUserWallets(userID string) ([]models.UserWallet, error) {
+	rows, err := r.db.Query(`
+		SELECT w.address, LOWER(w.address) = LOWER(u.wallet_address), w.linked_at
+		FROM user_wallets w
+		JOIN users u ON u.id = w.user_id
+		WHERE w.user_id = $1
+		ORDER BY 2 DESC, w.linked_at, w.address
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user wallets: %w", err)
+	}
+	defer rows.Close()
+
+	wallets := []models.UserWallet{}
+	for rows.Next() {
+		var wallet models.UserWallet
+		if err := rows.Scan(&wallet.Address, &wallet.Primary, &wallet.LinkedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user wallet: %w", err)
+		}
+		wallets = append(wallets, wallet)
+	}
+
+	return wallets, rows.Err()
+}
+
+// SaveWalletLinkRequest stores a user's wallet link request, replacing any
+// they already had
+func (r *PostgresRepository) SaveWalletLinkRequest(request *models.WalletLinkRequest) error {
+	request.CreatedAt = time.Now()
+
+	_, err := r.db.Exec(`
+		INSERT INTO wallet_link_requests (user_id, address, merge, message, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET address = EXCLUDED.address, merge = EXCLUDED.merge, message = EXCLUDED.message,
+			expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at
+	`, request.UserID, request.Address, request.Merge, request.Message, request.ExpiresAt, request.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save wallet link request: %w", err)
+	}
+
+	return nil
+}
+
+// GetWalletLinkRequest returns a user's wallet link request
+func (r *PostgresRepository) GetWalletLinkRequest(userID string) (*models.WalletLinkRequest, error) {
+	request := &models.WalletLinkRequest{}
+	err := r.db.QueryRow(`
+		SELECT user_id, address, merge, message, expires_at, created_at
+		FROM wallet_link_requests
+		WHERE user_id = $1
+	`, userID).Scan(
+		&request.UserID,
+		&request.Address,
+		&request.Merge,
+		&request.Message,
+		&request.ExpiresAt,
+		&request.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("no wallet link request is pending")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet link request: %w", err)
+	}
+
+	return request, nil
+}
+
+// LinkWallet adds a wallet that belongs to no account to a user's account and
+// uses up their link request
+func (r *PostgresRepository) LinkWallet(userID, address string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(
+		"INSERT INTO user_wallets (address, user_id, linked_at) VALUES ($1, $2, NOW())",
+		address, userID,
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		err = errors.New("wallet belongs to another account")
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to link wallet: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM wallet_link_requests WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("failed to clear wallet link request: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UnlinkWallet removes a wallet other than the primary one from a user's account
+func (r *PostgresRepository) UnlinkWallet(userID, address string) error {
+	result, err := r.db.Exec(`
+		DELETE FROM user_wallets w
+		USING users u
+		WHERE u.id = w.user_id AND w.user_id = $1
+			AND LOWER(w.address) = LOWER($2) AND LOWER(w.address) <> LOWER(u.wallet_address)
+	`, userID, address)
+	if err != nil {
+		return fmt.Errorf("failed to unlink wallet: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("wallet not found")
+	}
+
+	return nil
+}
+
+// userReferences lists the columns that name a user as the author or actor
+// of something, moved wholesale when accounts merge. The audit log keeps
+// naming the merged account, which still exists, so the record stays as it was.
+var userReferences = []struct{ table, column string }{
+	{"products", "submitter_id"},
+	{"products", "last_editor_id"},
+	{"pending_edits", "user_id"},
+	{"product_revisions", "editor_id"},
+	{"product_revisions", "reverted_by"},
+	{"product_comments", "user_id"},
+	{"product_comments", "moderated_by"},
+	{"product_reviews", "moderated_by"},
+	{"product_status_changes", "actor_id"},
+	{"product_score_assessments", "assessor_id"},
+	{"product_score_changes", "assessor_id"},
+	{"moderation_cases", "assignee_id"},
+	{"moderation_cases", "resolved_by"},
+	{"tags", "created_by"},
+	{"tag_aliases", "created_by"},
+	{"vote_anomalies", "resolved_by"},
+	{"voided_upvotes", "user_id"},
+	{"voided_upvotes", "voided_by"},
+	{"users", "banned_by"},
+}
+
+// MergeUsers folds a duplicate account into the survivor. Wallets,
+// contributions and moderation history move. Upvotes, ratings and reports
+// move unless the survivor already upvoted, rated or reported the same
+// thing, and a review of a product the survivor also reviewed is hidden.
+// The survivor keeps its own profile, filling gaps from the duplicate, takes
+// the older creation date and the later probation. The duplicate stays as a
+// redirect to the survivor. It returns the number of wallets moved.
+func (r *PostgresRepository) MergeUsers(sourceID, targetID, reason, actorID string) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock both accounts so concurrent merges cannot interleave
+	var twitterHandle sql.NullString
+	var twitterVerifiedAt *time.Time
+	err = tx.QueryRow(
+		"SELECT twitter_handle, twitter_verified_at FROM users WHERE id = $1 AND merged_into IS NULL FOR UPDATE",
+		sourceID,
+	).Scan(&twitterHandle, &twitterVerifiedAt)
+	if err == sql.ErrNoRows {
+		err = errors.New("user not found")
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	var locked string
+	err = tx.QueryRow("SELECT id FROM users WHERE id = $1 AND merged_into IS NULL FOR UPDATE", targetID).Scan(&locked)
+	if err == sql.ErrNoRows {
+		err = errors.New("target user not found")
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock target user: %w", err)
+	}
+
+	result, err := tx.Exec("UPDATE user_wallets SET user_id = $2 WHERE user_id = $1", sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move wallets: %w", err)
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check moved wallets: %w", err)
+	}
+
+	// Upvotes: one per user, so votes by both accounts collapse into one
+	_, err = tx.Exec(`
+		UPDATE upvotes u SET user_id = $2
+		WHERE u.user_id = $1
+			AND NOT EXISTS (SELECT 1 FROM upvotes t WHERE t.user_id = $2 AND t.product_id = u.product_id)
+	`, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move upvotes: %w", err)
+	}
+	products, err := returnedIDsTx(tx, "DELETE FROM upvotes WHERE user_id = $1 RETURNING product_id", sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove duplicate upvotes: %w", err)
+	}
+	_, err = tx.Exec(`
+		UPDATE products p
+		SET upvote_count = (SELECT COUNT(*) FROM upvotes u WHERE u.product_id = p.id),
+			weighted_score = (SELECT COALESCE(SUM(u.weight), 0) FROM upvotes u WHERE u.product_id = p.id)
+		WHERE p.id = ANY($1)
+	`, pq.Array(products))
+	if err != nil {
+		return 0, fmt.Errorf("failed to recount upvotes: %w", err)
+	}
+
+	// Ratings likewise keep one per user and dimension
+	_, err = tx.Exec(`
+		UPDATE product_ratings pr SET user_id = $2
+		WHERE pr.user_id = $1
+			AND NOT EXISTS (
+				SELECT 1 FROM product_ratings t
+				WHERE t.user_id = $2 AND t.product_id = pr.product_id AND t.dimension = pr.dimension
+			)
+	`, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move ratings: %w", err)
+	}
+	products, err = returnedIDsTx(tx, "DELETE FROM product_ratings WHERE user_id = $1 RETURNING product_id", sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove duplicate ratings: %w", err)
+	}
+	for _, id := range products {
+		if err = r.refreshCommunityScoresTx(tx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	// Reviews keep one per user; the duplicate's review of a product the
+	// survivor also reviewed stays with the duplicate, hidden
+	_, err = tx.Exec(`
+		UPDATE product_reviews rv SET user_id = $2
+		WHERE rv.user_id = $1
+			AND NOT EXISTS (SELECT 1 FROM product_reviews t WHERE t.user_id = $2 AND t.product_id = rv.product_id)
+	`, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move reviews: %w", err)
+	}
+	products, err = returnedIDsTx(tx, `
+		UPDATE product_reviews
+		SET status = 'hidden', moderation_reason = 'Duplicate review from a merged account', moderated_at = NOW()
+		WHERE user_id = $1 AND status = 'visible'
+		RETURNING product_id
+	`, sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to hide duplicate reviews: %w", err)
+	}
+	for _, id := range products {
+		if err = refreshDiscussionCountsTx(tx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	// Reports keep one per user and case
+	_, err = tx.Exec(`
+		UPDATE reports rp SET reporter_id = $2
+		WHERE rp.reporter_id = $1
+			AND NOT EXISTS (SELECT 1 FROM reports t WHERE t.reporter_id = $2 AND t.case_id = rp.case_id)
+	`, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move reports: %w", err)
+	}
+	cases, err := returnedIDsTx(tx, "DELETE FROM reports WHERE reporter_id = $1 RETURNING case_id", sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove duplicate reports: %w", err)
+	}
+	_, err = tx.Exec(`
+		UPDATE moderation_cases mc
+		SET report_count = (SELECT COUNT(*) FROM reports rp WHERE rp.case_id = mc.id)
+		WHERE mc.id = ANY($1)
+	`, pq.Array(cases))
+	if err != nil {
+		return 0, fmt.Errorf("failed to recount reports: %w", err)
+	}
+
+	// Cases about the duplicate follow it, unless both accounts have one
+	// being worked, which stays put
+	_, err = tx.Exec(`
+		UPDATE moderation_cases mc SET target_id = $2
+		WHERE mc.target_type = 'user' AND mc.target_id = $1
+			AND NOT (
+				mc.status IN ('open', 'in_review')
+				AND EXISTS (
+					SELECT 1 FROM moderation_cases t
+					WHERE t.target_type = 'user' AND t.target_id = $2 AND t.status IN ('open', 'in_review')
+				)
+			)
+	`, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move moderation cases: %w", err)
+	}
+
+	for _, ref := range userReferences {
+		_, err = tx.Exec(fmt.Sprintf("UPDATE %s SET %s = $2 WHERE %s = $1", ref.table, ref.column, ref.column), sourceID, targetID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to move %s.%s: %w", ref.table, ref.column, err)
+		}
+	}
+
+	// Retire the duplicate, releasing its Twitter handle for the survivor, and
+	// point earlier merges into it at the survivor
+	_, err = tx.Exec(`
+		UPDATE users
+		SET merged_into = $2, merged_at = NOW(), twitter_handle = NULL, twitter_verified_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retire merged user: %w", err)
+	}
+	_, err = tx.Exec("UPDATE users SET merged_into = $2 WHERE merged_into = $1", sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move merge redirects: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE users t
+		SET display_name = COALESCE(t.display_name, s.display_name),
+			avatar_url = COALESCE(t.avatar_url, s.avatar_url),
+			bio = COALESCE(t.bio, s.bio),
+			twitter_handle = COALESCE(t.twitter_handle, $3),
+			twitter_verified_at = CASE WHEN t.twitter_handle IS NULL THEN $4 ELSE t.twitter_verified_at END,
+			probation_reason = CASE WHEN s.probation_until > COALESCE(t.probation_until, '-infinity')
+				THEN s.probation_reason ELSE t.probation_reason END,
+			probation_until = GREATEST(t.probation_until, s.probation_until),
+			created_at = LEAST(t.created_at, s.created_at),
+			updated_at = NOW()
+		FROM users s
+		WHERE t.id = $2 AND s.id = $1
+	`, sourceID, targetID, twitterHandle, twitterVerifiedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to merge user profile: %w", err)
+	}
+
+	// Pending link requests of either account may name a wallet that just
+	// moved, so both are cancelled
+	_, err = tx.Exec("DELETE FROM wallet_link_requests WHERE user_id IN ($1, $2)", sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear wallet link requests: %w", err)
+	}
+	_, err = tx.Exec("DELETE FROM twitter_challenges WHERE user_id = $1", sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear twitter challenges: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"merged_into":   targetID,
+		"wallets_moved": moved,
+		"reason":        reason,
+	})
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "merge_user",
+		EntityType: "user",
+		EntityID:   sourceID,
+		Details:    details,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return int(moved), nil
+}
+
+// returnedIDsTx runs a statement that returns one ID per changed row and
+// collects the distinct IDs
+func returnedIDsTx(tx *sql.Tx, query string, args ...interface{}) ([]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := map[string]bool{}
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// GetVoteActivitySince returns every upvote cast since the given time along
+// with the account and product creation times vote analysis needs
+func (r *PostgresRepository) GetVoteActivitySince(since time.Time) ([]models.VoteActivity, error) {
+	query := `
+		SELECT u.id, u.user_id, u.product_id, u.created_at, us.created_at, p.created_at
+		FROM upvotes u
+		JOIN users us ON us.id = u.user_id
+		JOIN products p ON p.id = u.product_id
+		WHERE u.created_at >= $1
+		ORDER BY u.product_id, u.created_at
+	`
+
+	rows, err := r.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote activity: %w", err)
+	}
+	defer rows.Close()
+
+	activity := []models.VoteActivity{}
+	for rows.Next() {
+		var vote models.VoteActivity
+		err := rows.Scan(
+			&vote.UpvoteID,
+			&vote.UserID,
+			&vote.ProductID,
+			&vote.VotedAt,
+			&vote.UserCreatedAt,
+			&vote.ProductCreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vote activity: %w", err)
+		}
+		activity = append(activity, vote)
+	}
+
+	return activity, rows.Err()
+}
+
+// SaveVoteAnomalies stores newly detected anomalies. An open anomaly for the
+// same kind and subject is refreshed in place, and anomalies whose votes were
+// all covered by a dismissed report are not raised again.
+func (r *PostgresRepository) SaveVoteAnomalies(anomalies []models.VoteAnomaly) (int, error) {
+	saved := 0
+	for _, anomaly := range anomalies {
+		result, err := r.db.Exec(`
+			INSERT INTO vote_anomalies (id, kind, subject, product_id, upvote_ids, user_ids, details, status, detected_at)
+			SELECT $1, $2, $3, $4, $5, $6, $7, 'open', $8
+			WHERE NOT EXISTS (
+				SELECT 1 FROM vote_anomalies
+				WHERE kind = $2 AND subject = $3 AND status = 'dismissed' AND upvote_ids @> $5
+			)
+			ON CONFLICT (kind, subject) WHERE status = 'open'
+			DO UPDATE SET upvote_ids = EXCLUDED.upvote_ids, user_ids = EXCLUDED.user_ids,
+				details = EXCLUDED.details, detected_at = EXCLUDED.detected_at
+		`,
+			generateID(),
+			anomaly.Kind,
+			anomaly.Subject,
+			anomaly.ProductID,
+			pq.Array(anomaly.UpvoteIDs),
+			pq.Array(anomaly.UserIDs),
+			nullableJSON(anomaly.Details),
+			time.Now(),
+		)
+		if err != nil {
+			return saved, fmt.Errorf("failed to save vote anomaly: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return saved, fmt.Errorf("failed to check vote anomaly result: %w", err)
+		}
+		saved += int(affected)
+	}
+
+	return saved, nil
+}
+
+// GetVoteAnomalies returns anomalies with the given status, newest first
+func (r *PostgresRepository) GetVoteAnomalies(status string) ([]models.VoteAnomaly, error) {
+	query := `
+		SELECT id, kind, subject, product_id, upvote_ids, user_ids, details, status,
+		       detected_at, resolved_at, resolved_by, resolution_note
+		FROM vote_anomalies
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY detected_at DESC
+	`
+
+	rows, err := r.db.Query(query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote anomalies: %w", err)
+	}
+	defer rows.Close()
+
+	anomalies := []models.VoteAnomaly{}
+	for rows.Next() {
+		var anomaly models.VoteAnomaly
+		var details []byte
+		err := rows.Scan(
+			&anomaly.ID,
+			&anomaly.Kind,
+			&anomaly.Subject,
+			&anomaly.ProductID,
+			pq.Array(&anomaly.UpvoteIDs),
+			pq.Array(&anomaly.UserIDs),
+			&details,
+			&anomaly.Status,
+			&anomaly.DetectedAt,
+			&anomaly.ResolvedAt,
+			&anomaly.ResolvedBy,
+			&anomaly.ResolutionNote,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vote anomaly: %w", err)
+		}
+		anomaly.Details = details
+		anomalies = append(anomalies, anomaly)
+	}
+
+	return anomalies, rows.Err()
+}
+
+// VoidAnomalyVotes removes every vote attached to the given open anomalies,
+// archiving them in voided_upvotes, adjusting product counters, closing the
+// anomalies and writing an audit log entry for each. It returns the number of
+// votes voided.
+func (r *PostgresRepository) VoidAnomalyVotes(anomalyIDs []string, actorID, reason string) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	voided := 0
+	now := time.Now()
+	for _, anomalyID := range anomalyIDs {
+		var upvoteIDs []string
+		err = tx.QueryRow(
+			"SELECT upvote_ids FROM vote_anomalies WHERE id = $1 AND status = 'open' FOR UPDATE",
+			anomalyID,
+		).Scan(pq.Array(&upvoteIDs))
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("open anomaly %s not found", anomalyID)
+			return 0, err
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to lock vote anomaly: %w", err)
+		}
+
+		// Archive and delete the votes, then take them off the product counters
+		var count int
+		err = tx.QueryRow(`
+			WITH deleted AS (
+				DELETE FROM upvotes WHERE id = ANY($1)
+				RETURNING id, user_id, product_id, weight, created_at
+			), archived AS (
+				INSERT INTO voided_upvotes (id, user_id, product_id, weight, created_at, voided_at, voided_by, anomaly_id, reason)
+				SELECT id, user_id, product_id, weight, created_at, $2, $3, $4, $5 FROM deleted
+				RETURNING product_id, weight
+			), totals AS (
+				SELECT product_id, COUNT(*) AS n, SUM(weight) AS w FROM archived GROUP BY product_id
+			), updated AS (
+				UPDATE products p
+				SET upvote_count = GREATEST(p.upvote_count - totals.n, 0),
+				    weighted_score = GREATEST(p.weighted_score - totals.w, 0)
+				FROM totals
+				WHERE p.id = totals.product_id
+				RETURNING p.id
+			)
+			SELECT COALESCE(SUM(n), 0) FROM totals
+		`, pq.Array(upvoteIDs), now, actorID, anomalyID, reason).Scan(&count)
+		if err != nil {
+			return 0, fmt.Errorf("failed to void votes: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			UPDATE vote_anomalies
+			SET status = 'voided', resolved_at = $2, resolved_by = $3, resolution_note = $4
+			WHERE id = $1
+		`, anomalyID, now, actorID, reason)
+		if err != nil {
+			return 0, fmt.Errorf("failed to close vote anomaly: %w", err)
+		}
+
+		details, _ := json.Marshal(map[string]interface{}{
+			"reason":       reason,
+			"upvote_ids":   upvoteIDs,
+			"votes_voided": count,
+		})
+		err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+			ActorID:    &actorID,
+			Action:     "void_votes",
+			EntityType: "vote_anomaly",
+			EntityID:   anomalyID,
+			Details:    details,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		voided += count
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return voided, nil
+}
+
+// DismissVoteAnomaly closes an open anomaly without touching its votes
+func (r *PostgresRepository) DismissVoteAnomaly(anomalyID, actorID, reason string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.Exec(`
+		UPDATE vote_anomalies
+		SET status = 'dismissed', resolved_at = $2, resolved_by = $3, resolution_note = $4
+		WHERE id = $1 AND status = 'open'
+	`, anomalyID, time.Now(), actorID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to dismiss vote anomaly: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check dismiss result: %w", err)
+	}
+	if affected == 0 {
+		err = errors.New("open anomaly not found")
+		return err
+	}
+
+	details, _ := json.Marshal(map[string]string{"reason": reason})
+	err = r.createAuditLogEntryTx(tx, &models.AuditLogEntry{
+		ActorID:    &actorID,
+		Action:     "dismiss_anomaly",
+		EntityType: "vote_anomaly",
+		EntityID:   anomalyID,
+		Details:    details,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
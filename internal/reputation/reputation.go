@@ -0,0 +1,67 @@
+package reputation
+
+import (
+	"github.com/wesjorgensen/EthAppList/backend/internal/config"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// Points awarded, or deducted when negative, for each contribution
+const (
+	ApprovedSubmissionPoints = 10
+	AcceptedEditPoints       = 2
+	UpvoteReceivedPoints     = 1
+	UsefulReportPoints       = 3
+	RevertedEditPoints       = -5
+	RejectedEditPoints       = -2
+)
+
+// Privileges unlocked by reputation
+const (
+	DirectEdit = "direct_edit" // product edits apply without review
+	Revert     = "revert"      // products may be reverted to an earlier revision
+)
+
+// Score totals the points for a user's contributions. It may be negative.
+func Score(counts models.ReputationCounts) int {
+	return counts.ApprovedSubmissions*ApprovedSubmissionPoints +
+		counts.AcceptedEdits*AcceptedEditPoints +
+		counts.UpvotesReceived*UpvoteReceivedPoints +
+		counts.UsefulReports*UsefulReportPoints +
+		counts.RevertedEdits*RevertedEditPoints +
+		counts.RejectedEdits*RejectedEditPoints
+}
+
+// Privileges lists every privilege with its configured threshold, marking
+// those a score unlocks
+func Privileges(cfg *config.Config, score int) []models.ReputationPrivilege {
+	privileges := []models.ReputationPrivilege{
+		{Name: DirectEdit, Description: "Product edits apply without review", Threshold: cfg.ReputationDirectEdit},
+		{Name: Revert, Description: "Revert products to an earlier revision", Threshold: cfg.ReputationRevert},
+	}
+	for i := range privileges {
+		privileges[i].Unlocked = score >= privileges[i].Threshold
+	}
+	return privileges
+}
+
+// Threshold returns the reputation a privilege needs
+func Threshold(cfg *config.Config, privilege string) int {
+	switch privilege {
+	case DirectEdit:
+		return cfg.ReputationDirectEdit
+	case Revert:
+		return cfg.ReputationRevert
+	}
+	return 0
+}
+
+// Evaluate builds a user's reputation from their contribution counts
+func Evaluate(cfg *config.Config, userID string, counts models.ReputationCounts) *models.Reputation {
+	score := Score(counts)
+	return &models.Reputation{
+		UserID:     userID,
+		Score:      score,
+		Counts:     counts,
+		Privileges: Privileges(cfg, score),
+	}
+}
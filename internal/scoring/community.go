@@ -0,0 +1,72 @@
+package scoring
+
+import (
+	"math"
+	"sort"
+)
+
+// CommunityParams tunes how community ratings are aggregated
+type CommunityParams struct {
+	PriorMean   float64 // rating assumed before anyone has rated
+	PriorWeight float64 // how many ratings the prior counts as
+	Trim        float64 // fraction of ratings dropped from each end for the trimmed mean
+}
+
+// Community summarizes the community ratings of one score dimension
+type Community struct {
+	Count       int
+	Mean        float64
+	TrimmedMean float64
+	Bayesian    float64
+}
+
+// Summarize aggregates ratings between 0 and 1. The Bayesian average pulls
+// dimensions with few ratings towards the prior, so a handful of votes
+// cannot swing a score to an extreme.
+func Summarize(values []float64, params CommunityParams) Community {
+	summary := Community{
+		Count:    len(values),
+		Bayesian: round(params.PriorMean),
+	}
+	if len(values) == 0 {
+		return summary
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+
+	summary.Mean = round(sum / float64(len(values)))
+	summary.TrimmedMean = round(TrimmedMean(values, params.Trim))
+	summary.Bayesian = round((sum + params.PriorMean*params.PriorWeight) / (float64(len(values)) + params.PriorWeight))
+	return summary
+}
+
+// TrimmedMean averages values after dropping the given fraction of the
+// lowest and highest values, always keeping at least one
+func TrimmedMean(values []float64, trim float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	drop := int(math.Floor(float64(len(sorted)) * trim))
+	if 2*drop >= len(sorted) {
+		drop = (len(sorted) - 1) / 2
+	}
+	kept := sorted[drop : len(sorted)-drop]
+
+	var sum float64
+	for _, value := range kept {
+		sum += value
+	}
+	return sum / float64(len(kept))
+}
+
+// round keeps scores at two decimals, like assessed scores
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
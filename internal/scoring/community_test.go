@@ -0,0 +1,74 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTrimmedMean(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		trim   float64
+		want   float64
+	}{
+		{"empty", nil, 0.1, 0},
+		{"no trim", []float64{0, 0.5, 1}, 0, 0.5},
+		{"outliers dropped", []float64{0, 0.6, 0.6, 0.6, 1}, 0.2, 0.6},
+		{"unsorted input", []float64{1, 0.6, 0, 0.6, 0.6}, 0.2, 0.6},
+		{"fraction rounds down", []float64{0, 0.5, 1}, 0.2, 0.5},
+		{"keeps the middle value", []float64{0.1, 0.2, 0.9}, 0.5, 0.2},
+		{"keeps the middle pair", []float64{0, 0.4, 0.6, 1}, 0.5, 0.5},
+		{"single value", []float64{0.7}, 0.5, 0.7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TrimmedMean(tt.values, tt.trim); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("TrimmedMean(%v, %v) = %v, want %v", tt.values, tt.trim, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrimmedMeanLeavesInputUnsorted(t *testing.T) {
+	values := []float64{1, 0, 0.5}
+	TrimmedMean(values, 0.1)
+	if values[0] != 1 || values[1] != 0 || values[2] != 0.5 {
+		t.Errorf("TrimmedMean reordered its input: %v", values)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	params := CommunityParams{PriorMean: 0.5, PriorWeight: 2, Trim: 0.2}
+
+	tests := []struct {
+		name   string
+		values []float64
+		want   Community
+	}{
+		{
+			name:   "no ratings holds the prior",
+			values: nil,
+			want:   Community{Count: 0, Bayesian: 0.5},
+		},
+		{
+			name:   "one rating pulled towards the prior",
+			values: []float64{1},
+			want:   Community{Count: 1, Mean: 1, TrimmedMean: 1, Bayesian: 0.67},
+		},
+		{
+			name:   "many ratings outweigh the prior",
+			values: []float64{0, 0.9, 0.9, 0.9, 0.9, 0.9, 0.9, 0.9, 0.9, 1},
+			want:   Community{Count: 10, Mean: 0.82, TrimmedMean: 0.9, Bayesian: 0.77},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summarize(tt.values, params); got != tt.want {
+				t.Errorf("Summarize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
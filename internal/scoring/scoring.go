@@ -0,0 +1,125 @@
+package scoring
+
+import "errors"
+
+// Score dimensions, matching the product score fields
+const (
+	Security         = "security"
+	UX               = "ux"
+	Decentralization = "decent"
+	Vibes            = "vibes"
+)
+
+// Unassessed is the neutral score a dimension holds until it is assessed
+const Unassessed = 0.5
+
+// Criterion is one thing assessors rate when scoring a dimension
+type Criterion struct {
+	Key         string  `json:"key"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Weight      float64 `json:"weight"` // relative importance within the dimension
+}
+
+// Rubric lists the criteria that make up a score dimension
+type Rubric struct {
+	Dimension   string      `json:"dimension"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Criteria    []Criterion `json:"criteria"`
+}
+
+// Rubrics holds the rubric for every dimension
+var Rubrics = []Rubric{
+	{
+		Dimension:   Security,
+		Name:        "Security",
+		Description: "How well users' funds and data are protected",
+		Criteria: []Criterion{
+			{Key: "audits", Name: "Audits", Description: "Independent audits cover the deployed code and their findings are resolved", Weight: 3},
+			{Key: "open_source", Name: "Open source", Description: "Contracts are verified and the source is public", Weight: 2},
+			{Key: "admin_controls", Name: "Admin controls", Description: "Upgrades and privileged actions sit behind timelocks or multisigs", Weight: 2},
+			{Key: "incident_history", Name: "Incident history", Description: "No unresolved exploits, and past incidents were handled openly", Weight: 2},
+			{Key: "bug_bounty", Name: "Bug bounty", Description: "An active bounty rewards responsible disclosure", Weight: 1},
+		},
+	},
+	{
+		Dimension:   UX,
+		Name:        "User experience",
+		Description: "How easy the product is to use well",
+		Criteria: []Criterion{
+			{Key: "onboarding", Name: "Onboarding", Description: "New users can get started without outside help", Weight: 2},
+			{Key: "transaction_clarity", Name: "Transaction clarity", Description: "Users understand what they sign and what it costs", Weight: 2},
+			{Key: "reliability", Name: "Reliability", Description: "The product works consistently and fails gracefully", Weight: 2},
+			{Key: "documentation", Name: "Documentation", Description: "Docs are complete and current", Weight: 1},
+			{Key: "accessibility", Name: "Accessibility", Description: "Usable on mobile and with assistive technology", Weight: 1},
+		},
+	},
+	{
+		Dimension:   Decentralization,
+		Name:        "Decentralization",
+		Description: "How far the product depends on trusted parties",
+		Criteria: []Criterion{
+			{Key: "custody", Name: "Custody", Description: "Users keep control of their own assets", Weight: 3},
+			{Key: "upgradeability", Name: "Upgradeability", Description: "No single party can change the rules unilaterally", Weight: 2},
+			{Key: "governance", Name: "Governance", Description: "Decisions are made openly by a broad set of stakeholders", Weight: 2},
+			{Key: "frontend", Name: "Frontend", Description: "The interface can be self-hosted or is served from decentralized storage", Weight: 1},
+			{Key: "infrastructure", Name: "Infrastructure", Description: "Works without a specific operator's RPC, sequencer or API", Weight: 1},
+		},
+	},
+	{
+		Dimension:   Vibes,
+		Name:        "Vibes",
+		Description: "How the product and its team fit the ecosystem's values",
+		Criteria: []Criterion{
+			{Key: "community", Name: "Community", Description: "An active, constructive community", Weight: 1},
+			{Key: "team_transparency", Name: "Team transparency", Description: "The team communicates openly about plans and problems", Weight: 1},
+			{Key: "track_record", Name: "Track record", Description: "The team has shipped what it promised", Weight: 1},
+			{Key: "public_goods", Name: "Public goods", Description: "Contributes back through open code, research or funding", Weight: 1},
+		},
+	},
+}
+
+// Dimensions lists every score dimension
+var Dimensions = []string{Security, UX, Decentralization, Vibes}
+
+// RubricFor returns the rubric of a dimension
+func RubricFor(dimension string) (Rubric, bool) {
+	for _, rubric := range Rubrics {
+		if rubric.Dimension == dimension {
+			return rubric, true
+		}
+	}
+	return Rubric{}, false
+}
+
+// Criterion returns the criterion with the given key
+func (r Rubric) Criterion(key string) (Criterion, bool) {
+	for _, criterion := range r.Criteria {
+		if criterion.Key == key {
+			return criterion, true
+		}
+	}
+	return Criterion{}, false
+}
+
+// Aggregate combines per-criterion values between 0 and 1 into the
+// dimension's score: the weighted mean of the criteria that apply, rounded
+// to two decimals. A nil value marks a criterion as not applicable.
+func (r Rubric) Aggregate(values map[string]*float64) (float64, error) {
+	var sum, weights float64
+	for _, criterion := range r.Criteria {
+		value := values[criterion.Key]
+		if value == nil {
+			continue
+		}
+		sum += *value * criterion.Weight
+		weights += criterion.Weight
+	}
+
+	if weights == 0 {
+		return 0, errors.New("at least one criterion must apply")
+	}
+
+	return round(sum / weights), nil
+}
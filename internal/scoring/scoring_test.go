@@ -0,0 +1,64 @@
+package scoring
+
+import "testing"
+
+func ptr(v float64) *float64 { return &v }
+
+func TestAggregate(t *testing.T) {
+	rubric := Rubric{
+		Dimension: "test",
+		Criteria: []Criterion{
+			{Key: "a", Weight: 3},
+			{Key: "b", Weight: 1},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]*float64
+		want    float64
+		wantErr bool
+	}{
+		{"weighted mean", map[string]*float64{"a": ptr(1), "b": ptr(0)}, 0.75, false},
+		{"all equal", map[string]*float64{"a": ptr(0.4), "b": ptr(0.4)}, 0.4, false},
+		{"not applicable criterion skipped", map[string]*float64{"a": nil, "b": ptr(0.2)}, 0.2, false},
+		{"missing criterion skipped", map[string]*float64{"a": ptr(0.6)}, 0.6, false},
+		{"unknown keys ignored", map[string]*float64{"a": ptr(1), "z": ptr(0)}, 1, false},
+		{"rounded to two decimals", map[string]*float64{"a": ptr(0.333), "b": ptr(0.333)}, 0.33, false},
+		{"nothing applies", map[string]*float64{"a": nil}, 0, true},
+		{"no values", nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rubric.Aggregate(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Aggregate() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Aggregate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRubricsCoverDimensions(t *testing.T) {
+	for _, dimension := range Dimensions {
+		rubric, ok := RubricFor(dimension)
+		if !ok {
+			t.Errorf("no rubric for %s", dimension)
+			continue
+		}
+
+		keys := make(map[string]bool)
+		for _, criterion := range rubric.Criteria {
+			if criterion.Weight <= 0 {
+				t.Errorf("%s criterion %s has weight %v", dimension, criterion.Key, criterion.Weight)
+			}
+			if keys[criterion.Key] {
+				t.Errorf("%s criterion %s is listed twice", dimension, criterion.Key)
+			}
+			keys[criterion.Key] = true
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/slug"
+)
+
+// maxCategoryDepth bounds how deep the category tree may nest
+const maxCategoryDepth = 5
+
+// GetCategories returns all categories as a flat list with product counts
+func (s *Service) GetCategories() ([]models.Category, error) {
+	return s.repo.GetCategories()
+}
+
+// GetCategoryTree returns the categories nested under their parents. Each
+// node's product count covers only products tagged with that category.
+func (s *Service) GetCategoryTree() ([]models.Category, error) {
+	categories, err := s.repo.GetCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	childIDs := make(map[string][]int)
+	var rootIDs []int
+	for i, category := range categories {
+		if category.ParentID == nil {
+			rootIDs = append(rootIDs, i)
+			continue
+		}
+		childIDs[*category.ParentID] = append(childIDs[*category.ParentID], i)
+	}
+
+	var build func(i int) models.Category
+	build = func(i int) models.Category {
+		node := categories[i]
+		for _, child := range childIDs[node.ID] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+
+	tree := make([]models.Category, 0, len(rootIDs))
+	for _, i := range rootIDs {
+		tree = append(tree, build(i))
+	}
+
+	return tree, nil
+}
+
+// GetCategory returns a single category by ID or slug
+func (s *Service) GetCategory(idOrSlug string) (*models.Category, error) {
+	return s.repo.GetCategoryByID(idOrSlug)
+}
+
+// SubmitCategory creates a category directly when the submitter is a curator
+// and otherwise queues it for review. It reports whether the category was
+// created immediately.
+func (s *Service) SubmitCategory(category *models.Category, user *models.User) (bool, error) {
+	category.ID = ""
+	if err := s.validateCategory(category); err != nil {
+		return false, err
+	}
+
+	if s.IsUserCurator(user.WalletAddress) {
+		return true, s.repo.CreateCategory(category)
+	}
+
+	changeData, err := json.Marshal(category)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode category: %w", err)
+	}
+
+	return false, s.repo.CreatePendingEdit(&models.PendingEdit{
+		UserID:     user.ID,
+		EntityType: "category",
+		ChangeType: "create",
+		ChangeData: string(changeData),
+	})
+}
+
+// UpdateCategory validates and updates an existing category, which may be
+// addressed by its ID or slug
+func (s *Service) UpdateCategory(category *models.Category, actorID string) error {
+	existing, err := s.repo.GetCategoryByID(category.ID)
+	if err != nil {
+		return err
+	}
+	category.ID = existing.ID
+
+	if err := s.validateCategory(category); err != nil {
+		return err
+	}
+	return s.repo.UpdateCategory(category, actorID)
+}
+
+// DeleteCategory deletes a category that has no products or subcategories.
+// It may be addressed by its ID or slug.
+func (s *Service) DeleteCategory(idOrSlug, actorID string) error {
+	existing, err := s.repo.GetCategoryByID(idOrSlug)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteCategory(existing.ID, actorID)
+}
+
+// MergeCategories folds source into target, moving its products and
+// subcategories, and returns the number of products moved
+func (s *Service) MergeCategories(sourceID, targetID, actorID string) (int, error) {
+	if sourceID == targetID {
+		return 0, errors.New("cannot merge a category into itself")
+	}
+
+	source, err := s.repo.GetCategoryByID(sourceID)
+	if err != nil {
+		return 0, err
+	}
+	target, err := s.repo.GetCategoryByID(targetID)
+	if err != nil {
+		return 0, fmt.Errorf("target %w", err)
+	}
+
+	// Merging a category into one of its own descendants would orphan the subtree
+	for parentID := target.ParentID; parentID != nil; {
+		if *parentID == source.ID {
+			return 0, errors.New("cannot merge a category into its own subcategory")
+		}
+		parent, err := s.repo.GetCategoryByID(*parentID)
+		if err != nil {
+			return 0, fmt.Errorf("parent category %s: %w", *parentID, err)
+		}
+		parentID = parent.ParentID
+	}
+
+	return s.repo.MergeCategories(source.ID, target.ID, actorID)
+}
+
+// validateCategory normalises the name and slug, ensures the slug is unique
+// and that the parent exists without forming a cycle or nesting too deeply
+func (s *Service) validateCategory(category *models.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return errors.New("category name is required")
+	}
+
+	category.Slug = slug.Make(category.Slug)
+	if category.Slug == "" {
+		category.Slug = slug.Make(category.Name)
+	}
+	if category.Slug == "" {
+		return errors.New("category slug must contain letters or digits")
+	}
+
+	taken, err := s.repo.CategorySlugTaken(category.Slug, category.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("category slug is already taken")
+	}
+
+	if category.ParentID == nil || *category.ParentID == "" {
+		category.ParentID = nil
+		return nil
+	}
+
+	// Walk up the parent chain to reject self-references, cycles and deep nesting
+	parentID := *category.ParentID
+	for depth := 1; parentID != ""; depth++ {
+		if parentID == category.ID {
+			return errors.New("parent_id would create a cycle")
+		}
+		if depth >= maxCategoryDepth {
+			return fmt.Errorf("categories may nest at most %d levels deep", maxCategoryDepth)
+		}
+
+		parent, err := s.repo.GetCategoryByID(parentID)
+		if err != nil {
+			return fmt.Errorf("parent category %s: %w", parentID, err)
+		}
+		if depth == 1 {
+			// Store the canonical ID even if the parent was given by slug
+			category.ParentID = &parent.ID
+		}
+
+		parentID = ""
+		if parent.ParentID != nil {
+			parentID = *parent.ParentID
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// GetChains returns all chains with their product counts
+func (s *Service) GetChains() ([]models.Chain, error) {
+	return s.repo.GetChains()
+}
+
+// GetChain returns a single chain by ID
+func (s *Service) GetChain(id string) (*models.Chain, error) {
+	return s.repo.GetChainByID(id)
+}
+
+// CreateChain validates and creates a new chain
+func (s *Service) CreateChain(chain *models.Chain) error {
+	if err := s.validateChain(chain); err != nil {
+		return err
+	}
+	return s.repo.CreateChain(chain)
+}
+
+// UpdateChain validates and updates an existing chain
+func (s *Service) UpdateChain(chain *models.Chain) error {
+	if _, err := s.repo.GetChainByID(chain.ID); err != nil {
+		return err
+	}
+	if err := s.validateChain(chain); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateChain(chain); err != nil {
+		return err
+	}
+	s.contractIndexChanged()
+	return nil
+}
+
+// DeleteChain deletes a chain that has no products or child chains
+func (s *Service) DeleteChain(id string) error {
+	if err := s.repo.DeleteChain(id); err != nil {
+		return err
+	}
+	s.contractIndexChanged()
+	return nil
+}
+
+// validateChain checks chain metadata and that the parent chain exists
+// without forming a cycle
+func (s *Service) validateChain(chain *models.Chain) error {
+	chain.Name = strings.TrimSpace(chain.Name)
+	if chain.Name == "" {
+		return errors.New("chain name is required")
+	}
+
+	if chain.EVMChainID != nil && *chain.EVMChainID <= 0 {
+		return errors.New("evm_chain_id must be positive")
+	}
+
+	if chain.ExplorerURL != "" {
+		parsed, err := url.Parse(chain.ExplorerURL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return errors.New("explorer_url must be an http(s) URL")
+		}
+	}
+
+	if chain.ParentChainID == nil || *chain.ParentChainID == "" {
+		chain.ParentChainID = nil
+		return nil
+	}
+
+	// Walk up the parent chain to reject self-references and cycles
+	parentID := *chain.ParentChainID
+	for depth := 0; parentID != ""; depth++ {
+		if parentID == chain.ID || depth > 10 {
+			return errors.New("parent_chain_id would create a cycle")
+		}
+
+		parent, err := s.repo.GetChainByID(parentID)
+		if err != nil {
+			return fmt.Errorf("parent chain %s: %w", parentID, err)
+		}
+
+		parentID = ""
+		if parent.ParentChainID != nil {
+			parentID = *parent.ParentChainID
+		}
+	}
+
+	return nil
+}
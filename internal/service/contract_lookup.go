@@ -0,0 +1,107 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// MaxContractLookups caps how many addresses one batch lookup may resolve
+const MaxContractLookups = 500
+
+// ContractLookup identifies a contract to resolve
+type ContractLookup struct {
+	ChainID int64  `json:"chain_id"` // EIP-155 chain ID
+	Address string `json:"address"`
+}
+
+// ContractLookupResult pairs a lookup with the product found, if any
+type ContractLookupResult struct {
+	ContractLookup
+	Match *models.ContractMatch `json:"match"`
+}
+
+// LookupContract resolves a contract on an EVM chain to the product that deployed it
+func (s *Service) LookupContract(chainID int64, address string) (*models.ContractMatch, bool) {
+	match, ok := s.contracts.Lookup(chainID, address)
+	if !ok {
+		return nil, false
+	}
+	return &match, true
+}
+
+// LookupContracts resolves many contracts at once, in request order
+func (s *Service) LookupContracts(lookups []ContractLookup) []ContractLookupResult {
+	results := make([]ContractLookupResult, len(lookups))
+	for i, lookup := range lookups {
+		results[i].ContractLookup = lookup
+		results[i].Match, _ = s.LookupContract(lookup.ChainID, lookup.Address)
+	}
+	return results
+}
+
+// RefreshContractIndex rebuilds the contract lookup index from the database.
+// Only publicly listed products are indexed.
+func (s *Service) RefreshContractIndex() error {
+	matches, err := s.repo.GetContractMatches(lifecycle.PublicStates)
+	if err != nil {
+		return err
+	}
+	s.contracts.Replace(matches)
+	return nil
+}
+
+// ContractIndexStats reports the size and age of the contract lookup index
+func (s *Service) ContractIndexStats() (size int, builtAt time.Time) {
+	return s.contracts.Stats()
+}
+
+// contractIndexChanged asks the indexer to rebuild the index. Requests made
+// while a rebuild is already queued are coalesced into it.
+func (s *Service) contractIndexChanged() {
+	select {
+	case s.contractsChanged <- struct{}{}:
+	default:
+	}
+}
+
+// StartContractIndexer builds the contract lookup index and keeps it fresh,
+// rebuilding it whenever products change and on the configured interval,
+// until the returned stop function is called
+func (s *Service) StartContractIndexer() (stop func()) {
+	if err := s.RefreshContractIndex(); err != nil {
+		log.Printf("Contract index build failed: %v", err)
+	}
+
+	var tick <-chan time.Time
+	var ticker *time.Ticker
+	if s.cfg.ContractIndexRefresh > 0 {
+		ticker = time.NewTicker(s.cfg.ContractIndexRefresh)
+		tick = ticker.C
+	}
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-tick:
+			case <-s.contractsChanged:
+			case <-done:
+				return
+			}
+
+			if err := s.RefreshContractIndex(); err != nil {
+				log.Printf("Contract index refresh failed: %v", err)
+			}
+		}
+	}()
+
+	return func() {
+		if ticker != nil {
+			ticker.Stop()
+		}
+		close(done)
+	}
+}
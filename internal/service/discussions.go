@@ -0,0 +1,324 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// Limits on discussion content
+const (
+	MaxReviewLength  = 10000 // characters of markdown
+	MaxCommentLength = 5000  // characters of markdown
+	MaxCommentDepth  = 8     // replies nested below a thread's first comment
+)
+
+// moderationStatuses maps each moderation action to the status it sets
+var moderationStatuses = map[string]string{
+	"hide":    "hidden",
+	"restore": "visible",
+	"remove":  "removed",
+}
+
+// discussableProduct resolves a product that reviews and comments may be
+// added to
+func (s *Service) discussableProduct(productID string) (*models.Product, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if !lifecycle.IsPublic(product.Status) {
+		return nil, errors.New("only listed products can be discussed")
+	}
+	return product, nil
+}
+
+// validateDiscussionText trims markdown text and checks its length
+func validateDiscussionText(kind, text string, max int) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("%s text is required", kind)
+	}
+	if utf8.RuneCountInString(text) > max {
+		return "", fmt.Errorf("%s text must be at most %d characters", kind, max)
+	}
+	return text, nil
+}
+
+// validateReviewRating checks an optional star rating
+func validateReviewRating(rating *int) error {
+	if rating != nil && (*rating < 1 || *rating > 5) {
+		return errors.New("rating must be between 1 and 5")
+	}
+	return nil
+}
+
+// moderationStatus returns the status a moderation action sets, refusing to
+// act on content that was already removed
+func moderationStatus(action, current string) (string, error) {
+	status, ok := moderationStatuses[action]
+	if !ok {
+		return "", fmt.Errorf("unknown moderation action %q", action)
+	}
+	if current == "removed" || current == "deleted" {
+		return "", fmt.Errorf("cannot %s content that has been %s", action, current)
+	}
+	if current == status {
+		return "", fmt.Errorf("content is already %s", status)
+	}
+	return status, nil
+}
+
+// CreateReview adds a user's review of a product. Each user may review a
+// product once.
+func (s *Service) CreateReview(productID, body string, rating *int, author *models.User) (*models.Review, error) {
+	body, err := validateDiscussionText("review", body, MaxReviewLength)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateReviewRating(rating); err != nil {
+		return nil, err
+	}
+
+	product, err := s.discussableProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &models.Review{
+		ProductID: product.ID,
+		UserID:    author.ID,
+		Body:      body,
+		Rating:    rating,
+	}
+	if err := s.repo.CreateReview(review); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetReview(review.ID)
+}
+
+// GetProductReviews returns a page of the reviews of a product viewer may
+// see, newest first. Curators also see hidden reviews. A signed-in viewer's
+// own review is returned separately, whatever its status.
+func (s *Service) GetProductReviews(productID string, viewer *models.User, page, perPage int) ([]models.Review, int, *models.Review, error) {
+	productID, err := s.repo.ResolveProductID(productID)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if err := s.checkProductVisible(productID, viewer); err != nil {
+		return nil, 0, nil, err
+	}
+
+	includeHidden := viewer != nil && s.IsUserCurator(viewer.WalletAddress)
+	reviews, total, err := s.repo.GetProductReviews(productID, includeHidden, page, perPage)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	var own *models.Review
+	if viewer != nil && viewer.ID != "" {
+		own, err = s.repo.GetUserReview(productID, viewer.ID)
+		if err != nil && err.Error() != "review not found" {
+			return nil, 0, nil, err
+		}
+	}
+
+	return reviews, total, own, nil
+}
+
+// UpdateReview lets the author rewrite their review. A hidden review stays
+// hidden until a curator restores it.
+func (s *Service) UpdateReview(reviewID, body string, rating *int, author *models.User) (*models.Review, error) {
+	body, err := validateDiscussionText("review", body, MaxReviewLength)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateReviewRating(rating); err != nil {
+		return nil, err
+	}
+
+	review, err := s.repo.GetReview(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != author.ID {
+		return nil, errors.New("only the author may edit this review")
+	}
+	if review.Status == "removed" {
+		return nil, errors.New("review has been removed by a curator")
+	}
+
+	if err := s.repo.UpdateReview(review.ID, body, rating); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetReview(review.ID)
+}
+
+// DeleteReview lets the author delete their review. A review removed by a
+// curator stays, so its author cannot post it again.
+func (s *Service) DeleteReview(reviewID string, author *models.User) error {
+	review, err := s.repo.GetReview(reviewID)
+	if err != nil {
+		return err
+	}
+	if review.UserID != author.ID {
+		return errors.New("only the author may delete this review")
+	}
+	if review.Status == "removed" {
+		return errors.New("review has been removed by a curator")
+	}
+
+	return s.repo.DeleteReview(review.ID)
+}
+
+// ModerateReview hides, restores or removes a review
+func (s *Service) ModerateReview(reviewID, action, reason string, curator *models.User) (*models.Review, error) {
+	review, err := s.repo.GetReview(reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := moderationStatus(action, review.Status)
+	if err != nil {
+		return nil, err
+	}
+	if status != "visible" && reason == "" {
+		return nil, errors.New("a reason is required")
+	}
+
+	if err := s.repo.ModerateReview(review.ID, status, reason, curator.ID, action); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetReview(review.ID)
+}
+
+// CreateComment adds a comment to a product, or a reply when parentID is set
+func (s *Service) CreateComment(productID string, parentID *string, body string, author *models.User) (*models.Comment, error) {
+	body, err := validateDiscussionText("comment", body, MaxCommentLength)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.discussableProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID != nil && *parentID == "" {
+		parentID = nil
+	}
+	if parentID != nil {
+		parent, err := s.repo.GetComment(*parentID)
+		if err != nil {
+			return nil, fmt.Errorf("parent %w", err)
+		}
+		if parent.ProductID != product.ID {
+			return nil, errors.New("parent comment not found")
+		}
+		if parent.Status != "visible" {
+			return nil, errors.New("cannot reply to a comment that is not visible")
+		}
+		if parent.Depth >= MaxCommentDepth {
+			return nil, fmt.Errorf("replies may be nested at most %d levels deep", MaxCommentDepth)
+		}
+	}
+
+	comment := &models.Comment{
+		ProductID: product.ID,
+		ParentID:  parentID,
+		UserID:    &author.ID,
+		Body:      body,
+	}
+	if err := s.repo.CreateComment(comment); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetComment(comment.ID)
+}
+
+// GetProductComments returns a page of the threads on a product viewer may
+// see, newest first, each with its replies. Curators also see hidden
+// comments.
+func (s *Service) GetProductComments(productID string, viewer *models.User, page, perPage int) ([]*models.Comment, int, error) {
+	productID, err := s.repo.ResolveProductID(productID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.checkProductVisible(productID, viewer); err != nil {
+		return nil, 0, err
+	}
+
+	includeHidden := viewer != nil && s.IsUserCurator(viewer.WalletAddress)
+	return s.repo.GetProductComments(productID, includeHidden, page, perPage)
+}
+
+// UpdateComment lets the author rewrite their comment
+func (s *Service) UpdateComment(commentID, body string, author *models.User) (*models.Comment, error) {
+	body, err := validateDiscussionText("comment", body, MaxCommentLength)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.repo.GetComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID == nil || *comment.UserID != author.ID {
+		return nil, errors.New("only the author may edit this comment")
+	}
+	if comment.Status == "removed" {
+		return nil, errors.New("comment has been removed by a curator")
+	}
+
+	if err := s.repo.UpdateComment(comment.ID, body); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetComment(comment.ID)
+}
+
+// DeleteComment lets the author delete their comment
+func (s *Service) DeleteComment(commentID string, author *models.User) error {
+	comment, err := s.repo.GetComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.UserID == nil || *comment.UserID != author.ID {
+		return errors.New("only the author may delete this comment")
+	}
+	if comment.Status == "removed" {
+		return errors.New("comment has been removed by a curator")
+	}
+
+	return s.repo.DeleteComment(comment.ID)
+}
+
+// ModerateComment hides, restores or removes a comment. Replies are not
+// affected.
+func (s *Service) ModerateComment(commentID, action, reason string, curator *models.User) (*models.Comment, error) {
+	comment, err := s.repo.GetComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := moderationStatus(action, comment.Status)
+	if err != nil {
+		return nil, err
+	}
+	if status != "visible" && reason == "" {
+		return nil, errors.New("a reason is required")
+	}
+
+	if err := s.repo.ModerateComment(comment.ID, status, reason, curator.ID, action); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetComment(comment.ID)
+}
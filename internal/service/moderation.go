@@ -0,0 +1,377 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// MaxReportDetailsLength caps the free-text details of a report
+const MaxReportDetailsLength = 2000
+
+// ReportReasons are the reason codes a report may give
+var ReportReasons = []string{
+	"scam",           // fraudulent product or phishing
+	"spam",           // advertising or repeated content
+	"abuse",          // harassment, hate or threats
+	"misinformation", // false or misleading claims
+	"impersonation",  // pretends to be another project or person
+	"illegal",        // unlawful content
+	"other",          // explained in the details
+}
+
+// caseActions lists the resolution actions, other than dismissing, that
+// apply to each kind of report target
+var caseActions = map[string][]string{
+	"product":  {"delist", "flag_scam", "ban"},
+	"revision": {"delist", "ban"},
+	"review":   {"hide", "remove", "ban"},
+	"comment":  {"hide", "remove", "ban"},
+	"user":     {"ban"},
+}
+
+// activeCaseStatuses are the statuses of cases still in the queue
+var activeCaseStatuses = []string{"open", "in_review"}
+
+// ReportContent files a user's report against a product, revision, review,
+// comment or user. Reports against the same target are grouped into one case.
+func (s *Service) ReportContent(targetType, targetID, reason, details string, reporter *models.User) (*models.Report, error) {
+	if !slices.Contains(ReportReasons, reason) {
+		return nil, fmt.Errorf("unknown report reason %q", reason)
+	}
+	details = strings.TrimSpace(details)
+	if reason == "other" && details == "" {
+		return nil, errors.New("details are required when the reason is other")
+	}
+	if utf8.RuneCountInString(details) > MaxReportDetailsLength {
+		return nil, fmt.Errorf("details must be at most %d characters", MaxReportDetailsLength)
+	}
+
+	var productID *string
+	switch targetType {
+	case "product":
+		id, err := s.repo.ResolveProductID(targetID)
+		if err != nil {
+			return nil, err
+		}
+		targetID = id
+		productID = &id
+	case "revision":
+		revision, err := s.repo.GetRevisionByID(targetID)
+		if err != nil {
+			return nil, err
+		}
+		productID = &revision.ProductID
+	case "review":
+		review, err := s.repo.GetReview(targetID)
+		if err != nil {
+			return nil, err
+		}
+		productID = &review.ProductID
+	case "comment":
+		comment, err := s.repo.GetComment(targetID)
+		if err != nil {
+			return nil, err
+		}
+		productID = &comment.ProductID
+	case "user":
+		user, err := s.repo.GetUserByID(targetID)
+		if err != nil {
+			return nil, err
+		}
+		if user.ID == reporter.ID {
+			return nil, errors.New("you cannot report yourself")
+		}
+	default:
+		return nil, fmt.Errorf("unknown report target type %q", targetType)
+	}
+
+	report := &models.Report{
+		ReporterID: reporter.ID,
+		Reason:     reason,
+		Details:    details,
+	}
+	if _, err := s.repo.CreateReport(report, targetType, targetID, productID); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// GetModerationQueue returns a page of moderation cases, oldest first. Only
+// open and in-review cases are listed unless other statuses are asked for.
+func (s *Service) GetModerationQueue(filter models.ModerationCaseFilter) ([]models.ModerationCase, int, error) {
+	if len(filter.Statuses) == 0 {
+		filter.Statuses = activeCaseStatuses
+	}
+	for _, status := range filter.Statuses {
+		switch status {
+		case "open", "in_review", "resolved", "dismissed":
+		default:
+			return nil, 0, fmt.Errorf("unknown case status %q", status)
+		}
+	}
+	if _, ok := caseActions[filter.TargetType]; filter.TargetType != "" && !ok {
+		return nil, 0, fmt.Errorf("unknown report target type %q", filter.TargetType)
+	}
+
+	return s.repo.GetModerationCases(filter)
+}
+
+// GetModerationCase returns a case with its reports, its assignee and the
+// moderation actions taken on it
+func (s *Service) GetModerationCase(caseID string) (*models.ModerationCase, error) {
+	c, err := s.repo.GetModerationCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.AssigneeID != nil {
+		c.Assignee, err = s.repo.GetUserByID(*c.AssigneeID)
+		if err != nil && err.Error() != "user not found" {
+			return nil, err
+		}
+	}
+
+	c.History, err = s.repo.GetEntityAuditLog("moderation_case", c.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// AssignModerationCase hands a case to a moderator, who must be a curator,
+// or returns it to the queue when assigneeID is empty
+func (s *Service) AssignModerationCase(caseID, assigneeID string, actor *models.User) (*models.ModerationCase, error) {
+	var assignee *string
+	if assigneeID != "" {
+		user, err := s.repo.GetUserByID(assigneeID)
+		if err != nil {
+			return nil, fmt.Errorf("assignee %w", err)
+		}
+		if !s.IsUserCurator(user.WalletAddress) {
+			return nil, errors.New("cases may only be assigned to curators")
+		}
+		assignee = &user.ID
+	}
+
+	if err := s.repo.AssignModerationCase(caseID, assignee, actor.ID); err != nil {
+		return nil, err
+	}
+
+	return s.GetModerationCase(caseID)
+}
+
+// ResolveModerationCase closes a case. Dismissing it takes no action; any
+// other action is applied to the reported target, or to its author when
+// banning, before the case is marked resolved. The case is claimed first, so
+// concurrent resolves cannot apply their actions twice.
+func (s *Service) ResolveModerationCase(caseID, action, note string, actor *models.User) (*models.ModerationCase, error) {
+	c, err := s.repo.GetModerationCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != "open" && c.Status != "in_review" {
+		return nil, errors.New("case is already closed")
+	}
+
+	if action != "dismiss" {
+		if !slices.Contains(caseActions[c.TargetType], action) {
+			return nil, fmt.Errorf("cannot %s a reported %s", action, c.TargetType)
+		}
+		if note == "" {
+			return nil, errors.New("a note is required")
+		}
+	}
+
+	if err := s.repo.ClaimModerationCase(c.ID); err != nil {
+		return nil, err
+	}
+	if err := s.applyCaseResolution(c, action, note, actor); err != nil {
+		s.repo.ReleaseModerationCase(c.ID)
+		return nil, err
+	}
+
+	return s.GetModerationCase(c.ID)
+}
+
+// applyCaseResolution applies a validated action to a claimed case and
+// closes it
+func (s *Service) applyCaseResolution(c *models.ModerationCase, action, note string, actor *models.User) error {
+	if action == "dismiss" {
+		return s.repo.CloseModerationCase(c.ID, "dismissed", action, note, actor.ID)
+	}
+
+	var err error
+	switch action {
+	case "hide", "remove":
+		err = s.moderateReportedContent(c, action, note, actor)
+	case "delist":
+		err = s.sanctionReportedProduct(*c.ProductID, lifecycle.Delisted, note, actor)
+	case "flag_scam":
+		err = s.sanctionReportedProduct(*c.ProductID, lifecycle.ScamFlagged, note, actor)
+	case "ban":
+		err = s.banReportedAuthor(c, note, actor)
+	}
+	if err != nil {
+		return err
+	}
+	if action != "ban" {
+		if err := s.probateReportedAuthor(c, note, actor); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.CloseModerationCase(c.ID, "resolved", action, note, actor.ID)
+}
+
+// ReopenModerationCase returns a closed case to the queue without undoing
+// the action it was resolved with
+func (s *Service) ReopenModerationCase(caseID, note string, actor *models.User) (*models.ModerationCase, error) {
+	if err := s.repo.ReopenModerationCase(caseID, note, actor.ID); err != nil {
+		return nil, err
+	}
+	return s.GetModerationCase(caseID)
+}
+
+// moderateReportedContent hides or removes a reported review or comment.
+// Content that is already in the requested state is left alone.
+func (s *Service) moderateReportedContent(c *models.ModerationCase, action, note string, actor *models.User) error {
+	var current string
+	switch c.TargetType {
+	case "review":
+		review, err := s.repo.GetReview(c.TargetID)
+		if err != nil {
+			return err
+		}
+		current = review.Status
+	case "comment":
+		comment, err := s.repo.GetComment(c.TargetID)
+		if err != nil {
+			return err
+		}
+		current = comment.Status
+	}
+
+	if current == moderationStatuses[action] {
+		return nil
+	}
+	status, err := moderationStatus(action, current)
+	if err != nil {
+		return err
+	}
+
+	if c.TargetType == "review" {
+		return s.repo.ModerateReview(c.TargetID, status, note, actor.ID, action)
+	}
+	return s.repo.ModerateComment(c.TargetID, status, note, actor.ID, action)
+}
+
+// sanctionReportedProduct moves a reported product to the delisted or
+// scam-flagged state, unless it is already there
+func (s *Service) sanctionReportedProduct(productID, status, note string, actor *models.User) error {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return err
+	}
+	if product.Status == status {
+		return nil
+	}
+	if err := lifecycle.Check(product.Status, status, lifecycle.Curator, note); err != nil {
+		return err
+	}
+
+	from := product.Status
+	err = s.repo.ChangeProductStatus(&models.ProductStatusChange{
+		ProductID:  product.ID,
+		FromStatus: &from,
+		ToStatus:   status,
+		Reason:     note,
+		ActorID:    &actor.ID,
+	}, lifecycle.Listed(status))
+	if err != nil {
+		return err
+	}
+	s.contractIndexChanged()
+
+	return nil
+}
+
+// reportedAuthorID returns the ID of the reported user, or of the author of
+// reported content, or "" when the author is unknown
+func (s *Service) reportedAuthorID(c *models.ModerationCase) (string, error) {
+	switch c.TargetType {
+	case "user":
+		return c.TargetID, nil
+	case "product":
+		product, err := s.repo.GetProductByID(c.TargetID)
+		if err != nil {
+			return "", err
+		}
+		return product.SubmitterID, nil
+	case "revision":
+		revision, err := s.repo.GetRevisionByID(c.TargetID)
+		if err != nil {
+			return "", err
+		}
+		if revision.EditorID != nil {
+			return *revision.EditorID, nil
+		}
+	case "review":
+		review, err := s.repo.GetReview(c.TargetID)
+		if err != nil {
+			return "", err
+		}
+		return review.UserID, nil
+	case "comment":
+		comment, err := s.repo.GetComment(c.TargetID)
+		if err != nil {
+			return "", err
+		}
+		if comment.UserID != nil {
+			return *comment.UserID, nil
+		}
+	}
+	return "", nil
+}
+
+// banReportedAuthor bans a reported user, or the author of reported content
+func (s *Service) banReportedAuthor(c *models.ModerationCase, note string, actor *models.User) error {
+	userID, err := s.reportedAuthorID(c)
+	if err != nil {
+		return err
+	}
+	if userID == "" {
+		return errors.New("the author of this content is unknown")
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if s.IsUserCurator(user.WalletAddress) {
+		return errors.New("curators cannot be banned")
+	}
+	if banActive(user, time.Now()) && user.BanExpiresAt == nil {
+		return nil
+	}
+
+	return s.repo.BanUser(user.ID, note, nil, nil, actor.ID)
+}
+
+// probateReportedAuthor puts the author of content taken down through a
+// report on automatic probation. Unknown authors are skipped.
+func (s *Service) probateReportedAuthor(c *models.ModerationCase, note string, actor *models.User) error {
+	userID, err := s.reportedAuthorID(c)
+	if err != nil || userID == "" {
+		return err
+	}
+	return s.extendProbation(userID, note, actor)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+const (
+	maxProductLinks     = 20
+	maxProductContracts = 50
+	maxLinkLabelLength  = 60
+	maxAddressLength    = 128 // generous enough for non-EVM address formats
+)
+
+// linkTypes are the kinds of external link a product may have
+var linkTypes = map[string]bool{
+	"website":    true,
+	"docs":       true,
+	"github":     true,
+	"twitter":    true,
+	"discord":    true,
+	"telegram":   true,
+	"blog":       true,
+	"whitepaper": true,
+	"audit":      true,
+	"other":      true,
+}
+
+// GetProductByContract returns the product that deployed address on a chain
+// if viewer may see it. viewer is nil for anonymous requests.
+func (s *Service) GetProductByContract(chainID, address string, viewer *models.User) (*models.Product, error) {
+	product, err := s.repo.GetProductByContract(chainID, address)
+	if err != nil {
+		return nil, err
+	}
+	return s.visibleProduct(product, viewer)
+}
+
+// validateProductLinks checks a product's external links
+func validateProductLinks(links []models.ExternalLink) error {
+	if len(links) > maxProductLinks {
+		return fmt.Errorf("a product may have at most %d links", maxProductLinks)
+	}
+
+	for i := range links {
+		link := &links[i]
+		link.Type = strings.ToLower(strings.TrimSpace(link.Type))
+		link.URL = strings.TrimSpace(link.URL)
+		link.Label = strings.TrimSpace(link.Label)
+
+		if !linkTypes[link.Type] {
+			return fmt.Errorf("unknown link type %q", link.Type)
+		}
+		parsed, err := url.Parse(link.URL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return fmt.Errorf("%s link must be an http(s) URL", link.Type)
+		}
+		if len(link.Label) > maxLinkLabelLength {
+			return fmt.Errorf("link labels must be at most %d characters", maxLinkLabelLength)
+		}
+	}
+
+	return nil
+}
+
+// validateProductContracts checks each contract's chain exists and its
+// address is well formed for that chain. EVM addresses must pass their EIP-55
+// checksum when given in mixed case and are stored checksummed.
+func (s *Service) validateProductContracts(contracts []models.Contract) error {
+	if len(contracts) > maxProductContracts {
+		return fmt.Errorf("a product may have at most %d contracts", maxProductContracts)
+	}
+
+	chains := make(map[string]*models.Chain)
+	seen := make(map[string]bool, len(contracts))
+	for i := range contracts {
+		contract := &contracts[i]
+		contract.Address = strings.TrimSpace(contract.Address)
+		contract.Label = strings.TrimSpace(contract.Label)
+
+		chain, ok := chains[contract.ChainID]
+		if !ok {
+			var err error
+			chain, err = s.repo.GetChainByID(contract.ChainID)
+			if err != nil {
+				return fmt.Errorf("contract chain %s: %w", contract.ChainID, err)
+			}
+			chains[contract.ChainID] = chain
+		}
+
+		if chain.EVMChainID != nil {
+			address, err := checksumAddress(contract.Address)
+			if err != nil {
+				return err
+			}
+			contract.Address = address
+		} else if contract.Address == "" || len(contract.Address) > maxAddressLength || strings.ContainsAny(contract.Address, " \t\n") {
+			return fmt.Errorf("invalid %s address %q", chain.Name, contract.Address)
+		}
+
+		if len(contract.Label) > maxLinkLabelLength {
+			return fmt.Errorf("contract labels must be at most %d characters", maxLinkLabelLength)
+		}
+
+		key := contract.ChainID + "/" + strings.ToLower(contract.Address)
+		if seen[key] {
+			return fmt.Errorf("contract %s is listed twice", contract.Address)
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// checksumAddress validates an EVM address and returns its EIP-55 form.
+// All-lowercase or all-uppercase addresses carry no checksum and are accepted.
+func checksumAddress(address string) (string, error) {
+	if !strings.HasPrefix(address, "0x") || !common.IsHexAddress(address) {
+		return "", fmt.Errorf("invalid EVM address %q", address)
+	}
+
+	checksummed := common.HexToAddress(address).Hex()
+	hexPart := address[2:]
+	if hexPart != strings.ToLower(hexPart) && hexPart != strings.ToUpper(hexPart) && address != checksummed {
+		return "", errors.New("address " + address + " fails its EIP-55 checksum")
+	}
+
+	return checksummed, nil
+}
+
+// listJSON renders a list for a field change, with empty lists as ""
+func listJSON(n int, list interface{}) string {
+	if n == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(list)
+	return string(data)
+}
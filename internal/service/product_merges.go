@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// maxDuplicateCandidates caps how many likely duplicates a submission reports
+const maxDuplicateCandidates = 5
+
+// DuplicateProductError is returned when a submission looks like a product
+// that is already listed and the submitter has not confirmed it is distinct
+type DuplicateProductError struct {
+	Candidates []models.DuplicateCandidate
+}
+
+func (e *DuplicateProductError) Error() string {
+	return fmt.Sprintf("product may duplicate %d existing product(s)", len(e.Candidates))
+}
+
+// FindDuplicateProducts returns existing products that share the product's
+// website domain or a contract address, or have a similar title. Only
+// products viewer may see are returned: public ones, and for curators any
+// that are not delisted.
+func (s *Service) FindDuplicateProducts(product *models.Product, viewer *models.User) ([]models.DuplicateCandidate, error) {
+	var hosts []string
+	for _, link := range product.Links {
+		if host := websiteHost(link); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+
+	var contractKeys []string
+	for _, contract := range product.Contracts {
+		contractKeys = append(contractKeys, contract.ChainID+":"+strings.ToLower(contract.Address))
+	}
+
+	statuses := lifecycle.PublicStates
+	if viewer != nil && s.IsUserCurator(viewer.WalletAddress) {
+		statuses = nil
+		for _, state := range lifecycle.States {
+			if state != lifecycle.Delisted {
+				statuses = append(statuses, state)
+			}
+		}
+	}
+
+	return s.repo.FindDuplicateProducts(
+		strings.TrimSpace(product.Title), hosts, contractKeys, statuses,
+		s.cfg.DuplicateTitleSimilarity, maxDuplicateCandidates,
+	)
+}
+
+// websiteHost returns the lowercased host of a website link without a
+// leading "www.", matching product_link_host in the database
+func websiteHost(link models.ExternalLink) string {
+	if link.Type != "website" {
+		return ""
+	}
+	parsed, err := url.Parse(link.URL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+}
+
+// MergeProducts folds a duplicate into the product that survives it, moving
+// its upvotes and relationships, and returns the survivor with the number of
+// upvotes moved. Both products get a revision recording the merge, and the
+// duplicate's ID redirects to the survivor from then on.
+func (s *Service) MergeProducts(sourceID, targetID, reason string, actor *models.User) (*models.Product, int, error) {
+	source, err := s.repo.GetProductByID(sourceID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if source.ID != sourceID {
+		return nil, 0, errors.New("product has already been merged")
+	}
+
+	// Merging into a merged duplicate merges into its survivor
+	target, err := s.repo.GetProductByID(targetID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("target %w", err)
+	}
+	if source.ID == target.ID {
+		return nil, 0, errors.New("cannot merge a product into itself")
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = "Duplicate of " + target.Title
+	}
+
+	moved, err := s.repo.MergeProducts(source.ID, target.ID, reason, actor.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.contractIndexChanged()
+
+	survivor, err := s.repo.GetProductByID(target.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return survivor, moved, nil
+}
+
+// MergeProductsInto folds several duplicates into one surviving product in
+// turn and returns the survivor with the total number of upvotes moved. It
+// stops at the first duplicate that cannot be merged; earlier merges stand.
+func (s *Service) MergeProductsInto(survivorID string, duplicateIDs []string, reason string, actor *models.User) (*models.Product, int, error) {
+	if len(duplicateIDs) == 0 {
+		return nil, 0, errors.New("product_ids is required")
+	}
+
+	var survivor *models.Product
+	total := 0
+	for _, duplicateID := range duplicateIDs {
+		merged, moved, err := s.MergeProducts(duplicateID, survivorID, reason, actor)
+		if err != nil {
+			return nil, 0, fmt.Errorf("merging %s: %w", duplicateID, err)
+		}
+		survivor = merged
+		total += moved
+	}
+
+	return survivor, total, nil
+}
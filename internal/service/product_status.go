@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// ChangeProductStatus moves a product through its lifecycle. The move must be
+// allowed from the product's current state for the actor's role, and a
+// deprecated product may name the product that replaces it.
+func (s *Service) ChangeProductStatus(productID, status, reason string, successorID *string, actor *models.User) (*models.Product, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var role lifecycle.Role
+	switch {
+	case s.IsUserCurator(actor.WalletAddress):
+		role = lifecycle.Curator
+	case actor.ID == product.SubmitterID:
+		role = lifecycle.Submitter
+	default:
+		return nil, errors.New("only the submitter or a curator may change this product's status")
+	}
+
+	if err := lifecycle.Check(product.Status, status, role, reason); err != nil {
+		return nil, err
+	}
+
+	if successorID != nil && *successorID == "" {
+		successorID = nil
+	}
+	if successorID != nil {
+		if status != lifecycle.Deprecated {
+			return nil, errors.New("successor_id may only be set when deprecating a product")
+		}
+		if *successorID == product.ID {
+			return nil, errors.New("a product cannot succeed itself")
+		}
+
+		successor, err := s.repo.GetProductByID(*successorID)
+		if err != nil {
+			return nil, fmt.Errorf("successor %w", err)
+		}
+		if !lifecycle.Listed(successor.Status) {
+			return nil, errors.New("successor must be a listed product")
+		}
+	}
+
+	from := product.Status
+	err = s.repo.ChangeProductStatus(&models.ProductStatusChange{
+		ProductID:   product.ID,
+		FromStatus:  &from,
+		ToStatus:    status,
+		Reason:      reason,
+		ActorID:     &actor.ID,
+		SuccessorID: successorID,
+	}, lifecycle.Listed(status))
+	if err != nil {
+		return nil, err
+	}
+	s.contractIndexChanged()
+
+	return s.repo.GetProductByID(product.ID)
+}
+
+// GetProductStatusHistory returns the lifecycle moves of a product viewer
+// may see, oldest first
+func (s *Service) GetProductStatusHistory(productID string, viewer *models.User) ([]models.ProductStatusChange, error) {
+	if err := s.checkProductVisible(productID, viewer); err != nil {
+		return nil, err
+	}
+	return s.repo.GetProductStatusHistory(productID)
+}
+
+// canViewProduct reports whether viewer may see a product. Products outside
+// the public states are only shown to their submitter and to curators.
+// viewer is nil for anonymous requests.
+func (s *Service) canViewProduct(product *models.Product, viewer *models.User) bool {
+	if lifecycle.IsPublic(product.Status) {
+		return true
+	}
+	if viewer == nil {
+		return false
+	}
+	return viewer.ID == product.SubmitterID || s.IsUserCurator(viewer.WalletAddress)
+}
+
+// visibleProduct returns product if viewer may see it, and otherwise
+// reports it as not found so its existence is not given away
+func (s *Service) visibleProduct(product *models.Product, viewer *models.User) (*models.Product, error) {
+	if !s.canViewProduct(product, viewer) {
+		return nil, errors.New("product not found")
+	}
+	return product, nil
+}
+
+// checkProductVisible returns "product not found" unless the product exists
+// and viewer may see it
+func (s *Service) checkProductVisible(productID string, viewer *models.User) error {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return err
+	}
+	_, err = s.visibleProduct(product, viewer)
+	return err
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+func TestCanViewProduct(t *testing.T) {
+	svc := newTestService(newFakeRepository())
+
+	viewers := []struct {
+		name   string
+		viewer *models.User
+		always bool // may see the product in every state
+	}{
+		{"anonymous", nil, false},
+		{"other user", otherUser, false},
+		{"submitter", submitter, true},
+		{"curator", curator, true},
+	}
+
+	for _, status := range lifecycle.States {
+		product := &models.Product{ID: "p1", SubmitterID: submitter.ID, Status: status}
+		for _, v := range viewers {
+			want := v.always || lifecycle.IsPublic(status)
+			if got := svc.canViewProduct(product, v.viewer); got != want {
+				t.Errorf("canViewProduct(%s, %s) = %v, want %v", status, v.name, got, want)
+			}
+		}
+	}
+}
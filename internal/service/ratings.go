@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/scoring"
+)
+
+// RateProduct records a user's 0-1 ratings of a product's score dimensions.
+// A dimension given as nil withdraws the user's rating of it; dimensions
+// left out keep their current rating.
+func (s *Service) RateProduct(userID, productID string, ratings map[string]*float64) error {
+	if len(ratings) == 0 {
+		return errors.New("at least one rating is required")
+	}
+	for dimension, value := range ratings {
+		if _, ok := scoring.RubricFor(dimension); !ok {
+			return fmt.Errorf("unknown score dimension %q", dimension)
+		}
+		if value != nil && (*value < 0 || *value > 1) {
+			return fmt.Errorf("%s rating must be between 0 and 1", dimension)
+		}
+	}
+
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return err
+	}
+	if !lifecycle.IsPublic(product.Status) {
+		return errors.New("only listed products can be rated")
+	}
+
+	return s.repo.RateProduct(userID, product.ID, ratings)
+}
+
+// RemoveProductRatings withdraws all of a user's ratings of a product
+func (s *Service) RemoveProductRatings(userID, productID string) error {
+	productID, err := s.repo.ResolveProductID(productID)
+	if err != nil {
+		return err
+	}
+	return s.repo.RemoveProductRatings(userID, productID)
+}
+
+// GetProductRatings returns a product's community scores beside its curator
+// scores, with the spread of ratings for each dimension. The viewer's own
+// ratings are included when viewer is set.
+func (s *Service) GetProductRatings(productID string, viewer *models.User) (*models.ProductRatings, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	product, err = s.visibleProduct(product, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	distributions, err := s.repo.GetRatingDistributions(product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	curatorScores := map[string]float64{
+		scoring.Security:         product.SecurityScore,
+		scoring.UX:               product.UXScore,
+		scoring.Decentralization: product.DecentScore,
+		scoring.Vibes:            product.VibesScore,
+	}
+
+	ratings := &models.ProductRatings{ProductID: product.ID}
+	for _, dimension := range scoring.Dimensions {
+		community, ok := product.CommunityScores[dimension]
+		if !ok {
+			// Nobody has rated this dimension, so only the prior applies
+			community.Score = s.cfg.RatingPriorMean
+		}
+
+		ratings.Dimensions = append(ratings.Dimensions, models.DimensionRatings{
+			Dimension:    dimension,
+			CuratorScore: curatorScores[dimension],
+			Community:    community,
+			Distribution: distributions[dimension],
+		})
+	}
+
+	if viewer != nil {
+		ratings.ViewerRatings, err = s.repo.GetUserRatings(viewer.ID, product.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ratings, nil
+}
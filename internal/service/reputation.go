@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/reputation"
+)
+
+// GetUserReputation returns a user's reputation, what it is made of and the
+// privileges it unlocks
+func (s *Service) GetUserReputation(userID string) (*models.Reputation, error) {
+	counts, err := s.repo.GetReputationCounts(userID)
+	if err != nil {
+		return nil, err
+	}
+	return reputation.Evaluate(s.cfg, userID, *counts), nil
+}
+
+// hasPrivilege reports whether a user's reputation unlocks a privilege.
+// Curators hold every privilege.
+func (s *Service) hasPrivilege(user *models.User, privilege string) (bool, error) {
+	if s.IsUserCurator(user.WalletAddress) {
+		return true, nil
+	}
+
+	threshold := reputation.Threshold(s.cfg, privilege)
+	if threshold <= 0 {
+		return true, nil
+	}
+
+	counts, err := s.repo.GetReputationCounts(user.ID)
+	if err != nil {
+		return false, err
+	}
+	return reputation.Score(*counts) >= threshold, nil
+}
+
+// requirePrivilege fails unless a user's reputation unlocks a privilege
+func (s *Service) requirePrivilege(user *models.User, privilege, action string) error {
+	ok, err := s.hasPrivilege(user, privilege)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s requires a reputation of %d", action, reputation.Threshold(s.cfg, privilege))
+	}
+	return nil
+}
+
+// editNeedsReview reports whether a user's product edits are queued for
+// review: while they are on probation, or until they have the reputation to
+// edit directly
+func (s *Service) editNeedsReview(userID string) (bool, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return false, err
+	}
+	if onProbation(user, time.Now()) {
+		return true, nil
+	}
+
+	direct, err := s.hasPrivilege(user, reputation.DirectEdit)
+	if err != nil {
+		return false, err
+	}
+	return !direct, nil
+}
+
+// GetUserProfile returns a user with their contribution metrics, reputation
+// and wallets filled in
+func (s *Service) GetUserProfile(walletAddress string) (*models.User, error) {
+	user, err := s.repo.GetUserByWallet(walletAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.GetReputationCounts(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	user.SubmittedProducts = counts.SubmittedProducts
+	user.Upvotes = counts.UpvotesReceived
+	user.Reputation = reputation.Score(*counts)
+
+	user.Wallets, err = s.repo.GetUserWallets(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
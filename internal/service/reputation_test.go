@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+func TestUpvotesOnDraftEarnNoReputation(t *testing.T) {
+	repo := newFakeRepository(submitter, otherUser, curator)
+	repo.products["p1"] = &models.Product{ID: "p1", SubmitterID: submitter.ID, Status: lifecycle.Draft}
+	svc := newTestService(repo)
+
+	upvotesReceived := func() int {
+		t.Helper()
+		rep, err := svc.GetUserReputation(submitter.ID)
+		if err != nil {
+			t.Fatalf("GetUserReputation() error = %v", err)
+		}
+		return rep.Counts.UpvotesReceived
+	}
+
+	// Curators can see the draft but not vote for it
+	if err := svc.UpvoteProduct(curator.ID, "p1"); err == nil {
+		t.Fatal("UpvoteProduct() on a draft succeeded")
+	}
+	if got := upvotesReceived(); got != 0 {
+		t.Fatalf("upvotes received for a draft = %d, want 0", got)
+	}
+
+	// Once published the votes count, until the product is delisted
+	repo.products["p1"].Status = lifecycle.Published
+	repo.products["p1"].Approved = true
+	if err := svc.UpvoteProduct(curator.ID, "p1"); err != nil {
+		t.Fatalf("UpvoteProduct() error = %v", err)
+	}
+	if got := upvotesReceived(); got != 1 {
+		t.Fatalf("upvotes received for a published product = %d, want 1", got)
+	}
+
+	repo.products["p1"].Status = lifecycle.Delisted
+	repo.products["p1"].Approved = false
+	if got := upvotesReceived(); got != 0 {
+		t.Errorf("upvotes received for a delisted product = %d, want 0", got)
+	}
+}
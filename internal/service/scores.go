@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/scoring"
+)
+
+const (
+	maxScoreNoteLength      = 2000
+	maxCriterionEvidence    = 10
+	maxScoreHistoryPageSize = 200
+)
+
+// GetScoreRubrics returns the rubric behind every score dimension
+func (s *Service) GetScoreRubrics() []scoring.Rubric {
+	return scoring.Rubrics
+}
+
+// GetProductScores returns the assessments behind the scores of a product
+// viewer may see
+func (s *Service) GetProductScores(productID string, viewer *models.User) ([]models.ScoreAssessment, error) {
+	productID, err := s.repo.ResolveProductID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkProductVisible(productID, viewer); err != nil {
+		return nil, err
+	}
+	return s.repo.GetProductScores(productID)
+}
+
+// AssessProductScore sets one of a product's scores from a rubric
+// assessment. Every criterion of the dimension's rubric must be rated or
+// marked not applicable with a null value; the score is their weighted
+// aggregate.
+func (s *Service) AssessProductScore(productID, dimension string, criteria []models.ScoreCriterion, note string, assessor *models.User) (*models.ScoreAssessment, error) {
+	rubric, ok := scoring.RubricFor(dimension)
+	if !ok {
+		return nil, fmt.Errorf("unknown score dimension %q", dimension)
+	}
+
+	productID, err := s.repo.ResolveProductID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]*float64, len(criteria))
+	for i := range criteria {
+		criterion := &criteria[i]
+		criterion.Criterion = strings.TrimSpace(criterion.Criterion)
+		criterion.Notes = strings.TrimSpace(criterion.Notes)
+
+		if _, ok := rubric.Criterion(criterion.Criterion); !ok {
+			return nil, fmt.Errorf("unknown %s criterion %q", dimension, criterion.Criterion)
+		}
+		if _, seen := values[criterion.Criterion]; seen {
+			return nil, fmt.Errorf("criterion %s is rated more than once", criterion.Criterion)
+		}
+		if criterion.Value != nil && (*criterion.Value < 0 || *criterion.Value > 1) {
+			return nil, fmt.Errorf("criterion %s must be between 0 and 1", criterion.Criterion)
+		}
+		if len(criterion.Notes) > maxScoreNoteLength {
+			return nil, fmt.Errorf("notes for %s must be at most %d characters", criterion.Criterion, maxScoreNoteLength)
+		}
+		if err := validateEvidence(criterion); err != nil {
+			return nil, err
+		}
+
+		values[criterion.Criterion] = criterion.Value
+	}
+
+	for _, criterion := range rubric.Criteria {
+		if _, ok := values[criterion.Key]; !ok {
+			return nil, fmt.Errorf("criterion %s is required; send null if it does not apply", criterion.Key)
+		}
+	}
+
+	score, err := rubric.Aggregate(values)
+	if err != nil {
+		return nil, err
+	}
+
+	note = strings.TrimSpace(note)
+	if len(note) > maxScoreNoteLength {
+		return nil, fmt.Errorf("note must be at most %d characters", maxScoreNoteLength)
+	}
+
+	assessment := &models.ScoreAssessment{
+		ProductID:  productID,
+		Dimension:  dimension,
+		Score:      score,
+		Criteria:   criteria,
+		Note:       note,
+		AssessorID: &assessor.ID,
+	}
+	if err := s.repo.SaveScoreAssessment(assessment); err != nil {
+		return nil, err
+	}
+
+	return assessment, nil
+}
+
+// GetScoreHistory returns the assessments made of the scores of a product
+// viewer may see, newest first, optionally for one dimension only
+func (s *Service) GetScoreHistory(productID, dimension string, limit int, viewer *models.User) ([]models.ScoreChange, error) {
+	if dimension != "" {
+		if _, ok := scoring.RubricFor(dimension); !ok {
+			return nil, fmt.Errorf("unknown score dimension %q", dimension)
+		}
+	}
+	if limit <= 0 || limit > maxScoreHistoryPageSize {
+		limit = maxScoreHistoryPageSize
+	}
+
+	productID, err := s.repo.ResolveProductID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkProductVisible(productID, viewer); err != nil {
+		return nil, err
+	}
+	return s.repo.GetScoreHistory(productID, dimension, limit)
+}
+
+// validateEvidence checks that a criterion's evidence is a short list of web links
+func validateEvidence(criterion *models.ScoreCriterion) error {
+	if len(criterion.Evidence) > maxCriterionEvidence {
+		return fmt.Errorf("criterion %s may cite at most %d pieces of evidence", criterion.Criterion, maxCriterionEvidence)
+	}
+
+	for i, evidence := range criterion.Evidence {
+		evidence = strings.TrimSpace(evidence)
+		parsed, err := url.Parse(evidence)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return errors.New("evidence for " + criterion.Criterion + " must be http(s) URLs")
+		}
+		criterion.Evidence[i] = evidence
+	}
+
+	return nil
+}
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"context"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -11,7 +13,15 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 
 	"github.com/wesjorgensen/EthAppList/backend/internal/config"
+	"github.com/wesjorgensen/EthAppList/backend/internal/contractindex"
+	"github.com/wesjorgensen/EthAppList/backend/internal/ens"
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
 	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/reputation"
+	"github.com/wesjorgensen/EthAppList/backend/internal/scoring"
+	"github.com/wesjorgensen/EthAppList/backend/internal/slug"
+	"github.com/wesjorgensen/EthAppList/backend/internal/twitter"
+	"github.com/wesjorgensen/EthAppList/backend/internal/voteweight"
 )
 
 // DataRepository interface defines the methods required by the service
@@ -19,12 +29,29 @@ type DataRepository interface {
 	// User methods
 	CreateUser(user *models.User) error
 	GetUserByWallet(walletAddress string) (*models.User, error)
+	GetUserByID(id string) (*models.User, error)
+	CountUserContributions(userID string) (int, error)
 
 	// Product methods
+	GetContractMatches(statuses []string) ([]models.ContractMatch, error)
+	FindDuplicateProducts(title string, hosts, contractKeys, statuses []string, minSimilarity float64, limit int) ([]models.DuplicateCandidate, error)
+	MergeProducts(sourceID, targetID, reason, actorID string) (int, error)
+	ResolveProductID(id string) (string, error)
+	GetProductScores(productID string) ([]models.ScoreAssessment, error)
+	SaveScoreAssessment(assessment *models.ScoreAssessment) error
+	GetScoreHistory(productID, dimension string, limit int) ([]models.ScoreChange, error)
+	RateProduct(userID, productID string, ratings map[string]*float64) error
+	RemoveProductRatings(userID, productID string) error
+	GetRatingDistributions(productID string) (map[string][]models.RatingBucket, error)
+	GetUserRatings(userID, productID string) (map[string]float64, error)
 	CreateProduct(product *models.Product) error
 	GetProductByID(id string) (*models.Product, error)
-	GetProducts(categoryID, chainID, searchTerm, sortOption string, page, perPage int) ([]*models.Product, int, error)
+	GetProductBySlug(slug string) (*models.Product, bool, error)
+	GetProductByContract(chainID, address string) (*models.Product, error)
+	GetProducts(filter models.ProductFilter) ([]*models.Product, int, error)
 	UpdateProduct(product *models.Product) error
+	ChangeProductStatus(change *models.ProductStatusChange, listed bool) error
+	GetProductStatusHistory(productID string) ([]models.ProductStatusChange, error)
 	DeleteAllProducts() error
 
 	// Product revision methods
@@ -37,12 +64,106 @@ type DataRepository interface {
 
 	// Category methods
 	GetCategories() ([]models.Category, error)
+	GetCategoryByID(idOrSlug string) (*models.Category, error)
+	CategorySlugTaken(slug, excludeID string) (bool, error)
 	CreateCategory(category *models.Category) error
+	UpdateCategory(category *models.Category, actorID string) error
+	DeleteCategory(id, actorID string) error
+	MergeCategories(sourceID, targetID, actorID string) (int, error)
+
+	// Tag methods
+	GetTags() ([]models.Tag, error)
+	GetTag(idOrSlug string) (*models.Tag, error)
+	SearchTags(prefix string, limit int) ([]models.Tag, error)
+	UpdateTag(tag *models.Tag, actorID string) error
+	DeleteTag(id, actorID string) error
+	AddTagAlias(tagID, alias, actorID string) (int, error)
+	RemoveTagAlias(tagID, alias, actorID string) error
+
+	// Chain methods
+	GetChains() ([]models.Chain, error)
+	GetChainByID(id string) (*models.Chain, error)
+	CreateChain(chain *models.Chain) error
+	UpdateChain(chain *models.Chain) error
+	DeleteChain(id string) error
 
 	// Upvote methods
-	UpvoteProduct(userID, productID string) error
+	UpvoteProduct(upvote *models.Upvote) error
+	RemoveUpvote(userID, productID string) error
+	GetUpvotedProductIDs(userID string, productIDs []string) (map[string]bool, error)
+	GetUserUpvotes(userID string, page, perPage int) ([]models.Upvote, int, error)
+	ReconcileUpvoteCounts(repair bool) (*models.UpvoteReconciliation, error)
+
+	// Discussion methods
+	CreateReview(review *models.Review) error
+	GetReview(id string) (*models.Review, error)
+	GetUserReview(productID, userID string) (*models.Review, error)
+	GetProductReviews(productID string, includeHidden bool, page, perPage int) ([]models.Review, int, error)
+	UpdateReview(id, body string, rating *int) error
+	DeleteReview(id string) error
+	ModerateReview(id, status, reason, actorID, action string) error
+	CreateComment(comment *models.Comment) error
+	GetComment(id string) (*models.Comment, error)
+	GetProductComments(productID string, includeHidden bool, page, perPage int) ([]*models.Comment, int, error)
+	UpdateComment(id, body string) error
+	DeleteComment(id string) error
+	ModerateComment(id, status, reason, actorID, action string) error
+
+	// Moderation methods
+	CreateReport(report *models.Report, targetType, targetID string, productID *string) (string, error)
+	GetModerationCases(filter models.ModerationCaseFilter) ([]models.ModerationCase, int, error)
+	GetModerationCase(id string) (*models.ModerationCase, error)
+	AssignModerationCase(caseID string, assigneeID *string, actorID string) error
+	ClaimModerationCase(caseID string) error
+	ReleaseModerationCase(caseID string) error
+	CloseModerationCase(caseID, status, resolution, note, actorID string) error
+	ReopenModerationCase(caseID, note, actorID string) error
+	BanUser(userID, reason string, expiresAt, probationUntil *time.Time, actorID string) error
+	LiftUserBan(userID, reason string, probationUntil *time.Time, actorID string) error
+	SetUserProbation(userID string, until *time.Time, reason, actorID string) error
+	GetSanctionedUsers() ([]models.User, error)
+
+	// Reputation methods
+	GetReputationCounts(userID string) (*models.ReputationCounts, error)
+
+	// Profile methods
+	FindUser(idOrWallet string) (*models.User, error)
+	UpdateUserProfile(user *models.User) error
+	GetUserEdits(userID string, statuses []string, page, perPage int) ([]models.UserEdit, int, error)
+	GetRevisionByID(id string) (*models.ProductRevision, error)
+
+	// ENS methods
+	GetUsersForENSRefresh(checkedBefore, attemptedBefore time.Time, limit int) ([]models.User, error)
+	SetUserENS(userID, name, avatar string) error
+	SetUserENSAttempt(userID string) error
+
+	// Twitter link methods
+	SaveTwitterChallenge(challenge *models.TwitterChallenge) error
+	GetTwitterChallenge(userID string) (*models.TwitterChallenge, error)
+	LinkTwitterHandle(userID, handle string) error
+	UnlinkTwitterHandle(userID string) error
+
+	// Wallet methods
+	GetUserWallets(userID string) ([]models.UserWallet, error)
+	SaveWalletLinkRequest(request *models.WalletLinkRequest) error
+	GetWalletLinkRequest(userID string) (*models.WalletLinkRequest, error)
+	LinkWallet(userID, address string) error
+	UnlinkWallet(userID, address string) error
+	MergeUsers(sourceID, targetID, reason, actorID string) (int, error)
+
+	// Vote analysis methods
+	GetVoteActivitySince(since time.Time) ([]models.VoteActivity, error)
+	SaveVoteAnomalies(anomalies []models.VoteAnomaly) (int, error)
+	GetVoteAnomalies(status string) ([]models.VoteAnomaly, error)
+	VoidAnomalyVotes(anomalyIDs []string, actorID, reason string) (int, error)
+	DismissVoteAnomaly(anomalyID, actorID, reason string) error
+
+	// Audit log methods
+	GetAuditLog(entityType string, limit int) ([]models.AuditLogEntry, error)
+	GetEntityAuditLog(entityType, entityID string) ([]models.AuditLogEntry, error)
 
 	// Admin methods
+	CreatePendingEdit(edit *models.PendingEdit) error
 	GetPendingEdits() ([]models.PendingEdit, error)
 	ApproveEdit(editID string) error
 	RejectEdit(editID string) error
@@ -50,16 +171,56 @@ type DataRepository interface {
 
 // Service implements business logic for the application
 type Service struct {
-	repo DataRepository
-	cfg  *config.Config
+	repo        DataRepository
+	cfg         *config.Config
+	voteWeights *voteweight.Policy
+
+	// ENS lookups, nil when no RPC endpoint is configured. ensPending wakes
+	// the refresher when a new user signs up.
+	ens        *ens.Resolver
+	ensPending chan struct{}
+
+	// Proves Twitter/X handles, nil when linking is turned off
+	twitter twitter.Verifier
+
+	// Contract lookup index, rebuilt by the indexer when products change
+	contracts        *contractindex.Index
+	contractsChanged chan struct{}
 }
 
 // New creates a new service
-func New(repo DataRepository, cfg *config.Config) *Service {
-	return &Service{
-		repo: repo,
-		cfg:  cfg,
+func New(repo DataRepository, cfg *config.Config) (*Service, error) {
+	voteWeights, err := voteweight.NewPolicy(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build vote weighting policy: %w", err)
+	}
+
+	var resolver *ens.Resolver
+	if cfg.ENSRPCURL != "" {
+		resolver, err = ens.NewResolver(cfg.ENSRPCURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up ENS lookups: %w", err)
+		}
+	}
+
+	var twitterVerifier twitter.Verifier
+	switch cfg.TwitterVerifier {
+	case "api":
+		twitterVerifier = twitter.NewAPIVerifier(cfg.TwitterBearerToken)
+	case "stub":
+		twitterVerifier = twitter.StubVerifier{}
 	}
+
+	return &Service{
+		repo:             repo,
+		cfg:              cfg,
+		voteWeights:      voteWeights,
+		ens:              resolver,
+		ensPending:       make(chan struct{}, 1),
+		twitter:          twitterVerifier,
+		contracts:        contractindex.New(),
+		contractsChanged: make(chan struct{}, 1),
+	}, nil
 }
 
 // GetConfig returns the config for middleware and other components
@@ -86,6 +247,12 @@ func (s *Service) AuthenticateWallet(address, signature, message string) (string
 		if err != nil {
 			return "", fmt.Errorf("failed to create user: %w", err)
 		}
+		s.queueENSRefresh()
+	}
+
+	// Banned and suspended users may not sign in
+	if denial := accessDenial(user, time.Now()); denial != "" {
+		return "", errors.New(denial)
 	}
 
 	// Generate JWT token
@@ -97,34 +264,198 @@ func (s *Service) AuthenticateWallet(address, signature, message string) (string
 	return token, nil
 }
 
-// GetProducts returns a list of products based on filter
-func (s *Service) GetProducts(categoryID, chainID, searchTerm, sortOption string, page, perPage int) ([]*models.Product, int, error) {
-	return s.repo.GetProducts(categoryID, chainID, searchTerm, sortOption, page, perPage)
-}
+// GetProducts returns a list of products based on filter. Without a status
+// filter only publicly listable products are returned.
+func (s *Service) GetProducts(filter models.ProductFilter) ([]*models.Product, int, error) {
+	if len(filter.Status) == 0 {
+		filter.Status = lifecycle.PublicStates
+	}
+	for _, status := range filter.Status {
+		if !lifecycle.Valid(status) {
+			return nil, 0, fmt.Errorf("unknown status %q", status)
+		}
+	}
 
-// GetProduct returns a single product by ID
-func (s *Service) GetProduct(id string) (*models.Product, error) {
-	return s.repo.GetProductByID(id)
+	for i, tag := range filter.Tags {
+		filter.Tags[i] = slug.Make(tag)
+	}
+	return s.repo.GetProducts(filter)
 }
 
-// SubmitProduct creates a new product
-func (s *Service) SubmitProduct(product *models.Product) error {
-	return s.repo.CreateProduct(product)
+// GetProduct returns a single product by ID if viewer may see it. viewer is
+// nil for anonymous requests.
+func (s *Service) GetProduct(id string, viewer *models.User) (*models.Product, error) {
+	product, err := s.repo.GetProductByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.visibleProduct(product, viewer)
 }
 
-// GetCategories returns all categories
-func (s *Service) GetCategories() ([]models.Category, error) {
-	return s.repo.GetCategories()
+// GetProductBySlug returns a product by its slug if viewer may see it.
+// redirected is true when the slug is one the product used before a title
+// change.
+func (s *Service) GetProductBySlug(productSlug string, viewer *models.User) (product *models.Product, redirected bool, err error) {
+	product, redirected, err = s.repo.GetProductBySlug(productSlug)
+	if err != nil {
+		return nil, false, err
+	}
+	product, err = s.visibleProduct(product, viewer)
+	if err != nil {
+		return nil, false, err
+	}
+	return product, redirected, nil
 }
 
-// SubmitCategory creates a new category
-func (s *Service) SubmitCategory(category *models.Category) error {
-	return s.repo.CreateCategory(category)
+// SubmitProduct creates a new product. Products start as drafts when asked
+// to and are otherwise submitted for review; curators may publish directly.
+// Unless confirmDuplicate is set, a product that looks like one already
+// listed is rejected with a *DuplicateProductError naming the candidates.
+func (s *Service) SubmitProduct(product *models.Product, submitter *models.User, confirmDuplicate bool) error {
+	product.SubmitterID = submitter.ID
+
+	switch {
+	case product.Status == lifecycle.Draft:
+	case s.IsUserCurator(submitter.WalletAddress) && (product.Status == lifecycle.Published || product.Approved):
+		product.Status = lifecycle.Published
+	default:
+		product.Status = lifecycle.Submitted
+	}
+	product.Approved = lifecycle.Listed(product.Status)
+	product.StatusReason = ""
+	product.SuccessorID = nil
+
+	// Scores start neutral and change only through assessments
+	product.SecurityScore = scoring.Unassessed
+	product.UXScore = scoring.Unassessed
+	product.DecentScore = scoring.Unassessed
+	product.VibesScore = scoring.Unassessed
+
+	if err := normalizeProductTags(product); err != nil {
+		return err
+	}
+	if err := validateProductLinks(product.Links); err != nil {
+		return err
+	}
+	if err := s.validateProductContracts(product.Contracts); err != nil {
+		return err
+	}
+
+	if !confirmDuplicate {
+		candidates, err := s.FindDuplicateProducts(product, submitter)
+		if err != nil {
+			return err
+		}
+		if len(candidates) > 0 {
+			return &DuplicateProductError{Candidates: candidates}
+		}
+	}
+
+	if err := s.repo.CreateProduct(product); err != nil {
+		return err
+	}
+	s.contractIndexChanged()
+	return nil
 }
 
-// UpvoteProduct adds an upvote to a product
+// UpvoteProduct adds an upvote to a listed product, weighted by the voting
+// policy. Products the voter may not see are reported as not found.
 func (s *Service) UpvoteProduct(userID, productID string) error {
-	return s.repo.UpvoteProduct(userID, productID)
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	// Votes for a merged duplicate count for the product it was merged into
+	productID, err = s.repo.ResolveProductID(productID)
+	if err != nil {
+		return err
+	}
+
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.visibleProduct(product, user); err != nil {
+		return err
+	}
+	if !lifecycle.Listed(product.Status) {
+		return errors.New("only listed products can be upvoted")
+	}
+
+	// Weighing may read the chain, so repeat votes are turned away first.
+	// The repository still rejects a duplicate that races past this check.
+	upvoted, err := s.repo.GetUpvotedProductIDs(userID, []string{productID})
+	if err != nil {
+		return err
+	}
+	if upvoted[productID] {
+		return errors.New("already upvoted")
+	}
+
+	contributions, err := s.repo.CountUserContributions(userID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	weight, signals := s.voteWeights.Weigh(ctx, voteweight.Voter{
+		User:          user,
+		Contributions: contributions,
+	})
+
+	return s.repo.UpvoteProduct(&models.Upvote{
+		UserID:        userID,
+		ProductID:     productID,
+		Weight:        weight,
+		WeightSignals: signals,
+	})
+}
+
+// RemoveUpvote retracts a user's upvote from a product
+func (s *Service) RemoveUpvote(userID, productID string) error {
+	productID, err := s.repo.ResolveProductID(productID)
+	if err != nil {
+		return err
+	}
+	return s.repo.RemoveUpvote(userID, productID)
+}
+
+// GetUserUpvotes returns the products a user has upvoted, newest vote first
+func (s *Service) GetUserUpvotes(userID string, page, perPage int) ([]models.Upvote, int, error) {
+	return s.repo.GetUserUpvotes(userID, page, perPage)
+}
+
+// MarkViewerUpvotes sets ViewerHasUpvoted on each product for the given viewer
+func (s *Service) MarkViewerUpvotes(viewerID string, products ...*models.Product) error {
+	if viewerID == "" || len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+
+	upvoted, err := s.repo.GetUpvotedProductIDs(viewerID, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		hasUpvoted := upvoted[product.ID]
+		product.ViewerHasUpvoted = &hasUpvoted
+	}
+
+	return nil
+}
+
+// ReconcileUpvoteCounts recomputes denormalized upvote counters and reports drift.
+// With repair set to false it only reports.
+func (s *Service) ReconcileUpvoteCounts(repair bool) (*models.UpvoteReconciliation, error) {
+	return s.repo.ReconcileUpvoteCounts(repair)
 }
 
 // GetPendingEdits returns all pending edits
@@ -134,7 +465,11 @@ func (s *Service) GetPendingEdits() ([]models.PendingEdit, error) {
 
 // ApproveEdit approves a pending edit
 func (s *Service) ApproveEdit(editID string) error {
-	return s.repo.ApproveEdit(editID)
+	if err := s.repo.ApproveEdit(editID); err != nil {
+		return err
+	}
+	s.contractIndexChanged()
+	return nil
 }
 
 // RejectEdit rejects a pending edit
@@ -152,31 +487,73 @@ func (s *Service) IsUserAdmin(walletAddress string) bool {
 	return strings.ToLower(walletAddress) == strings.ToLower(s.cfg.AdminWallet)
 }
 
+// IsUserCurator checks if a user may curate content based on their wallet address
+func (s *Service) IsUserCurator(walletAddress string) bool {
+	return s.cfg.IsCuratorWallet(walletAddress)
+}
+
 // DeleteAllProducts removes all products from the database (for testing purposes only)
 func (s *Service) DeleteAllProducts() error {
-	return s.repo.DeleteAllProducts()
+	if err := s.repo.DeleteAllProducts(); err != nil {
+		return err
+	}
+	s.contractIndexChanged()
+	return nil
 }
 
 // Revision system service methods
 
-// GetProductHistory returns the revision history for a product
-func (s *Service) GetProductHistory(productID string, page, perPage int) ([]models.RevisionSummary, int, error) {
+// GetProductHistory returns the revision history for a product viewer may see
+func (s *Service) GetProductHistory(productID string, page, perPage int, viewer *models.User) ([]models.RevisionSummary, int, error) {
+	if err := s.checkProductVisible(productID, viewer); err != nil {
+		return nil, 0, err
+	}
 	return s.repo.GetProductRevisions(productID, page, perPage)
 }
 
-// GetProductRevision returns a specific revision of a product
-func (s *Service) GetProductRevision(productID string, revisionNumber int) (*models.ProductRevision, error) {
+// GetProductRevision returns a specific revision of a product viewer may see
+func (s *Service) GetProductRevision(productID string, revisionNumber int, viewer *models.User) (*models.ProductRevision, error) {
+	if err := s.checkProductVisible(productID, viewer); err != nil {
+		return nil, err
+	}
 	return s.repo.GetProductRevision(productID, revisionNumber)
 }
 
-// CompareProductRevisions compares two revisions of a product
-func (s *Service) CompareProductRevisions(productID string, fromRevision, toRevision int) (*models.ProductDiff, error) {
+// CompareProductRevisions compares two revisions of a product viewer may see
+func (s *Service) CompareProductRevisions(productID string, fromRevision, toRevision int, viewer *models.User) (*models.ProductDiff, error) {
+	if err := s.checkProductVisible(productID, viewer); err != nil {
+		return nil, err
+	}
 	return s.repo.CompareProductRevisions(productID, fromRevision, toRevision)
 }
 
 // RevertProduct reverts a product to a specific revision
 func (s *Service) RevertProduct(productID string, revisionNumber int, editorID, reason string) error {
-	return s.repo.RevertProductToRevision(productID, revisionNumber, &editorID, reason)
+	// A merged duplicate's revisions are kept for the record only
+	resolved, err := s.repo.ResolveProductID(productID)
+	if err != nil {
+		return err
+	}
+	if resolved != productID {
+		return errors.New("product has been merged into another product")
+	}
+
+	editor, err := s.repo.GetUserByID(editorID)
+	if err != nil {
+		return err
+	}
+	if onProbation(editor, time.Now()) {
+		return errors.New("accounts on probation cannot revert products")
+	}
+	if err := s.requirePrivilege(editor, reputation.Revert, "reverting products"); err != nil {
+		return err
+	}
+
+	if err := s.repo.RevertProductToRevision(productID, revisionNumber, &editorID, reason); err != nil {
+		return err
+	}
+	s.contractIndexChanged()
+	return nil
 }
 
 // GetRecentEdits returns recent product edits across all products
@@ -248,12 +625,75 @@ func (s *Service) generateJWT(user *models.User) (string, error) {
 	return tokenString, nil
 }
 
-// UpdateProduct handles direct product updates with edit summaries
-func (s *Service) UpdateProduct(product *models.Product, editorID, editSummary string, minorEdit bool) error {
+// UpdateProduct handles direct product updates with edit summaries. Edits by
+// users on probation, or without the reputation to edit directly, are queued
+// for review instead; it reports whether the edit was queued. Products the
+// editor may not see, such as someone else's draft, are reported as not
+// found.
+func (s *Service) UpdateProduct(product *models.Product, editor *models.User, editSummary string, minorEdit bool) (bool, error) {
+	editorID := editor.ID
+
 	// Get the current product to compare changes
 	currentProduct, err := s.repo.GetProductByID(product.ID)
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	// Only the submitter and curators may edit a product that is not public
+	if _, err := s.visibleProduct(currentProduct, editor); err != nil {
+		return false, err
+	}
+
+	// Edits addressed to a merged duplicate apply to the survivor
+	product.ID = currentProduct.ID
+
+	// Lifecycle state only changes through ChangeProductStatus, and scores
+	// only through AssessProductScore
+	product.Approved = currentProduct.Approved
+	product.Status = currentProduct.Status
+	product.StatusReason = currentProduct.StatusReason
+	product.SuccessorID = currentProduct.SuccessorID
+	product.SecurityScore = currentProduct.SecurityScore
+	product.UXScore = currentProduct.UXScore
+	product.DecentScore = currentProduct.DecentScore
+	product.VibesScore = currentProduct.VibesScore
+
+	// Omitted tags are left as they are
+	if product.Tags == nil {
+		product.Tags = currentProduct.Tags
+	} else if err := normalizeProductTags(product); err != nil {
+		return false, err
+	}
+
+	// Likewise for links and contract addresses
+	if product.Links == nil {
+		product.Links = currentProduct.Links
+	} else if err := validateProductLinks(product.Links); err != nil {
+		return false, err
+	}
+	if product.Contracts == nil {
+		product.Contracts = currentProduct.Contracts
+	} else if err := s.validateProductContracts(product.Contracts); err != nil {
+		return false, err
+	}
+
+	// Edits that need review wait in the pending queue
+	needsReview, err := s.editNeedsReview(editorID)
+	if err != nil {
+		return false, err
+	}
+	if needsReview {
+		changeData, err := json.Marshal(product)
+		if err != nil {
+			return false, fmt.Errorf("failed to encode product: %w", err)
+		}
+		return true, s.repo.CreatePendingEdit(&models.PendingEdit{
+			UserID:     editorID,
+			EntityType: "product",
+			EntityID:   product.ID,
+			ChangeType: "update",
+			ChangeData: string(changeData),
+		})
 	}
 
 	// Calculate field changes between current and updated product
@@ -262,7 +702,7 @@ func (s *Service) UpdateProduct(product *models.Product, editorID, editSummary s
 	// Create a revision record for this update first
 	err = s.repo.CreateProductRevision(product.ID, &editorID, &editSummary, changes, product)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Update the product's revision number and last editor
@@ -275,10 +715,11 @@ func (s *Service) UpdateProduct(product *models.Product, editorID, editSummary s
 	// Update the product in the database
 	err = s.repo.UpdateProduct(product)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
+	s.contractIndexChanged()
+	return false, nil
 }
 
 // calculateProductChanges compares two products and returns the field changes
@@ -375,6 +816,36 @@ func calculateProductChanges(oldProduct, newProduct *models.Product) []models.Pr
 		})
 	}
 
+	oldTags, newTags := models.TagList(oldProduct.Tags), models.TagList(newProduct.Tags)
+	if oldTags != newTags {
+		changes = append(changes, models.ProductFieldChange{
+			FieldName:  "tags",
+			OldValue:   &oldTags,
+			NewValue:   &newTags,
+			ChangeType: "modified",
+		})
+	}
+
+	oldLinks, newLinks := listJSON(len(oldProduct.Links), oldProduct.Links), listJSON(len(newProduct.Links), newProduct.Links)
+	if oldLinks != newLinks {
+		changes = append(changes, models.ProductFieldChange{
+			FieldName:  "links",
+			OldValue:   &oldLinks,
+			NewValue:   &newLinks,
+			ChangeType: "modified",
+		})
+	}
+
+	oldContracts, newContracts := listJSON(len(oldProduct.Contracts), oldProduct.Contracts), listJSON(len(newProduct.Contracts), newProduct.Contracts)
+	if oldContracts != newContracts {
+		changes = append(changes, models.ProductFieldChange{
+			FieldName:  "contracts",
+			OldValue:   &oldContracts,
+			NewValue:   &newContracts,
+			ChangeType: "modified",
+		})
+	}
+
 	// Add ID for each change record (in real implementation, the repository would do this)
 	for i := range changes {
 		changes[i].ID = fmt.Sprintf("change_%d", i+1)
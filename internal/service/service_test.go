@@ -0,0 +1,231 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/config"
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/voteweight"
+)
+
+// fakeRepository keeps products and users in memory. It embeds
+// DataRepository so it only needs the methods the tests reach; any other
+// call panics.
+type fakeRepository struct {
+	DataRepository
+	products  map[string]*models.Product
+	users     map[string]*models.User
+	upvotes   []*models.Upvote
+	revisions int
+	updates   int
+	weighed   int // votes weighed, counted by CountUserContributions
+}
+
+func newFakeRepository(users ...*models.User) *fakeRepository {
+	repo := &fakeRepository{
+		products: make(map[string]*models.Product),
+		users:    make(map[string]*models.User),
+	}
+	for _, user := range users {
+		repo.users[user.ID] = user
+	}
+	return repo
+}
+
+func (r *fakeRepository) GetProductByID(id string) (*models.Product, error) {
+	product, ok := r.products[id]
+	if !ok {
+		return nil, errors.New("product not found")
+	}
+	copied := *product
+	return &copied, nil
+}
+
+func (r *fakeRepository) ResolveProductID(id string) (string, error) {
+	return id, nil
+}
+
+func (r *fakeRepository) GetUserByID(id string) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+// GetReputationCounts counts only the weighted upvotes a user received on
+// listed products, as the Postgres repository does
+func (r *fakeRepository) GetReputationCounts(userID string) (*models.ReputationCounts, error) {
+	var received float64
+	for _, upvote := range r.upvotes {
+		product := r.products[upvote.ProductID]
+		if product.SubmitterID == userID && product.Approved && product.MergedInto == nil && upvote.UserID != userID {
+			received += upvote.Weight
+		}
+	}
+	return &models.ReputationCounts{UpvotesReceived: int(math.Round(received))}, nil
+}
+
+func (r *fakeRepository) CountUserContributions(string) (int, error) {
+	r.weighed++
+	return 0, nil
+}
+
+func (r *fakeRepository) GetUpvotedProductIDs(userID string, productIDs []string) (map[string]bool, error) {
+	upvoted := make(map[string]bool)
+	for _, upvote := range r.upvotes {
+		for _, id := range productIDs {
+			if upvote.UserID == userID && upvote.ProductID == id {
+				upvoted[id] = true
+			}
+		}
+	}
+	return upvoted, nil
+}
+
+func (r *fakeRepository) UpvoteProduct(upvote *models.Upvote) error {
+	for _, existing := range r.upvotes {
+		if existing.UserID == upvote.UserID && existing.ProductID == upvote.ProductID {
+			return errors.New("already upvoted")
+		}
+	}
+	r.upvotes = append(r.upvotes, upvote)
+	return nil
+}
+
+func (r *fakeRepository) CreateProductRevision(string, *string, *string, []models.ProductFieldChange, *models.Product) error {
+	r.revisions++
+	return nil
+}
+
+func (r *fakeRepository) UpdateProduct(product *models.Product) error {
+	r.updates++
+	copied := *product
+	r.products[product.ID] = &copied
+	return nil
+}
+
+var (
+	submitter = &models.User{ID: "submitter", WalletAddress: "0x1000000000000000000000000000000000000001"}
+	otherUser = &models.User{ID: "other", WalletAddress: "0x2000000000000000000000000000000000000002"}
+	curator   = &models.User{ID: "curator", WalletAddress: "0x3000000000000000000000000000000000000003"}
+)
+
+// newTestService returns a service over repo in which curator is a curator
+// and everyone may edit directly
+func newTestService(repo DataRepository) *Service {
+	return &Service{
+		repo:             repo,
+		cfg:              &config.Config{CuratorWallets: []string{curator.WalletAddress}},
+		voteWeights:      &voteweight.Policy{},
+		contractsChanged: make(chan struct{}, 1),
+	}
+}
+
+func TestUpdateProductVisibility(t *testing.T) {
+	editors := []struct {
+		name   string
+		editor *models.User
+	}{
+		{"submitter", submitter},
+		{"other user", otherUser},
+		{"curator", curator},
+	}
+
+	for _, status := range lifecycle.States {
+		for _, e := range editors {
+			t.Run(status+"/"+e.name, func(t *testing.T) {
+				repo := newFakeRepository(submitter, otherUser, curator)
+				repo.products["p1"] = &models.Product{ID: "p1", Title: "Old", SubmitterID: submitter.ID, Status: status}
+				svc := newTestService(repo)
+
+				_, err := svc.UpdateProduct(&models.Product{ID: "p1", Title: "New"}, e.editor, "retitle", false)
+
+				wantAllowed := lifecycle.IsPublic(status) || e.editor != otherUser
+				if !wantAllowed {
+					if err == nil || err.Error() != "product not found" {
+						t.Fatalf("UpdateProduct() error = %v, want product not found", err)
+					}
+					if repo.updates != 0 || repo.revisions != 0 {
+						t.Errorf("UpdateProduct() wrote %d updates and %d revisions, want none", repo.updates, repo.revisions)
+					}
+					return
+				}
+
+				if err != nil {
+					t.Fatalf("UpdateProduct() error = %v", err)
+				}
+				if got := repo.products["p1"]; got.Title != "New" || got.Status != status {
+					t.Errorf("UpdateProduct() stored title %q status %q, want %q %q", got.Title, got.Status, "New", status)
+				}
+			})
+		}
+	}
+}
+
+func TestUpvoteProductLifecycle(t *testing.T) {
+	voters := []struct {
+		name  string
+		voter *models.User
+	}{
+		{"submitter", submitter},
+		{"other user", otherUser},
+		{"curator", curator},
+	}
+
+	for _, status := range lifecycle.States {
+		for _, v := range voters {
+			t.Run(status+"/"+v.name, func(t *testing.T) {
+				repo := newFakeRepository(submitter, otherUser, curator)
+				repo.products["p1"] = &models.Product{ID: "p1", SubmitterID: submitter.ID, Status: status}
+				svc := newTestService(repo)
+
+				err := svc.UpvoteProduct(v.voter.ID, "p1")
+
+				var wantErr string
+				switch {
+				case !lifecycle.IsPublic(status) && v.voter == otherUser:
+					wantErr = "product not found"
+				case !lifecycle.Listed(status):
+					wantErr = "only listed products can be upvoted"
+				}
+				if wantErr != "" {
+					if err == nil || err.Error() != wantErr {
+						t.Fatalf("UpvoteProduct() error = %v, want %s", err, wantErr)
+					}
+					if len(repo.upvotes) != 0 {
+						t.Errorf("UpvoteProduct() stored %d votes, want none", len(repo.upvotes))
+					}
+					return
+				}
+
+				if err != nil {
+					t.Fatalf("UpvoteProduct() error = %v", err)
+				}
+				if len(repo.upvotes) != 1 || repo.upvotes[0].Weight != 1 {
+					t.Errorf("UpvoteProduct() stored %+v, want one vote of weight 1", repo.upvotes)
+				}
+			})
+		}
+	}
+}
+
+func TestUpvoteProductTwice(t *testing.T) {
+	repo := newFakeRepository(submitter, otherUser)
+	repo.products["p1"] = &models.Product{ID: "p1", SubmitterID: submitter.ID, Status: lifecycle.Published}
+	svc := newTestService(repo)
+
+	if err := svc.UpvoteProduct(otherUser.ID, "p1"); err != nil {
+		t.Fatalf("first UpvoteProduct() error = %v", err)
+	}
+	err := svc.UpvoteProduct(otherUser.ID, "p1")
+	if err == nil || err.Error() != "already upvoted" {
+		t.Fatalf("second UpvoteProduct() error = %v, want already upvoted", err)
+	}
+	if repo.weighed != 1 {
+		t.Errorf("votes weighed = %d, want 1: the repeat vote should be refused before weighing", repo.weighed)
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/slug"
+)
+
+const (
+	maxProductTags   = 10 // tags a single product may carry
+	maxTagNameLength = 40
+	maxTagSuggestion = 10 // suggestions returned per request
+)
+
+// GetTags returns all tags with their aliases and product counts
+func (s *Service) GetTags() ([]models.Tag, error) {
+	return s.repo.GetTags()
+}
+
+// GetTag returns a tag by ID, slug or alias
+func (s *Service) GetTag(idOrSlug string) (*models.Tag, error) {
+	return s.repo.GetTag(slug.Make(idOrSlug))
+}
+
+// SuggestTags returns existing tags for a tag input box. With a prefix it
+// autocompletes; with a product draft it also proposes tags whose slug or
+// alias appears in the draft's text. Tags already on the draft are skipped.
+func (s *Service) SuggestTags(prefix string, draft *models.Product) ([]models.Tag, error) {
+	skip := make(map[string]bool)
+	if draft != nil {
+		for _, tag := range draft.Tags {
+			skip[tag.ID] = true
+			skip[slug.Make(tag.Slug)] = true
+			skip[slug.Make(tag.Name)] = true
+		}
+	}
+
+	var candidates []models.Tag
+	if prefix = slug.Make(prefix); prefix != "" {
+		matches, err := s.repo.SearchTags(prefix, maxTagSuggestion*2)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, matches...)
+	}
+
+	if draft != nil {
+		matches, err := s.tagsMentionedIn(draft.Title, draft.ShortDesc, draft.LongDesc, draft.MarkdownContent)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, matches...)
+	}
+
+	suggestions := []models.Tag{}
+	seen := make(map[string]bool)
+	for _, tag := range candidates {
+		if seen[tag.ID] || skip[tag.ID] || skip[tag.Slug] {
+			continue
+		}
+		seen[tag.ID] = true
+		suggestions = append(suggestions, tag)
+		if len(suggestions) == maxTagSuggestion {
+			break
+		}
+	}
+
+	return suggestions, nil
+}
+
+// tagsMentionedIn returns tags whose slug or an alias occurs as a whole
+// hyphen-delimited phrase in the slugified text, most used first
+func (s *Service) tagsMentionedIn(texts ...string) ([]models.Tag, error) {
+	text := "-" + slug.Make(strings.Join(texts, " ")) + "-"
+	if text == "--" {
+		return nil, nil
+	}
+
+	tags, err := s.repo.GetTags()
+	if err != nil {
+		return nil, err
+	}
+
+	var mentioned []models.Tag
+	for _, tag := range tags {
+		for _, phrase := range append([]string{tag.Slug}, tag.Aliases...) {
+			if strings.Contains(text, "-"+phrase+"-") {
+				mentioned = append(mentioned, tag)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(mentioned, func(i, j int) bool {
+		return mentioned[i].ProductCount > mentioned[j].ProductCount
+	})
+	return mentioned, nil
+}
+
+// normalizeProductTags checks and normalises the tags on a product by name
+// without touching the database. The repository resolves aliases and creates
+// tags that do not exist yet in the transaction that saves the product, so a
+// rejected submission or edit leaves no tags behind.
+func normalizeProductTags(product *models.Product) error {
+	if len(product.Tags) == 0 {
+		return nil
+	}
+
+	requested := make([]models.Tag, 0, len(product.Tags))
+	seen := make(map[string]bool, len(product.Tags))
+	for _, tag := range product.Tags {
+		tag.Name = strings.TrimSpace(tag.Name)
+		if tag.Name == "" {
+			tag.Name = tag.Slug
+		}
+		tag.Slug = slug.Make(tag.Name)
+		if tag.Slug == "" {
+			return errors.New("tag names must contain letters or digits")
+		}
+		if len(tag.Name) > maxTagNameLength {
+			return fmt.Errorf("tag names must be at most %d characters", maxTagNameLength)
+		}
+		if seen[tag.Slug] {
+			continue
+		}
+		seen[tag.Slug] = true
+
+		tag.ID = ""
+		requested = append(requested, tag)
+	}
+
+	if len(requested) > maxProductTags {
+		return fmt.Errorf("a product may have at most %d tags", maxProductTags)
+	}
+
+	product.Tags = requested
+	return nil
+}
+
+// UpdateTag renames a tag or changes its slug or description
+func (s *Service) UpdateTag(tag *models.Tag, actorID string) error {
+	existing, err := s.repo.GetTag(tag.ID)
+	if err != nil {
+		return err
+	}
+	tag.ID = existing.ID
+
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.Name == "" {
+		return errors.New("tag name is required")
+	}
+	if len(tag.Name) > maxTagNameLength {
+		return fmt.Errorf("tag names must be at most %d characters", maxTagNameLength)
+	}
+
+	tag.Slug = slug.Make(tag.Slug)
+	if tag.Slug == "" {
+		tag.Slug = slug.Make(tag.Name)
+	}
+	if tag.Slug == "" {
+		return errors.New("tag slug must contain letters or digits")
+	}
+
+	return s.repo.UpdateTag(tag, actorID)
+}
+
+// DeleteTag deletes a tag and removes it from every product
+func (s *Service) DeleteTag(idOrSlug, actorID string) error {
+	tag, err := s.repo.GetTag(idOrSlug)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteTag(tag.ID, actorID)
+}
+
+// AddTagAlias makes alias resolve to the given tag, folding in any tag that
+// already uses alias as its slug. It returns the number of products re-tagged.
+func (s *Service) AddTagAlias(idOrSlug, alias, actorID string) (int, error) {
+	tag, err := s.repo.GetTag(idOrSlug)
+	if err != nil {
+		return 0, err
+	}
+
+	alias = slug.Make(alias)
+	if alias == "" {
+		return 0, errors.New("alias must contain letters or digits")
+	}
+
+	return s.repo.AddTagAlias(tag.ID, alias, actorID)
+}
+
+// RemoveTagAlias stops alias from resolving to the given tag
+func (s *Service) RemoveTagAlias(idOrSlug, alias, actorID string) error {
+	tag, err := s.repo.GetTag(idOrSlug)
+	if err != nil {
+		return err
+	}
+	return s.repo.RemoveTagAlias(tag.ID, slug.Make(alias), actorID)
+}
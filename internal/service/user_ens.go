@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+const (
+	// ensRefreshBatch caps the users looked up in one background run, so a
+	// backlog is worked through gradually rather than flooding the RPC endpoint
+	ensRefreshBatch = 50
+
+	// ensLookupTimeout bounds the RPC calls made to resolve one user
+	ensLookupTimeout = 10 * time.Second
+
+	// ensRetryDelay is how long a user whose lookup failed waits before the
+	// background refresher tries them again
+	ensRetryDelay = 30 * time.Minute
+)
+
+// refreshUserENS looks up the user's verified ENS name and avatar and caches
+// them on the user. A failed lookup is recorded so the user waits their turn
+// before being retried.
+func (s *Service) refreshUserENS(user *models.User) error {
+	ctx, cancel := context.WithTimeout(context.Background(), ensLookupTimeout)
+	defer cancel()
+
+	profile, err := s.ens.LookupProfile(ctx, user.WalletAddress)
+	if err != nil {
+		if err := s.repo.SetUserENSAttempt(user.ID); err != nil {
+			log.Printf("Warning: failed to record ENS lookup for user %s: %v", user.ID, err)
+		}
+		return fmt.Errorf("failed to look up ENS name: %w", err)
+	}
+
+	if err := s.repo.SetUserENS(user.ID, profile.Name, profile.Avatar); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user.ENSName = profile.Name
+	user.ENSAvatar = profile.Avatar
+	user.ENSCheckedAt = &now
+	return nil
+}
+
+// RefreshUserENS looks up a user's ENS name and avatar now rather than
+// waiting for the cached ones to go stale, and returns the updated user
+func (s *Service) RefreshUserENS(userID string) (*models.User, error) {
+	if s.ens == nil {
+		return nil, errors.New("ENS lookups are not configured")
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshUserENS(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// RefreshStaleENS looks up the ENS names of users never looked up or looked
+// up longer ago than the cache TTL, a batch at a time, and returns how many
+// were refreshed. Users whose lookup fails are logged and retried once
+// ensRetryDelay has passed, behind everyone else waiting.
+func (s *Service) RefreshStaleENS() (int, error) {
+	if s.ens == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+	users, err := s.repo.GetUsersForENSRefresh(now.Add(-s.cfg.ENSCacheTTL), now.Add(-ensRetryDelay), ensRefreshBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	for i := range users {
+		if err := s.refreshUserENS(&users[i]); err != nil {
+			log.Printf("Warning: ENS refresh failed for user %s: %v", users[i].ID, err)
+			continue
+		}
+		refreshed++
+	}
+
+	return refreshed, nil
+}
+
+// queueENSRefresh asks the refresher to run soon, so new users are shown by
+// name without waiting for the next interval. Requests made while a run is
+// already queued are coalesced into it.
+func (s *Service) queueENSRefresh() {
+	select {
+	case s.ensPending <- struct{}{}:
+	default:
+	}
+}
+
+// StartENSRefresher keeps cached ENS names fresh in the background, running
+// on the configured interval and whenever a user signs up, until the returned
+// stop function is called
+func (s *Service) StartENSRefresher() (stop func()) {
+	if s.ens == nil || s.cfg.ENSRefreshInterval <= 0 {
+		return func() {}
+	}
+
+	ticker := time.NewTicker(s.cfg.ENSRefreshInterval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+			case <-s.ensPending:
+			case <-done:
+				return
+			}
+
+			refreshed, err := s.RefreshStaleENS()
+			if err != nil {
+				log.Printf("ENS refresh failed: %v", err)
+			} else if refreshed > 0 {
+				log.Printf("ENS refresh updated %d users", refreshed)
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
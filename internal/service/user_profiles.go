@@ -0,0 +1,282 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/lifecycle"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+const (
+	MaxDisplayNameLength = 50
+	MaxBioLength         = 1000
+	MaxAvatarURLLength   = 500
+
+	// profilePreviewSize is how many items of each section a profile shows;
+	// the rest are paged through the section endpoints
+	profilePreviewSize = 10
+)
+
+// Profile sections a user may hide
+const (
+	SectionSubmissions = "submissions"
+	SectionEdits       = "edits"
+	SectionUpvotes     = "upvotes"
+)
+
+// addressPattern matches strings that look like a wallet address
+var addressPattern = regexp.MustCompile(`^0[xX][0-9a-fA-F]{40}$`)
+
+// UpdateUserProfile changes a user's display name, avatar, bio or privacy settings
+func (s *Service) UpdateUserProfile(userID string, update models.ProfileUpdate) (*models.User, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.DisplayName != nil {
+		name := strings.TrimSpace(*update.DisplayName)
+		if err := validateDisplayName(name); err != nil {
+			return nil, err
+		}
+		user.DisplayName = name
+	}
+	if update.AvatarURL != nil {
+		avatar := strings.TrimSpace(*update.AvatarURL)
+		if err := validateAvatarURL(avatar); err != nil {
+			return nil, err
+		}
+		user.AvatarURL = avatar
+	}
+	if update.Bio != nil {
+		bio := strings.TrimSpace(*update.Bio)
+		if utf8.RuneCountInString(bio) > MaxBioLength {
+			return nil, fmt.Errorf("bio must be at most %d characters", MaxBioLength)
+		}
+		user.Bio = bio
+	}
+	if privacy := update.Privacy; privacy != nil {
+		if privacy.ShowSubmissions != nil {
+			user.Privacy.ShowSubmissions = *privacy.ShowSubmissions
+		}
+		if privacy.ShowEdits != nil {
+			user.Privacy.ShowEdits = *privacy.ShowEdits
+		}
+		if privacy.ShowUpvotes != nil {
+			user.Privacy.ShowUpvotes = *privacy.ShowUpvotes
+		}
+	}
+
+	if err := s.repo.UpdateUserProfile(user); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetUserByID(user.ID)
+}
+
+// validateDisplayName checks a display name. Names that look like a wallet
+// address are refused so nobody can pass themselves off as another wallet.
+func validateDisplayName(name string) error {
+	if utf8.RuneCountInString(name) > MaxDisplayNameLength {
+		return fmt.Errorf("display name must be at most %d characters", MaxDisplayNameLength)
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return errors.New("display name must not contain control characters")
+		}
+	}
+	if addressPattern.MatchString(name) {
+		return errors.New("display name must not be a wallet address")
+	}
+	return nil
+}
+
+// validateAvatarURL checks an avatar URL; empty removes the avatar
+func validateAvatarURL(avatar string) error {
+	if avatar == "" {
+		return nil
+	}
+	if len(avatar) > MaxAvatarURLLength {
+		return fmt.Errorf("avatar URL must be at most %d characters", MaxAvatarURLLength)
+	}
+	parsed, err := url.Parse(avatar)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return errors.New("avatar URL must be an http(s) URL")
+	}
+	return nil
+}
+
+// profileViewer looks up the user a profile belongs to and reports whether
+// the viewer sees all of it: the user themselves and curators do
+func (s *Service) profileViewer(idOrWallet string, viewer *models.User) (*models.User, bool, error) {
+	user, err := s.repo.FindUser(idOrWallet)
+	if err != nil {
+		return nil, false, err
+	}
+	full := viewer != nil && (viewer.ID == user.ID || s.IsUserCurator(viewer.WalletAddress))
+	return user, full, nil
+}
+
+// sectionVisible reports whether a profile section is shown to a viewer
+func sectionVisible(user *models.User, full bool, section string) bool {
+	if full {
+		return true
+	}
+	switch section {
+	case SectionSubmissions:
+		return user.Privacy.ShowSubmissions
+	case SectionEdits:
+		return user.Privacy.ShowEdits
+	case SectionUpvotes:
+		return user.Privacy.ShowUpvotes
+	}
+	return false
+}
+
+// profileStatuses returns the lifecycle states of products a viewer may see
+// in a profile
+func profileStatuses(full bool) []string {
+	if full {
+		return lifecycle.States
+	}
+	return lifecycle.PublicStates
+}
+
+// publicUser returns the parts of a user anyone may see. Users who have not
+// chosen a display name or avatar are shown with their ENS ones.
+func publicUser(user *models.User) *models.User {
+	public := &models.User{
+		ID:                user.ID,
+		WalletAddress:     user.WalletAddress,
+		TwitterHandle:     user.TwitterHandle,
+		TwitterVerifiedAt: user.TwitterVerifiedAt,
+		DisplayName:       user.DisplayName,
+		AvatarURL:         user.AvatarURL,
+		Bio:               user.Bio,
+		ENSName:           user.ENSName,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
+	}
+	if public.DisplayName == "" {
+		public.DisplayName = user.ENSName
+	}
+	if public.AvatarURL == "" {
+		public.AvatarURL = user.ENSAvatar
+	}
+	return public
+}
+
+// GetPublicProfile returns a user's profile, found by ID or wallet address,
+// as the viewer may see it. viewer is nil for anonymous requests.
+func (s *Service) GetPublicProfile(idOrWallet string, viewer *models.User) (*models.UserProfile, error) {
+	user, full, err := s.profileViewer(idOrWallet, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	rep, err := s.GetUserReputation(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &models.UserProfile{User: user, Reputation: rep}
+	if !full {
+		profile.User = publicUser(user)
+	}
+	profile.User.Reputation = rep.Score
+	profile.User.SubmittedProducts = rep.Counts.SubmittedProducts
+	profile.User.Upvotes = rep.Counts.UpvotesReceived
+
+	if sectionVisible(user, full, SectionSubmissions) {
+		if profile.Submissions, _, err = s.userSubmissions(user, full, 1, profilePreviewSize); err != nil {
+			return nil, err
+		}
+	} else {
+		profile.Hidden = append(profile.Hidden, SectionSubmissions)
+	}
+
+	if sectionVisible(user, full, SectionEdits) {
+		if profile.Edits, _, err = s.repo.GetUserEdits(user.ID, profileStatuses(full), 1, profilePreviewSize); err != nil {
+			return nil, err
+		}
+	} else {
+		profile.Hidden = append(profile.Hidden, SectionEdits)
+	}
+
+	if sectionVisible(user, full, SectionUpvotes) {
+		if profile.Upvoted, _, err = s.userUpvoted(user, full, 1, profilePreviewSize); err != nil {
+			return nil, err
+		}
+	} else {
+		profile.Hidden = append(profile.Hidden, SectionUpvotes)
+	}
+
+	return profile, nil
+}
+
+// userSubmissions returns a page of the products a user submitted
+func (s *Service) userSubmissions(user *models.User, full bool, page, perPage int) ([]*models.Product, int, error) {
+	return s.repo.GetProducts(models.ProductFilter{
+		SubmitterID: user.ID,
+		Status:      profileStatuses(full),
+		SortBy:      "new",
+		Page:        page,
+		PerPage:     perPage,
+	})
+}
+
+// userUpvoted returns a page of the products a user upvoted
+func (s *Service) userUpvoted(user *models.User, full bool, page, perPage int) ([]*models.Product, int, error) {
+	return s.repo.GetProducts(models.ProductFilter{
+		UpvotedBy: user.ID,
+		Status:    profileStatuses(full),
+		SortBy:    "new",
+		Page:      page,
+		PerPage:   perPage,
+	})
+}
+
+// profileSection looks up a profile and checks the viewer may see a section of it
+func (s *Service) profileSection(idOrWallet, section string, viewer *models.User) (*models.User, bool, error) {
+	user, full, err := s.profileViewer(idOrWallet, viewer)
+	if err != nil {
+		return nil, false, err
+	}
+	if !sectionVisible(user, full, section) {
+		return nil, false, fmt.Errorf("this user's %s are private", section)
+	}
+	return user, full, nil
+}
+
+// GetUserSubmissions returns a page of the products a user submitted
+func (s *Service) GetUserSubmissions(idOrWallet string, viewer *models.User, page, perPage int) ([]*models.Product, int, error) {
+	user, full, err := s.profileSection(idOrWallet, SectionSubmissions, viewer)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.userSubmissions(user, full, page, perPage)
+}
+
+// GetUserEdits returns a page of a user's product edits, newest first
+func (s *Service) GetUserEdits(idOrWallet string, viewer *models.User, page, perPage int) ([]models.UserEdit, int, error) {
+	user, full, err := s.profileSection(idOrWallet, SectionEdits, viewer)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repo.GetUserEdits(user.ID, profileStatuses(full), page, perPage)
+}
+
+// GetUserUpvotedProducts returns a page of the products a user upvoted
+func (s *Service) GetUserUpvotedProducts(idOrWallet string, viewer *models.User, page, perPage int) ([]*models.Product, int, error) {
+	user, full, err := s.profileSection(idOrWallet, SectionUpvotes, viewer)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.userUpvoted(user, full, page, perPage)
+}
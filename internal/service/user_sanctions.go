@@ -0,0 +1,221 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// MaxSanctionDays caps the length of suspensions and probation set by hand
+const MaxSanctionDays = 365
+
+// banActive reports whether a user is banned or suspended at the given time
+func banActive(user *models.User, now time.Time) bool {
+	return user.BannedAt != nil && (user.BanExpiresAt == nil || now.Before(*user.BanExpiresAt))
+}
+
+// onProbation reports whether a user is on probation at the given time
+func onProbation(user *models.User, now time.Time) bool {
+	return user.ProbationUntil != nil && now.Before(*user.ProbationUntil)
+}
+
+// accessDenial explains why a banned or suspended user, or the token of a
+// merged account, may not use the API, or returns "" when they may
+func accessDenial(user *models.User, now time.Time) string {
+	if user.MergedInto != nil {
+		return "account merged into another account; sign in again"
+	}
+	if !banActive(user, now) {
+		return ""
+	}
+
+	denial := "account banned"
+	if user.BanExpiresAt != nil {
+		denial = "account suspended until " + user.BanExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if user.BanReason != "" {
+		denial += ": " + user.BanReason
+	}
+	return denial
+}
+
+// probationFrom returns when automatic probation starting at t ends, or nil
+// when automatic probation is disabled
+func (s *Service) probationFrom(t time.Time) *time.Time {
+	if s.cfg.ProbationDays == 0 {
+		return nil
+	}
+	until := t.AddDate(0, 0, s.cfg.ProbationDays)
+	return &until
+}
+
+// CheckUserAccess reports whether an authenticated user may use the API and,
+// if not, why. Tokens name users by ID, or by wallet for older tokens. A
+// token for a user that no longer exists is denied.
+func (s *Service) CheckUserAccess(user *models.User) (bool, string, error) {
+	var full *models.User
+	var err error
+	if user.ID != "" {
+		full, err = s.repo.GetUserByID(user.ID)
+	} else {
+		full, err = s.repo.GetUserByWallet(user.WalletAddress)
+	}
+	if err != nil {
+		if err.Error() == "user not found" {
+			return false, "account not found", nil
+		}
+		return false, "", err
+	}
+
+	if denial := accessDenial(full, time.Now()); denial != "" {
+		return false, denial, nil
+	}
+	return true, "", nil
+}
+
+// sanctionableUser returns a user that actor may sanction. Curators, the
+// admin and the actor themselves are exempt.
+func (s *Service) sanctionableUser(userID string, actor *models.User) (*models.User, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == actor.ID {
+		return nil, errors.New("you cannot sanction yourself")
+	}
+	if s.IsUserCurator(user.WalletAddress) {
+		return nil, errors.New("curators cannot be sanctioned")
+	}
+	return user, nil
+}
+
+// BanUser bans a user for good, or suspends them for the given number of
+// days. A suspended user goes on probation when the suspension ends.
+func (s *Service) BanUser(userID, reason string, days int, actor *models.User) (*models.User, error) {
+	if reason == "" {
+		return nil, errors.New("a reason is required")
+	}
+	if days < 0 || days > MaxSanctionDays {
+		return nil, fmt.Errorf("days must be between 0 and %d", MaxSanctionDays)
+	}
+
+	user, err := s.sanctionableUser(userID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt, probationUntil *time.Time
+	if days > 0 {
+		expiry := time.Now().AddDate(0, 0, days)
+		expiresAt = &expiry
+		probationUntil = s.probationFrom(expiry)
+	}
+
+	if err := s.repo.BanUser(user.ID, reason, expiresAt, probationUntil, actor.ID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetUserByID(user.ID)
+}
+
+// ReinstateUser ends a ban or suspension early. The user goes on probation.
+func (s *Service) ReinstateUser(userID, reason string, actor *models.User) (*models.User, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !banActive(user, now) {
+		return nil, errors.New("user is not banned or suspended")
+	}
+
+	if err := s.repo.LiftUserBan(user.ID, reason, s.probationFrom(now), actor.ID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetUserByID(user.ID)
+}
+
+// SetUserProbation puts a user on probation for the given number of days
+func (s *Service) SetUserProbation(userID, reason string, days int, actor *models.User) (*models.User, error) {
+	if reason == "" {
+		return nil, errors.New("a reason is required")
+	}
+	if days < 1 || days > MaxSanctionDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", MaxSanctionDays)
+	}
+
+	user, err := s.sanctionableUser(userID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	until := time.Now().AddDate(0, 0, days)
+	if err := s.repo.SetUserProbation(user.ID, &until, reason, actor.ID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetUserByID(user.ID)
+}
+
+// EndUserProbation takes a user off probation early
+func (s *Service) EndUserProbation(userID, reason string, actor *models.User) (*models.User, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !onProbation(user, time.Now()) {
+		return nil, errors.New("user is not on probation")
+	}
+
+	if err := s.repo.SetUserProbation(user.ID, nil, reason, actor.ID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetUserByID(user.ID)
+}
+
+// extendProbation puts the author of content taken down through moderation
+// on automatic probation, unless they are already on it for longer
+func (s *Service) extendProbation(userID, reason string, actor *models.User) error {
+	until := s.probationFrom(time.Now())
+	if until == nil {
+		return nil
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if s.IsUserCurator(user.WalletAddress) || banActive(user, time.Now()) {
+		return nil
+	}
+	if user.ProbationUntil != nil && user.ProbationUntil.After(*until) {
+		return nil
+	}
+
+	return s.repo.SetUserProbation(user.ID, until, reason, actor.ID)
+}
+
+// GetSanctionedUsers returns users who are banned, suspended or on probation
+func (s *Service) GetSanctionedUsers() ([]models.User, error) {
+	return s.repo.GetSanctionedUsers()
+}
+
+// GetUserSanctions returns a user with the history of sanctions and other
+// moderation actions taken against them
+func (s *Service) GetUserSanctions(userID string) (*models.User, []models.AuditLogEntry, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	history, err := s.repo.GetEntityAuditLog("user", user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, history, nil
+}
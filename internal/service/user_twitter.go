@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/twitter"
+)
+
+// twitterVerifyTimeout bounds the lookup of a challenge post
+const twitterVerifyTimeout = 15 * time.Second
+
+// errTwitterDisabled is returned when no Twitter verifier is configured
+var errTwitterDisabled = errors.New("Twitter linking is not configured")
+
+// CreateTwitterChallenge starts linking a Twitter/X handle to a user. The
+// user proves they control the account by posting the returned code from it
+// before the challenge expires.
+func (s *Service) CreateTwitterChallenge(userID, handle string) (*models.TwitterChallenge, error) {
+	if s.twitter == nil {
+		return nil, errTwitterDisabled
+	}
+
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+	if !twitter.HandlePattern.MatchString(handle) {
+		return nil, errors.New("handle must be 1 to 15 letters, digits or underscores")
+	}
+
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+
+	challenge := &models.TwitterChallenge{
+		UserID:    userID,
+		Handle:    handle,
+		Code:      "ethapplist-" + hex.EncodeToString(nonce),
+		ExpiresAt: time.Now().Add(s.cfg.TwitterChallengeTTL),
+	}
+	if err := s.repo.SaveTwitterChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	challenge.Text = twitterChallengeText(challenge.Code)
+	return challenge, nil
+}
+
+// twitterChallengeText suggests a post carrying a challenge code
+func twitterChallengeText(code string) string {
+	return "Verifying my EthAppList account: " + code
+}
+
+// VerifyTwitterChallenge checks the post the user made for their pending
+// challenge and, if it proves they control the handle, links it to them
+func (s *Service) VerifyTwitterChallenge(userID, postURL string) (*models.User, error) {
+	if s.twitter == nil {
+		return nil, errTwitterDisabled
+	}
+
+	challenge, err := s.repo.GetTwitterChallenge(userID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(challenge.ExpiresAt) {
+		return nil, errors.New("Twitter link challenge has expired; request a new one")
+	}
+
+	handle, postID, err := twitter.ParsePostURL(postURL)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(handle, challenge.Handle) {
+		return nil, fmt.Errorf("post must be made from @%s", challenge.Handle)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), twitterVerifyTimeout)
+	defer cancel()
+
+	if err := s.twitter.Verify(ctx, challenge.Handle, postID, challenge.Code); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.LinkTwitterHandle(userID, challenge.Handle); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserByID(userID)
+}
+
+// UnlinkTwitterHandle removes the user's Twitter handle and cancels any
+// pending challenge
+func (s *Service) UnlinkTwitterHandle(userID string) (*models.User, error) {
+	if err := s.repo.UnlinkTwitterHandle(userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserByID(userID)
+}
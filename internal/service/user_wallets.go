@@ -0,0 +1,192 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// GetUserWallets returns the wallets that sign in to a user's account, the
+// primary wallet first
+func (s *Service) GetUserWallets(userID string) ([]models.UserWallet, error) {
+	return s.repo.GetUserWallets(userID)
+}
+
+// CreateWalletLinkRequest starts linking another wallet to a user's account.
+// The user signs the returned message from their primary wallet and from the
+// wallet being linked before it expires. With merge set, a wallet that
+// already belongs to another account brings that account along with it.
+func (s *Service) CreateWalletLinkRequest(userID, address string, merge bool) (*models.WalletLinkRequest, error) {
+	address = strings.TrimSpace(address)
+	if !common.IsHexAddress(address) {
+		return nil, errors.New("wallet_address must be an Ethereum address")
+	}
+	address = common.HexToAddress(address).Hex()
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(address, user.WalletAddress) {
+		return nil, errors.New("wallet is already linked to your account")
+	}
+	if s.IsUserCurator(address) {
+		return nil, errors.New("curator and admin wallets cannot be linked to another account")
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate link request: %w", err)
+	}
+
+	request := &models.WalletLinkRequest{
+		UserID:    user.ID,
+		Address:   address,
+		Merge:     merge,
+		ExpiresAt: time.Now().Add(s.cfg.WalletLinkTTL).UTC().Truncate(time.Second),
+	}
+	request.Message = walletLinkMessage(user, request, hex.EncodeToString(nonce))
+
+	if err := s.repo.SaveWalletLinkRequest(request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// walletLinkMessage spells out what signing a link request does, so neither
+// wallet can be tricked into linking or merging blind
+func walletLinkMessage(user *models.User, request *models.WalletLinkRequest, nonce string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Link wallet %s to EthAppList account %s (primary wallet %s).\n", request.Address, user.ID, user.WalletAddress)
+	if request.Merge {
+		fmt.Fprintf(&b, "If %s belongs to another account, merge that account into this one.\n", request.Address)
+	}
+	fmt.Fprintf(&b, "Nonce: %s\n", nonce)
+	fmt.Fprintf(&b, "Expires: %s", request.ExpiresAt.Format(time.RFC3339))
+	return b.String()
+}
+
+// LinkWallet completes the user's pending link request once the message is
+// signed by both their primary wallet and the wallet being linked. If the
+// wallet belongs to another account and the request asked to merge, that
+// account is merged into the user's. It returns the user's wallets.
+func (s *Service) LinkWallet(userID, accountSignature, walletSignature string) ([]models.UserWallet, error) {
+	request, err := s.repo.GetWalletLinkRequest(userID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(request.ExpiresAt) {
+		return nil, errors.New("wallet link request has expired; request a new one")
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if valid, err := s.verifySignature(user.WalletAddress, accountSignature, request.Message); err != nil || !valid {
+		return nil, errors.New("invalid signature from your primary wallet")
+	}
+	if valid, err := s.verifySignature(request.Address, walletSignature, request.Message); err != nil || !valid {
+		return nil, errors.New("invalid signature from the wallet being linked")
+	}
+
+	owner, err := s.repo.GetUserByWallet(request.Address)
+	if err != nil && err.Error() != "user not found" {
+		return nil, err
+	}
+
+	switch {
+	case owner == nil:
+		if err := s.repo.LinkWallet(user.ID, request.Address); err != nil {
+			return nil, err
+		}
+	case owner.ID == user.ID:
+		return nil, errors.New("wallet is already linked to your account")
+	case !request.Merge:
+		return nil, errors.New("wallet belongs to another account; request the link with merge to combine the accounts")
+	default:
+		if err := s.checkMergeable(owner, user); err != nil {
+			return nil, err
+		}
+		reason := "Merged by the account owner when linking " + request.Address
+		if _, err := s.repo.MergeUsers(owner.ID, user.ID, reason, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.GetUserWallets(user.ID)
+}
+
+// UnlinkWallet removes a wallet other than the primary one from the user's
+// account and returns the wallets left
+func (s *Service) UnlinkWallet(userID, address string) ([]models.UserWallet, error) {
+	if !common.IsHexAddress(address) {
+		return nil, errors.New("wallet not found")
+	}
+	if err := s.repo.UnlinkWallet(userID, address); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserWallets(userID)
+}
+
+// MergeUsers folds a duplicate account into another, moving its wallets,
+// contributions and votes, and returns the surviving account with the number
+// of wallets moved. Merged accounts stay behind as redirects and their
+// sessions end.
+func (s *Service) MergeUsers(sourceID, targetID, reason string, actor *models.User) (*models.User, int, error) {
+	if reason == "" {
+		return nil, 0, errors.New("a reason is required")
+	}
+	if targetID == "" {
+		return nil, 0, errors.New("into is required")
+	}
+
+	source, err := s.repo.GetUserByID(sourceID)
+	if err != nil {
+		return nil, 0, err
+	}
+	target, err := s.repo.GetUserByID(targetID)
+	if err != nil {
+		return nil, 0, errors.New("target user not found")
+	}
+	if err := s.checkMergeable(source, target); err != nil {
+		return nil, 0, err
+	}
+
+	moved, err := s.repo.MergeUsers(source.ID, target.ID, reason, actor.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	target, err = s.repo.GetUserByID(target.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return target, moved, nil
+}
+
+// checkMergeable refuses merges that would lose a curator's privileges,
+// shake off a ban or suspension, or go nowhere
+func (s *Service) checkMergeable(source, target *models.User) error {
+	now := time.Now()
+	switch {
+	case source.ID == target.ID:
+		return errors.New("a user cannot be merged into themselves")
+	case source.MergedInto != nil:
+		return errors.New("user has already been merged")
+	case target.MergedInto != nil:
+		return errors.New("target user has been merged into another account")
+	case s.IsUserCurator(source.WalletAddress):
+		return errors.New("curator and admin accounts cannot be merged into another account")
+	case banActive(source, now), banActive(target, now):
+		return errors.New("banned or suspended accounts cannot be merged")
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+	"github.com/wesjorgensen/EthAppList/backend/internal/voteanalysis"
+)
+
+// AnalyzeVotes scans recent upvotes for manipulation patterns and stores any
+// anomalies found. It returns the number of anomalies created or refreshed.
+func (s *Service) AnalyzeVotes() (int, error) {
+	activity, err := s.repo.GetVoteActivitySince(time.Now().Add(-s.cfg.VoteAnalysisLookback))
+	if err != nil {
+		return 0, err
+	}
+
+	anomalies := voteanalysis.Analyze(activity, voteanalysis.Thresholds{
+		BurstWindow:          s.cfg.VoteBurstWindow,
+		BurstSize:            s.cfg.VoteBurstSize,
+		NewAccountAge:        s.cfg.VoteNewAccountAge,
+		NewAccountMinVotes:   s.cfg.VoteNewAccountMinVotes,
+		CoVoteWindow:         s.cfg.VoteCoVoteWindow,
+		CoVoteMinShared:      s.cfg.VoteCoVoteMinShared,
+		CoVoteMinClusterSize: s.cfg.VoteCoVoteMinClusterSize,
+	})
+	if len(anomalies) == 0 {
+		return 0, nil
+	}
+
+	return s.repo.SaveVoteAnomalies(anomalies)
+}
+
+// StartVoteAnalyzer runs AnalyzeVotes on the configured interval until the
+// returned stop function is called. It does nothing if the interval is zero.
+func (s *Service) StartVoteAnalyzer() (stop func()) {
+	if s.cfg.VoteAnalysisInterval <= 0 {
+		return func() {}
+	}
+
+	ticker := time.NewTicker(s.cfg.VoteAnalysisInterval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				saved, err := s.AnalyzeVotes()
+				if err != nil {
+					log.Printf("Vote analysis failed: %v", err)
+				} else if saved > 0 {
+					log.Printf("Vote analysis flagged %d anomalies", saved)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// GetVoteAnomalies returns vote anomalies with the given status, or all of them if status is empty
+func (s *Service) GetVoteAnomalies(status string) ([]models.VoteAnomaly, error) {
+	return s.repo.GetVoteAnomalies(status)
+}
+
+// VoidAnomalyVotes voids every vote attached to the given anomalies
+func (s *Service) VoidAnomalyVotes(anomalyIDs []string, actorID, reason string) (int, error) {
+	if len(anomalyIDs) == 0 {
+		return 0, errors.New("no anomalies given")
+	}
+	if reason == "" {
+		return 0, errors.New("a reason is required")
+	}
+	return s.repo.VoidAnomalyVotes(anomalyIDs, actorID, reason)
+}
+
+// DismissVoteAnomaly marks an anomaly as a false positive
+func (s *Service) DismissVoteAnomaly(anomalyID, actorID, reason string) error {
+	return s.repo.DismissVoteAnomaly(anomalyID, actorID, reason)
+}
+
+// GetAuditLog returns recent administrative actions
+func (s *Service) GetAuditLog(entityType string, limit int) ([]models.AuditLogEntry, error) {
+	return s.repo.GetAuditLog(entityType, limit)
+}
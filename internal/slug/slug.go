@@ -0,0 +1,27 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Make converts a display name into a lowercase, hyphen-separated URL slug.
+// Runs of anything other than letters and digits collapse to a single hyphen.
+func Make(name string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range strings.ToLower(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingHyphen = true
+	}
+
+	return b.String()
+}
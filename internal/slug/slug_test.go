@@ -0,0 +1,28 @@
+package slug
+
+import "testing"
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"lowercases", "Uniswap", "uniswap"},
+		{"spaces become hyphens", "Account Abstraction", "account-abstraction"},
+		{"runs collapse", "DeFi  --  Lending!!", "defi-lending"},
+		{"no leading or trailing hyphens", "  (Layer 2)  ", "layer-2"},
+		{"digits kept", "ERC-4337", "erc-4337"},
+		{"non-ASCII dropped", "Café Protocol", "caf-protocol"},
+		{"only punctuation", "!!!", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Make(tt.in); got != tt.want {
+				t.Errorf("Make(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// apiBaseURL is the Twitter/X API the APIVerifier reads posts from
+const apiBaseURL = "https://api.twitter.com/2"
+
+// HandlePattern matches a valid Twitter/X handle, without the leading @
+var HandlePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+
+// postHosts are the hosts post URLs are accepted from
+var postHosts = map[string]bool{
+	"twitter.com":        true,
+	"www.twitter.com":    true,
+	"mobile.twitter.com": true,
+	"x.com":              true,
+	"www.x.com":          true,
+	"mobile.x.com":       true,
+}
+
+// Verifier checks that a post proves control of a Twitter/X account
+type Verifier interface {
+	// Verify returns nil if the post with the given ID was written by handle
+	// and contains challenge
+	Verify(ctx context.Context, handle, postID, challenge string) error
+}
+
+// ParsePostURL returns the handle and post ID from the URL of a post, such
+// as https://x.com/handle/status/123
+func ParsePostURL(rawURL string) (handle, postID string, err error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || !postHosts[strings.ToLower(u.Host)] {
+		return "", "", errors.New("post URL must be a twitter.com or x.com post link")
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 3 || parts[1] != "status" || !HandlePattern.MatchString(parts[0]) || !isNumeric(parts[2]) {
+		return "", "", errors.New("post URL must be a twitter.com or x.com post link")
+	}
+
+	return parts[0], parts[2], nil
+}
+
+// isNumeric reports whether s is a non-empty string of digits
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// APIVerifier verifies posts by reading them from the Twitter/X API
+type APIVerifier struct {
+	bearerToken string
+	client      *http.Client
+}
+
+// NewAPIVerifier creates a verifier that authenticates with an app bearer token
+func NewAPIVerifier(bearerToken string) *APIVerifier {
+	return &APIVerifier{
+		bearerToken: bearerToken,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// postResponse is the part of the API's post lookup response used here
+type postResponse struct {
+	Data *struct {
+		Text     string `json:"text"`
+		AuthorID string `json:"author_id"`
+	} `json:"data"`
+	Includes struct {
+		Users []struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+		} `json:"users"`
+	} `json:"includes"`
+}
+
+// Verify implements Verifier
+func (v *APIVerifier) Verify(ctx context.Context, handle, postID, challenge string) error {
+	endpoint := apiBaseURL + "/tweets/" + url.PathEscape(postID) + "?expansions=author_id&user.fields=username"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch post: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+v.bearerToken)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch post: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch post: API returned %s", resp.Status)
+	}
+
+	var body postResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("failed to fetch post: %w", err)
+	}
+	if body.Data == nil {
+		return errors.New("post not found")
+	}
+
+	var author string
+	for _, user := range body.Includes.Users {
+		if user.ID == body.Data.AuthorID {
+			author = user.Username
+		}
+	}
+	if !strings.EqualFold(author, handle) {
+		return fmt.Errorf("post was not written by @%s", handle)
+	}
+	if !strings.Contains(body.Data.Text, challenge) {
+		return errors.New("post does not contain the challenge")
+	}
+
+	return nil
+}
+
+// StubVerifier accepts every post without looking it up, for development
+// and tests without network access or API credentials
+type StubVerifier struct{}
+
+// Verify implements Verifier
+func (StubVerifier) Verify(context.Context, string, string, string) error {
+	return nil
+}
@@ -0,0 +1,57 @@
+package twitter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParsePostURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		wantHandle string
+		wantPostID string
+		wantErr    bool
+	}{
+		{"x.com post", "https://x.com/vitalik/status/1234567890", "vitalik", "1234567890", false},
+		{"twitter.com post", "https://twitter.com/eth_app/status/42", "eth_app", "42", false},
+		{"mobile host", "https://mobile.twitter.com/eth_app/status/42", "eth_app", "42", false},
+		{"host case ignored", "https://X.com/eth_app/status/42", "eth_app", "42", false},
+		{"trailing path and query", "https://x.com/eth_app/status/42/photo/1?s=20", "eth_app", "42", false},
+		{"surrounding whitespace", "  https://www.x.com/eth_app/status/42  ", "eth_app", "42", false},
+		{"http allowed", "http://x.com/eth_app/status/42", "eth_app", "42", false},
+		{"other host", "https://example.com/eth_app/status/42", "", "", true},
+		{"lookalike host", "https://x.com.example.com/eth_app/status/42", "", "", true},
+		{"other scheme", "ftp://x.com/eth_app/status/42", "", "", true},
+		{"profile link", "https://x.com/eth_app", "", "", true},
+		{"not a status", "https://x.com/eth_app/likes/42", "", "", true},
+		{"non-numeric post ID", "https://x.com/eth_app/status/abc", "", "", true},
+		{"handle too long", "https://x.com/a_very_long_handle_name/status/42", "", "", true},
+		{"handle with invalid characters", "https://x.com/eth-app/status/42", "", "", true},
+		{"empty", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handle, postID, err := ParsePostURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePostURL(%q) error = %v, want error %v", tt.url, err, tt.wantErr)
+			}
+			if handle != tt.wantHandle || postID != tt.wantPostID {
+				t.Errorf("ParsePostURL(%q) = %q, %q, want %q, %q", tt.url, handle, postID, tt.wantHandle, tt.wantPostID)
+			}
+		})
+	}
+}
+
+func TestStubVerifier(t *testing.T) {
+	var verifier Verifier = StubVerifier{}
+
+	handle, postID, err := ParsePostURL("https://x.com/eth_app/status/42")
+	if err != nil {
+		t.Fatalf("ParsePostURL() error = %v", err)
+	}
+	if err := verifier.Verify(context.Background(), handle, postID, "ethapplist-challenge"); err != nil {
+		t.Errorf("Verify() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,232 @@
+package voteanalysis
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// Thresholds tune when a voting pattern is considered suspicious
+type Thresholds struct {
+	BurstWindow          time.Duration // window in which BurstSize votes on one product count as a burst
+	BurstSize            int
+	NewAccountAge        time.Duration // a vote cast within this long of account creation is from a new account
+	NewAccountMinVotes   int           // new-account votes on one product before it is flagged
+	CoVoteWindow         time.Duration // two votes on the same product this close together are co-votes
+	CoVoteMinShared      int           // co-voted products before a pair of users is linked
+	CoVoteMinClusterSize int           // users in a linked cluster before it is flagged
+}
+
+// Analyze inspects recent voting activity and returns the anomalies it finds.
+// Returned anomalies have no ID or status; the repository assigns those.
+func Analyze(votes []models.VoteActivity, t Thresholds) []models.VoteAnomaly {
+	byProduct := make(map[string][]models.VoteActivity)
+	for _, vote := range votes {
+		byProduct[vote.ProductID] = append(byProduct[vote.ProductID], vote)
+	}
+
+	productIDs := make([]string, 0, len(byProduct))
+	for productID, productVotes := range byProduct {
+		sort.Slice(productVotes, func(i, j int) bool {
+			return productVotes[i].VotedAt.Before(productVotes[j].VotedAt)
+		})
+		productIDs = append(productIDs, productID)
+	}
+	sort.Strings(productIDs)
+
+	var anomalies []models.VoteAnomaly
+	for _, productID := range productIDs {
+		if anomaly, ok := detectBurst(byProduct[productID], t); ok {
+			anomalies = append(anomalies, anomaly)
+		}
+		if anomaly, ok := detectNewAccounts(byProduct[productID], t); ok {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+
+	return append(anomalies, detectCoVoting(byProduct, productIDs, t)...)
+}
+
+// detectBurst finds the densest BurstWindow of votes on a product
+func detectBurst(votes []models.VoteActivity, t Thresholds) (models.VoteAnomaly, bool) {
+	if t.BurstSize <= 0 || len(votes) < t.BurstSize {
+		return models.VoteAnomaly{}, false
+	}
+
+	bestStart, bestEnd := 0, 0
+	end := 0
+	for start := range votes {
+		for end < len(votes) && votes[end].VotedAt.Sub(votes[start].VotedAt) <= t.BurstWindow {
+			end++
+		}
+		if end-start > bestEnd-bestStart {
+			bestStart, bestEnd = start, end
+		}
+	}
+
+	burst := votes[bestStart:bestEnd]
+	if len(burst) < t.BurstSize {
+		return models.VoteAnomaly{}, false
+	}
+
+	first := burst[0]
+	details, _ := json.Marshal(map[string]interface{}{
+		"votes":                    len(burst),
+		"window_minutes":           t.BurstWindow.Minutes(),
+		"started_at":               first.VotedAt,
+		"minutes_after_submission": first.VotedAt.Sub(first.ProductCreatedAt).Minutes(),
+	})
+
+	return newProductAnomaly("burst", first.ProductID, burst, details), true
+}
+
+// detectNewAccounts flags products receiving several votes from accounts
+// created moments before voting. Account age is measured from sign-up, not
+// from the wallet's first on-chain activity.
+func detectNewAccounts(votes []models.VoteActivity, t Thresholds) (models.VoteAnomaly, bool) {
+	var fresh []models.VoteActivity
+	for _, vote := range votes {
+		if vote.VotedAt.Sub(vote.UserCreatedAt) <= t.NewAccountAge {
+			fresh = append(fresh, vote)
+		}
+	}
+
+	if t.NewAccountMinVotes <= 0 || len(fresh) < t.NewAccountMinVotes {
+		return models.VoteAnomaly{}, false
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"votes":               len(fresh),
+		"max_account_minutes": t.NewAccountAge.Minutes(),
+	})
+
+	return newProductAnomaly("new_account", fresh[0].ProductID, fresh, details), true
+}
+
+// detectCoVoting links users who repeatedly vote on the same products within
+// CoVoteWindow of each other and flags clusters of linked users
+func detectCoVoting(byProduct map[string][]models.VoteActivity, productIDs []string, t Thresholds) []models.VoteAnomaly {
+	if t.CoVoteMinShared <= 0 {
+		return nil
+	}
+
+	type pair struct{ a, b string }
+	shared := make(map[pair]map[string]bool)
+	pairVotes := make(map[pair][]string)
+
+	for _, productID := range productIDs {
+		votes := byProduct[productID]
+		for i := range votes {
+			for j := i + 1; j < len(votes) && votes[j].VotedAt.Sub(votes[i].VotedAt) <= t.CoVoteWindow; j++ {
+				p := pair{votes[i].UserID, votes[j].UserID}
+				if p.a > p.b {
+					p.a, p.b = p.b, p.a
+				}
+				if shared[p] == nil {
+					shared[p] = make(map[string]bool)
+				}
+				shared[p][productID] = true
+				pairVotes[p] = append(pairVotes[p], votes[i].UpvoteID, votes[j].UpvoteID)
+			}
+		}
+	}
+
+	// Union linked users into clusters
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(u string) string {
+		if parent[u] == "" || parent[u] == u {
+			parent[u] = u
+			return u
+		}
+		parent[u] = find(parent[u])
+		return parent[u]
+	}
+
+	var linked []pair
+	for p, products := range shared {
+		if len(products) >= t.CoVoteMinShared {
+			linked = append(linked, p)
+			parent[find(p.a)] = find(p.b)
+		}
+	}
+
+	clusterUsers := make(map[string]map[string]bool)
+	clusterVotes := make(map[string]map[string]bool)
+	for _, p := range linked {
+		root := find(p.a)
+		if clusterUsers[root] == nil {
+			clusterUsers[root] = make(map[string]bool)
+			clusterVotes[root] = make(map[string]bool)
+		}
+		clusterUsers[root][p.a] = true
+		clusterUsers[root][p.b] = true
+		for _, upvoteID := range pairVotes[p] {
+			clusterVotes[root][upvoteID] = true
+		}
+	}
+
+	minSize := t.CoVoteMinClusterSize
+	if minSize < 2 {
+		minSize = 2
+	}
+
+	var anomalies []models.VoteAnomaly
+	for root, users := range clusterUsers {
+		if len(users) < minSize {
+			continue
+		}
+
+		userIDs := sortedKeys(users)
+		upvoteIDs := sortedKeys(clusterVotes[root])
+		details, _ := json.Marshal(map[string]interface{}{
+			"users":          len(userIDs),
+			"votes":          len(upvoteIDs),
+			"window_minutes": t.CoVoteWindow.Minutes(),
+			"min_shared":     t.CoVoteMinShared,
+		})
+
+		anomalies = append(anomalies, models.VoteAnomaly{
+			Kind:      "co_voting",
+			Subject:   strings.Join(userIDs, ","),
+			UpvoteIDs: upvoteIDs,
+			UserIDs:   userIDs,
+			Details:   details,
+		})
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool { return anomalies[i].Subject < anomalies[j].Subject })
+	return anomalies
+}
+
+// newProductAnomaly builds an anomaly scoped to a single product
+func newProductAnomaly(kind, productID string, votes []models.VoteActivity, details json.RawMessage) models.VoteAnomaly {
+	upvoteIDs := make(map[string]bool, len(votes))
+	userIDs := make(map[string]bool, len(votes))
+	for _, vote := range votes {
+		upvoteIDs[vote.UpvoteID] = true
+		userIDs[vote.UserID] = true
+	}
+
+	return models.VoteAnomaly{
+		Kind:      kind,
+		Subject:   productID,
+		ProductID: &productID,
+		UpvoteIDs: sortedKeys(upvoteIDs),
+		UserIDs:   sortedKeys(userIDs),
+		Details:   details,
+	}
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
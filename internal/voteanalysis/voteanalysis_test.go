@@ -0,0 +1,143 @@
+package voteanalysis
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+var base = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// vote builds a vote on a product minutes after base by a user who signed up
+// long before
+func vote(id, user, product string, minutes int) models.VoteActivity {
+	return models.VoteActivity{
+		UpvoteID:         id,
+		UserID:           user,
+		ProductID:        product,
+		VotedAt:          base.Add(time.Duration(minutes) * time.Minute),
+		UserCreatedAt:    base.AddDate(-1, 0, 0),
+		ProductCreatedAt: base,
+	}
+}
+
+// newAccountVote is a vote cast the minute its user signed up
+func newAccountVote(id, user, product string, minutes int) models.VoteActivity {
+	v := vote(id, user, product, minutes)
+	v.UserCreatedAt = v.VotedAt.Add(-time.Minute)
+	return v
+}
+
+func TestAnalyze(t *testing.T) {
+	thresholds := Thresholds{
+		BurstWindow:          10 * time.Minute,
+		BurstSize:            3,
+		NewAccountAge:        time.Hour,
+		NewAccountMinVotes:   2,
+		CoVoteWindow:         time.Minute,
+		CoVoteMinShared:      2,
+		CoVoteMinClusterSize: 2,
+	}
+
+	type found struct {
+		kind      string
+		subject   string
+		upvoteIDs []string
+	}
+
+	tests := []struct {
+		name  string
+		votes []models.VoteActivity
+		want  []found
+	}{
+		{
+			name:  "no votes",
+			votes: nil,
+			want:  nil,
+		},
+		{
+			name: "steady votes",
+			votes: []models.VoteActivity{
+				vote("v1", "u1", "p1", 0),
+				vote("v2", "u2", "p1", 30),
+				vote("v3", "u3", "p1", 60),
+			},
+			want: nil,
+		},
+		{
+			name: "burst takes the densest window",
+			votes: []models.VoteActivity{
+				vote("v1", "u1", "p1", 0),
+				vote("v2", "u2", "p1", 40),
+				vote("v3", "u3", "p1", 42),
+				vote("v4", "u4", "p1", 45),
+				vote("v5", "u5", "p1", 90),
+			},
+			want: []found{{"burst", "p1", []string{"v2", "v3", "v4"}}},
+		},
+		{
+			name: "votes from new accounts",
+			votes: []models.VoteActivity{
+				newAccountVote("v1", "u1", "p1", 0),
+				vote("v2", "u2", "p1", 30),
+				newAccountVote("v3", "u3", "p1", 60),
+			},
+			want: []found{{"new_account", "p1", []string{"v1", "v3"}}},
+		},
+		{
+			name: "single new account is not flagged",
+			votes: []models.VoteActivity{
+				newAccountVote("v1", "u1", "p1", 0),
+				vote("v2", "u2", "p1", 30),
+			},
+			want: nil,
+		},
+		{
+			name: "users voting together on several products",
+			votes: []models.VoteActivity{
+				vote("v1", "u1", "p1", 0),
+				vote("v2", "u2", "p1", 0),
+				vote("v3", "u1", "p2", 100),
+				vote("v4", "u2", "p2", 100),
+				vote("v5", "u3", "p2", 200),
+			},
+			want: []found{{"co_voting", "u1,u2", []string{"v1", "v2", "v3", "v4"}}},
+		},
+		{
+			name: "voting together once is not flagged",
+			votes: []models.VoteActivity{
+				vote("v1", "u1", "p1", 0),
+				vote("v2", "u2", "p1", 0),
+				vote("v3", "u1", "p2", 100),
+				vote("v4", "u2", "p2", 200),
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []found
+			for _, anomaly := range Analyze(tt.votes, thresholds) {
+				got = append(got, found{anomaly.Kind, anomaly.Subject, anomaly.UpvoteIDs})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeDisabledDetectors(t *testing.T) {
+	var votes []models.VoteActivity
+	for i := 0; i < 5; i++ {
+		votes = append(votes, newAccountVote(fmt.Sprintf("v%d", i), fmt.Sprintf("u%d", i), "p1", i))
+	}
+
+	if anomalies := Analyze(votes, Thresholds{}); len(anomalies) != 0 {
+		t.Errorf("Analyze() with zero thresholds = %v, want none", anomalies)
+	}
+}
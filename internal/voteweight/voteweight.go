@@ -0,0 +1,245 @@
+package voteweight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/config"
+	"github.com/wesjorgensen/EthAppList/backend/internal/ens"
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// Voter holds what signals know about the user casting a vote
+type Voter struct {
+	User          *models.User
+	Contributions int // approved submissions plus accepted edits
+}
+
+// Signal scores one aspect of a voter's trustworthiness between 0 and 1
+type Signal interface {
+	Name() string
+	Score(ctx context.Context, voter Voter) (float64, error)
+}
+
+// weightedSignal pairs a signal with its relative importance in the policy
+type weightedSignal struct {
+	signal Signal
+	weight float64
+}
+
+// Policy combines signal scores into the weight applied to a vote
+type Policy struct {
+	minWeight float64
+	signals   []weightedSignal
+}
+
+// NewPolicy builds the weighting policy described by the config. Scores read
+// from the chain are cached per user so votes do not wait on the RPC endpoint.
+func NewPolicy(cfg *config.Config) (*Policy, error) {
+	policy := &Policy{minWeight: cfg.VoteWeightMin}
+
+	for name, weight := range cfg.VoteWeightSignals {
+		var signal Signal
+
+		switch name {
+		case "account_age":
+			signal = AccountAge{FullAge: time.Duration(cfg.VoteWeightAccountAgeDays) * 24 * time.Hour}
+		case "contributions":
+			signal = ContributionHistory{Target: cfg.VoteWeightContributionTarget}
+		case "onchain":
+			resolver, err := ens.NewResolver(cfg.EthRPCURL)
+			if err != nil {
+				return nil, err
+			}
+			signal = NewCachedSignal(OnChainActivity{resolver: resolver, Target: cfg.VoteWeightTxCountTarget}, cfg.VoteWeightCacheTTL)
+		case "ens":
+			signal = ENSOwnership{}
+		default:
+			return nil, fmt.Errorf("unknown vote weight signal: %s", name)
+		}
+
+		policy.signals = append(policy.signals, weightedSignal{signal: signal, weight: weight})
+	}
+
+	return policy, nil
+}
+
+// Weigh returns the weight for a vote cast by voter along with each signal's
+// score. Signals that fail are logged and left out of the average so an RPC
+// outage does not penalise every voter. With no usable signals every vote
+// counts fully.
+func (p *Policy) Weigh(ctx context.Context, voter Voter) (float64, map[string]float64) {
+	scores := make(map[string]float64, len(p.signals))
+
+	var total, totalWeight float64
+	for _, ws := range p.signals {
+		score, err := ws.signal.Score(ctx, voter)
+		if err != nil {
+			log.Printf("Warning: vote weight signal %s failed for user %s: %v", ws.signal.Name(), voter.User.ID, err)
+			continue
+		}
+
+		score = clamp(score)
+		scores[ws.signal.Name()] = score
+		total += score * ws.weight
+		totalWeight += ws.weight
+	}
+
+	if totalWeight == 0 {
+		return 1, scores
+	}
+
+	weight := p.minWeight + (1-p.minWeight)*(total/totalWeight)
+	return math.Round(weight*10000) / 10000, scores
+}
+
+// AccountAge scores how long the voter has had an account, reaching 1 at FullAge
+type AccountAge struct {
+	FullAge time.Duration
+}
+
+// Name implements Signal
+func (AccountAge) Name() string { return "account_age" }
+
+// Score implements Signal
+func (s AccountAge) Score(_ context.Context, voter Voter) (float64, error) {
+	if s.FullAge <= 0 {
+		return 1, nil
+	}
+	return float64(time.Since(voter.User.CreatedAt)) / float64(s.FullAge), nil
+}
+
+// ContributionHistory scores approved submissions and edits, reaching 1 at Target
+type ContributionHistory struct {
+	Target int
+}
+
+// Name implements Signal
+func (ContributionHistory) Name() string { return "contributions" }
+
+// Score implements Signal
+func (s ContributionHistory) Score(_ context.Context, voter Voter) (float64, error) {
+	if s.Target <= 0 {
+		return 1, nil
+	}
+	return float64(voter.Contributions) / float64(s.Target), nil
+}
+
+// OnChainActivity scores the number of transactions sent from the voter's
+// wallet, reaching 1 at Target
+type OnChainActivity struct {
+	resolver *ens.Resolver
+	Target   int
+}
+
+// Name implements Signal
+func (OnChainActivity) Name() string { return "onchain" }
+
+// Score implements Signal
+func (s OnChainActivity) Score(ctx context.Context, voter Voter) (float64, error) {
+	if s.Target <= 0 {
+		return 1, nil
+	}
+
+	nonce, err := s.resolver.Client().NonceAt(ctx, common.HexToAddress(voter.User.WalletAddress), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transaction count: %w", err)
+	}
+
+	return float64(nonce) / float64(s.Target), nil
+}
+
+// ENSOwnership scores 1 when the voter's wallet has a verified primary ENS
+// name. It reads the name cached on the user, which only counts when it
+// resolves back to the wallet, so a reverse record alone earns nothing.
+type ENSOwnership struct{}
+
+// Name implements Signal
+func (ENSOwnership) Name() string { return "ens" }
+
+// Score implements Signal
+func (ENSOwnership) Score(_ context.Context, voter Voter) (float64, error) {
+	if voter.User.ENSCheckedAt == nil {
+		return 0, errors.New("ENS name not looked up yet")
+	}
+	if voter.User.ENSName == "" {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// maxCachedScores bounds the users a CachedSignal remembers at once
+const maxCachedScores = 10000
+
+// cachedScore is a signal score and when it stops being used
+type cachedScore struct {
+	score     float64
+	expiresAt time.Time
+}
+
+// CachedSignal remembers another signal's score for each user for a while,
+// for signals too slow to compute on every vote. Failures are not cached.
+type CachedSignal struct {
+	signal Signal
+	ttl    time.Duration
+
+	mu     sync.Mutex
+	scores map[string]cachedScore
+}
+
+// NewCachedSignal caches the scores of signal for ttl
+func NewCachedSignal(signal Signal, ttl time.Duration) *CachedSignal {
+	return &CachedSignal{
+		signal: signal,
+		ttl:    ttl,
+		scores: make(map[string]cachedScore),
+	}
+}
+
+// Name implements Signal
+func (c *CachedSignal) Name() string { return c.signal.Name() }
+
+// Score implements Signal
+func (c *CachedSignal) Score(ctx context.Context, voter Voter) (float64, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	cached, ok := c.scores[voter.User.ID]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.score, nil
+	}
+
+	score, err := c.signal.Score(ctx, voter)
+	if err != nil || c.ttl <= 0 {
+		return score, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.scores) >= maxCachedScores {
+		for userID, entry := range c.scores {
+			if !now.Before(entry.expiresAt) {
+				delete(c.scores, userID)
+			}
+		}
+		if len(c.scores) >= maxCachedScores {
+			c.scores = make(map[string]cachedScore)
+		}
+	}
+	c.scores[voter.User.ID] = cachedScore{score: score, expiresAt: now.Add(c.ttl)}
+
+	return score, nil
+}
+
+// clamp limits a score to the range [0, 1]
+func clamp(score float64) float64 {
+	return math.Max(0, math.Min(1, score))
+}
@@ -0,0 +1,207 @@
+package voteweight
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wesjorgensen/EthAppList/backend/internal/models"
+)
+
+// fixedSignal scores every voter the same, or fails
+type fixedSignal struct {
+	name  string
+	score float64
+	err   error
+	calls int
+}
+
+func (s *fixedSignal) Name() string { return s.name }
+
+func (s *fixedSignal) Score(context.Context, Voter) (float64, error) {
+	s.calls++
+	return s.score, s.err
+}
+
+func TestClamp(t *testing.T) {
+	tests := []struct {
+		in, want float64
+	}{
+		{-0.5, 0},
+		{0, 0},
+		{0.25, 0.25},
+		{1, 1},
+		{3, 1},
+	}
+
+	for _, tt := range tests {
+		if got := clamp(tt.in); got != tt.want {
+			t.Errorf("clamp(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWeigh(t *testing.T) {
+	failure := errors.New("rpc unavailable")
+
+	tests := []struct {
+		name       string
+		minWeight  float64
+		signals    []weightedSignal
+		wantWeight float64
+		wantScores map[string]float64
+	}{
+		{
+			name:       "no signals counts fully",
+			minWeight:  0.2,
+			wantWeight: 1,
+			wantScores: map[string]float64{},
+		},
+		{
+			name:      "zero score gets the minimum",
+			minWeight: 0.2,
+			signals: []weightedSignal{
+				{&fixedSignal{name: "a", score: 0}, 1},
+			},
+			wantWeight: 0.2,
+			wantScores: map[string]float64{"a": 0},
+		},
+		{
+			name:      "scores averaged by weight",
+			minWeight: 0,
+			signals: []weightedSignal{
+				{&fixedSignal{name: "a", score: 1}, 3},
+				{&fixedSignal{name: "b", score: 0}, 1},
+			},
+			wantWeight: 0.75,
+			wantScores: map[string]float64{"a": 1, "b": 0},
+		},
+		{
+			name:      "scores clamped before averaging",
+			minWeight: 0.5,
+			signals: []weightedSignal{
+				{&fixedSignal{name: "a", score: 4}, 1},
+				{&fixedSignal{name: "b", score: -1}, 1},
+			},
+			wantWeight: 0.75,
+			wantScores: map[string]float64{"a": 1, "b": 0},
+		},
+		{
+			name:      "failed signals left out",
+			minWeight: 0,
+			signals: []weightedSignal{
+				{&fixedSignal{name: "a", score: 0.5}, 1},
+				{&fixedSignal{name: "b", err: failure}, 1},
+			},
+			wantWeight: 0.5,
+			wantScores: map[string]float64{"a": 0.5},
+		},
+		{
+			name:      "all signals failed counts fully",
+			minWeight: 0.2,
+			signals: []weightedSignal{
+				{&fixedSignal{name: "a", err: failure}, 1},
+			},
+			wantWeight: 1,
+			wantScores: map[string]float64{},
+		},
+		{
+			name:      "weight rounded to four places",
+			minWeight: 0,
+			signals: []weightedSignal{
+				{&fixedSignal{name: "a", score: 1}, 1},
+				{&fixedSignal{name: "b", score: 0}, 2},
+			},
+			wantWeight: 0.3333,
+			wantScores: map[string]float64{"a": 1, "b": 0},
+		},
+	}
+
+	voter := Voter{User: &models.User{ID: "u1"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &Policy{minWeight: tt.minWeight, signals: tt.signals}
+			weight, scores := policy.Weigh(context.Background(), voter)
+			if math.Abs(weight-tt.wantWeight) > 1e-9 {
+				t.Errorf("weight = %v, want %v", weight, tt.wantWeight)
+			}
+			if !reflect.DeepEqual(scores, tt.wantScores) {
+				t.Errorf("scores = %v, want %v", scores, tt.wantScores)
+			}
+		})
+	}
+}
+
+func TestENSOwnership(t *testing.T) {
+	checked := time.Now()
+
+	tests := []struct {
+		name    string
+		user    models.User
+		want    float64
+		wantErr bool
+	}{
+		{"not looked up yet", models.User{ENSName: "alice.eth"}, 0, true},
+		{"no verified name", models.User{ENSCheckedAt: &checked}, 0, false},
+		{"verified name", models.User{ENSName: "alice.eth", ENSCheckedAt: &checked}, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := tt.user
+			got, err := ENSOwnership{}.Score(context.Background(), Voter{User: &user})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Score() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedSignal(t *testing.T) {
+	alice := Voter{User: &models.User{ID: "alice"}}
+	bob := Voter{User: &models.User{ID: "bob"}}
+
+	inner := &fixedSignal{name: "onchain", score: 0.5}
+	cached := NewCachedSignal(inner, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if score, err := cached.Score(context.Background(), alice); err != nil || score != 0.5 {
+			t.Fatalf("Score() = %v, %v, want 0.5", score, err)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("inner signal called %d times for one user, want 1", inner.calls)
+	}
+
+	cached.Score(context.Background(), bob)
+	if inner.calls != 2 {
+		t.Errorf("inner signal called %d times for two users, want 2", inner.calls)
+	}
+
+	// Failures are retried rather than cached
+	failing := &fixedSignal{name: "onchain", err: errors.New("rpc unavailable")}
+	cached = NewCachedSignal(failing, time.Hour)
+	for i := 0; i < 2; i++ {
+		if _, err := cached.Score(context.Background(), alice); err == nil {
+			t.Fatal("Score() error = nil, want the inner error")
+		}
+	}
+	if failing.calls != 2 {
+		t.Errorf("failing signal called %d times, want 2", failing.calls)
+	}
+
+	// A zero TTL disables caching
+	inner = &fixedSignal{name: "onchain", score: 1}
+	cached = NewCachedSignal(inner, 0)
+	cached.Score(context.Background(), alice)
+	cached.Score(context.Background(), alice)
+	if inner.calls != 2 {
+		t.Errorf("uncached signal called %d times, want 2", inner.calls)
+	}
+}
@@ -0,0 +1,31 @@
+-- Category Hierarchy Migration
+-- Adds URL slugs and parent/child relationships to categories
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS slug TEXT;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id TEXT REFERENCES categories(id) ON DELETE RESTRICT;
+
+-- Backfill slugs from names, falling back to the ID for names without letters
+-- or digits. Names that slugify alike are numbered in creation order.
+DO $$
+DECLARE
+    c RECORD;
+    base TEXT;
+    candidate TEXT;
+    n INTEGER;
+BEGIN
+    FOR c IN SELECT id, name FROM categories WHERE slug IS NULL ORDER BY created_at, id LOOP
+        base := COALESCE(NULLIF(TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(c.name), '[^a-z0-9]+', '-', 'g')), ''), c.id);
+        candidate := base;
+        n := 1;
+        WHILE EXISTS (SELECT 1 FROM categories WHERE slug = candidate) LOOP
+            n := n + 1;
+            candidate := base || '-' || n;
+        END LOOP;
+        UPDATE categories SET slug = candidate WHERE id = c.id;
+    END LOOP;
+END $$;
+
+ALTER TABLE categories ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON categories(slug);
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
@@ -0,0 +1,20 @@
+-- Chain Metadata Migration
+-- Adds network metadata so chains can be managed through the API
+
+ALTER TABLE chains ADD COLUMN IF NOT EXISTS evm_chain_id BIGINT;
+ALTER TABLE chains ADD COLUMN IF NOT EXISTS native_currency TEXT;
+ALTER TABLE chains ADD COLUMN IF NOT EXISTS explorer_url TEXT;
+ALTER TABLE chains ADD COLUMN IF NOT EXISTS is_testnet BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE chains ADD COLUMN IF NOT EXISTS parent_chain_id TEXT REFERENCES chains(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chains_evm_chain_id ON chains(evm_chain_id) WHERE evm_chain_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_chains_parent_chain_id ON chains(parent_chain_id);
+
+-- Backfill metadata for the seeded chains
+UPDATE chains SET evm_chain_id = 1, native_currency = 'ETH', explorer_url = 'https://etherscan.io' WHERE id = '1';
+UPDATE chains SET evm_chain_id = 137, native_currency = 'POL', explorer_url = 'https://polygonscan.com' WHERE id = '2';
+UPDATE chains SET native_currency = 'SOL', explorer_url = 'https://explorer.solana.com' WHERE id = '3';
+UPDATE chains SET evm_chain_id = 56, native_currency = 'BNB', explorer_url = 'https://bscscan.com' WHERE id = '4';
+UPDATE chains SET evm_chain_id = 42161, native_currency = 'ETH', explorer_url = 'https://arbiscan.io', parent_chain_id = '1' WHERE id = '5';
+UPDATE chains SET evm_chain_id = 10, native_currency = 'ETH', explorer_url = 'https://optimistic.etherscan.io', parent_chain_id = '1' WHERE id = '6';
+UPDATE chains SET evm_chain_id = 43114, native_currency = 'AVAX', explorer_url = 'https://snowtrace.io' WHERE id = '7';
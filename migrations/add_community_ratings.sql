@@ -0,0 +1,26 @@
+-- Community Ratings Migration
+-- Users rate products between 0 and 1 on each score dimension. Aggregates
+-- are cached per product and dimension and rebuilt whenever ratings change.
+
+CREATE TABLE IF NOT EXISTS product_ratings (
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    dimension TEXT NOT NULL CHECK (dimension IN ('security', 'ux', 'decent', 'vibes')),
+    value DOUBLE PRECISION NOT NULL CHECK (value >= 0 AND value <= 1),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, user_id, dimension)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_ratings_user_id ON product_ratings(user_id);
+
+CREATE TABLE IF NOT EXISTS product_community_scores (
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    dimension TEXT NOT NULL,
+    rating_count INTEGER NOT NULL,
+    mean DOUBLE PRECISION NOT NULL,
+    trimmed_mean DOUBLE PRECISION NOT NULL,
+    score DOUBLE PRECISION NOT NULL, -- Bayesian average
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, dimension)
+);
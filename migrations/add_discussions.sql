@@ -0,0 +1,47 @@
+-- Discussions Migration
+-- Adds reviews (one per user per product, with an optional 1-5 star rating)
+-- and threaded comments. Both are written in markdown, edited or deleted by
+-- their authors and hidden or removed by curators. Products cache how many
+-- visible reviews and comments they have.
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS review_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS comment_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS product_reviews (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    rating SMALLINT CHECK (rating >= 1 AND rating <= 5),
+    status TEXT NOT NULL DEFAULT 'visible' CHECK (status IN ('visible', 'hidden', 'removed')),
+    moderation_reason TEXT,
+    moderated_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMP WITH TIME ZONE,
+    edited_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (product_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_reviews_product ON product_reviews(product_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_product_reviews_user ON product_reviews(user_id);
+
+CREATE TABLE IF NOT EXISTS product_comments (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    parent_id TEXT REFERENCES product_comments(id) ON DELETE CASCADE,
+    root_id TEXT REFERENCES product_comments(id) ON DELETE CASCADE,
+    depth INTEGER NOT NULL DEFAULT 0,
+    user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'visible' CHECK (status IN ('visible', 'hidden', 'removed', 'deleted')),
+    moderation_reason TEXT,
+    moderated_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMP WITH TIME ZONE,
+    edited_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_comments_threads ON product_comments(product_id, created_at DESC) WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_product_comments_root ON product_comments(root_id);
+CREATE INDEX IF NOT EXISTS idx_product_comments_parent ON product_comments(parent_id);
+CREATE INDEX IF NOT EXISTS idx_product_comments_user ON product_comments(user_id);
@@ -0,0 +1,47 @@
+-- Moderation Cases Migration
+-- Users report products, revisions, reviews, comments and other users with a
+-- reason code. Reports against the same target are grouped into a case that
+-- moderators assign, then resolve with an action or dismiss. Bans resolved
+-- from a case are recorded on the user.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS banned_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS ban_reason TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS banned_by TEXT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS moderation_cases (
+    id TEXT PRIMARY KEY,
+    target_type TEXT NOT NULL CHECK (target_type IN ('product', 'revision', 'review', 'comment', 'user')),
+    target_id TEXT NOT NULL,
+    product_id TEXT REFERENCES products(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'in_review', 'resolved', 'dismissed')),
+    assignee_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    report_count INTEGER NOT NULL DEFAULT 0,
+    resolution TEXT,
+    resolution_note TEXT,
+    resolved_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A resolve claims its case before applying the action, so concurrent
+-- resolves cannot apply it twice
+ALTER TABLE moderation_cases ADD COLUMN IF NOT EXISTS resolving_at TIMESTAMP WITH TIME ZONE;
+
+-- At most one case per target is being worked at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_moderation_cases_active_target
+    ON moderation_cases(target_type, target_id) WHERE status IN ('open', 'in_review');
+CREATE INDEX IF NOT EXISTS idx_moderation_cases_queue ON moderation_cases(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_moderation_cases_assignee ON moderation_cases(assignee_id) WHERE assignee_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS reports (
+    id TEXT PRIMARY KEY,
+    case_id TEXT NOT NULL REFERENCES moderation_cases(id) ON DELETE CASCADE,
+    reporter_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    details TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (case_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reports_reporter ON reports(reporter_id, created_at DESC);
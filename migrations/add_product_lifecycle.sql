@@ -0,0 +1,38 @@
+-- Product Lifecycle Migration
+-- Replaces the approved flag with lifecycle states. approved is kept in sync
+-- (true while published or deprecated) for existing queries.
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS status TEXT;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS status_reason TEXT;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS successor_id TEXT REFERENCES products(id) ON DELETE SET NULL;
+
+UPDATE products SET status = CASE WHEN approved THEN 'published' ELSE 'submitted' END WHERE status IS NULL;
+
+ALTER TABLE products ALTER COLUMN status SET NOT NULL;
+ALTER TABLE products ALTER COLUMN status SET DEFAULT 'submitted';
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_status_check;
+ALTER TABLE products ADD CONSTRAINT products_status_check CHECK (
+    status IN ('draft', 'submitted', 'in_review', 'published', 'deprecated', 'delisted', 'scam_flagged')
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_status ON products(status);
+
+-- History of every lifecycle move, with the reason given
+CREATE TABLE IF NOT EXISTS product_status_changes (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    reason TEXT,
+    actor_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    successor_id TEXT REFERENCES products(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_status_changes_product_id ON product_status_changes(product_id, created_at);
+
+-- Record the backfilled state as each product's starting point
+INSERT INTO product_status_changes (id, product_id, from_status, to_status, reason, created_at)
+SELECT 'migrated-' || p.id, p.id, NULL, p.status, 'migrated from approved flag', p.created_at
+FROM products p
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,25 @@
+-- Product Links Migration
+-- Adds typed external links and per-chain contract addresses to products
+
+CREATE TABLE IF NOT EXISTS product_links (
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('website', 'docs', 'github', 'twitter', 'discord', 'telegram', 'blog', 'whitepaper', 'audit', 'other')),
+    url TEXT NOT NULL,
+    label TEXT,
+    PRIMARY KEY (product_id, position)
+);
+
+-- Contracts hang off product_chains, so a product lists every chain it has contracts on
+CREATE TABLE IF NOT EXISTS product_contracts (
+    product_id TEXT NOT NULL,
+    chain_id TEXT NOT NULL,
+    address TEXT NOT NULL, -- EIP-55 checksummed on EVM chains
+    label TEXT,
+    PRIMARY KEY (product_id, chain_id, address),
+    FOREIGN KEY (product_id, chain_id) REFERENCES product_chains(product_id, chain_id) ON DELETE CASCADE
+);
+
+-- A deployed contract belongs to exactly one product. A delisted product
+-- gives the contract up when another product lists it.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_contracts_chain_address ON product_contracts(chain_id, LOWER(address));
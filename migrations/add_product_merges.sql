@@ -0,0 +1,15 @@
+-- Duplicate Detection and Product Merge Migration
+-- Lets submissions be checked against existing products by website domain,
+-- and records which product a merged duplicate was folded into.
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS merged_into TEXT REFERENCES products(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_merged_into ON products(merged_into) WHERE merged_into IS NOT NULL;
+
+-- Host of a link URL, lowercased and without a leading "www."
+CREATE OR REPLACE FUNCTION product_link_host(url TEXT)
+RETURNS TEXT AS $$
+    SELECT NULLIF(substring(LOWER(url) FROM '^[a-z][a-z0-9+.-]*://(?:www\.)?([^/?#:@]+)'), '')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE INDEX IF NOT EXISTS idx_product_links_website_host ON product_links(product_link_host(url)) WHERE type = 'website';
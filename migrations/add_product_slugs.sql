@@ -0,0 +1,38 @@
+-- Product Slugs Migration
+-- Adds human-readable product slugs and redirects from slugs a product used to have
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS slug TEXT;
+
+-- Backfill slugs from titles; later products sharing a title get -2, -3, ...
+-- Products are numbered one at a time against every slug assigned so far,
+-- so a suffixed slug never collides with a title that already ends in a
+-- number, such as "foo-2".
+DO $$
+DECLARE
+    p RECORD;
+    base TEXT;
+    candidate TEXT;
+    n INTEGER;
+BEGIN
+    FOR p IN SELECT id, title FROM products WHERE slug IS NULL ORDER BY created_at, id LOOP
+        base := COALESCE(NULLIF(TRIM(BOTH '-' FROM LEFT(REGEXP_REPLACE(LOWER(p.title), '[^a-z0-9]+', '-', 'g'), 60)), ''), 'product');
+        candidate := base;
+        n := 1;
+        WHILE EXISTS (SELECT 1 FROM products WHERE slug = candidate) LOOP
+            n := n + 1;
+            candidate := base || '-' || n;
+        END LOOP;
+        UPDATE products SET slug = candidate WHERE id = p.id;
+    END LOOP;
+END $$;
+
+ALTER TABLE products ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_slug ON products(slug);
+
+CREATE TABLE IF NOT EXISTS product_slug_redirects (
+    slug TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_slug_redirects_product_id ON product_slug_redirects(product_id);
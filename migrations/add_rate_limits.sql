@@ -0,0 +1,14 @@
+-- Rate Limits Migration
+-- Token buckets for the Postgres rate limit backend (RATE_LIMIT_BACKEND=postgres),
+-- shared by every replica of the server. Buckets are short-lived and rebuilt
+-- from scratch after a crash, so the table is unlogged.
+
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    full_at TIMESTAMP WITH TIME ZONE NOT NULL -- when the bucket will have refilled completely
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_full_at ON rate_limit_buckets(full_at);
@@ -0,0 +1,19 @@
+-- Reputation Migration
+-- Reputation is derived from a user's contributions. Reverted edits cost
+-- reputation, so revisions now record the revert that undid them. Reverts
+-- made before this migration are recovered from their edit summaries.
+
+ALTER TABLE product_revisions ADD COLUMN IF NOT EXISTS reverted_in INTEGER; -- revision number of the revert
+ALTER TABLE product_revisions ADD COLUMN IF NOT EXISTS reverted_by TEXT REFERENCES users(id) ON DELETE SET NULL;
+
+UPDATE product_revisions pr
+SET reverted_in = rv.revision_number, reverted_by = rv.editor_id
+FROM product_revisions rv
+WHERE rv.product_id = pr.product_id
+    AND rv.edit_summary ~ '^Reverted to revision [0-9]+:'
+    AND pr.revision_number > substring(rv.edit_summary from '^Reverted to revision ([0-9]+):')::INTEGER
+    AND pr.revision_number < rv.revision_number
+    AND pr.reverted_in IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pending_edits_user_status ON pending_edits(user_id, status);
+CREATE INDEX IF NOT EXISTS idx_products_submitter_id ON products(submitter_id);
@@ -0,0 +1,33 @@
+-- Score Assessment Migration
+-- Backs each product score with a rubric assessment: per-criterion ratings
+-- with notes and evidence, who assessed it and when. The score columns on
+-- products become a cache of the aggregates, written only by assessments,
+-- and every assessment is kept as score history apart from product revisions.
+
+CREATE TABLE IF NOT EXISTS product_score_assessments (
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    dimension TEXT NOT NULL CHECK (dimension IN ('security', 'ux', 'decent', 'vibes')),
+    score DECIMAL(3,2) NOT NULL CHECK (score >= 0 AND score <= 1),
+    criteria JSONB NOT NULL, -- [{criterion, value, notes, evidence}]
+    note TEXT,
+    assessor_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    assessed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, dimension)
+);
+
+CREATE TABLE IF NOT EXISTS product_score_changes (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    dimension TEXT NOT NULL,
+    old_score DECIMAL(3,2) NOT NULL,
+    new_score DECIMAL(3,2) NOT NULL,
+    criteria JSONB NOT NULL,
+    note TEXT,
+    assessor_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_score_changes_product ON product_score_changes(product_id, created_at DESC);
+
+-- Existing scores were set through free-form edits and keep their values
+-- until a curator assesses them
@@ -0,0 +1,20 @@
+-- Twitter Links Migration
+-- Users link a Twitter/X handle by posting a server-generated challenge from
+-- the account. Only verified handles are kept on users, and a handle belongs
+-- to one user at a time. Handles stored before verification existed were
+-- never proven and are cleared.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS twitter_verified_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE users SET twitter_handle = NULL WHERE twitter_verified_at IS NULL AND twitter_handle IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_twitter_handle ON users(LOWER(twitter_handle));
+
+-- The challenge a user is linking a handle with, at most one at a time
+CREATE TABLE IF NOT EXISTS twitter_challenges (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    handle TEXT NOT NULL,
+    code TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,37 @@
+-- Denormalized Upvote Counter Migration
+-- Stores each product's upvote total on the products row so listings and the
+-- all-time ranking no longer need COUNT(*) over upvotes on every read.
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS upvote_count INTEGER NOT NULL DEFAULT 0;
+
+-- Backfill counters from existing upvotes
+UPDATE products p
+SET upvote_count = COALESCE((SELECT COUNT(*) FROM upvotes u WHERE u.product_id = p.id), 0);
+
+-- Index for the "top_all" sort
+CREATE INDEX IF NOT EXISTS idx_products_upvote_count ON products(upvote_count DESC, created_at DESC);
+
+-- Counter maintenance should not look like an edit, so keep updated_at
+-- unchanged when nothing but upvote_count moves. Rows are compared with the
+-- counter masked so an edit that also moves it still counts as an edit.
+CREATE OR REPLACE FUNCTION update_products_updated_at_column()
+RETURNS TRIGGER AS $$
+DECLARE
+    old_content products%ROWTYPE := OLD;
+    new_content products%ROWTYPE := NEW;
+BEGIN
+    old_content.upvote_count := 0;
+    new_content.upvote_count := 0;
+    new_content.updated_at := old_content.updated_at;
+
+    IF new_content IS NOT DISTINCT FROM old_content THEN
+        NEW.updated_at = OLD.updated_at;
+    ELSE
+        NEW.updated_at = CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS update_products_updated_at ON products;
+CREATE TRIGGER update_products_updated_at BEFORE UPDATE ON products FOR EACH ROW EXECUTE FUNCTION update_products_updated_at_column();
@@ -0,0 +1,16 @@
+-- User ENS Migration
+-- Caches each user's verified primary ENS name and avatar so users can be
+-- shown by name rather than address. ens_checked_at records the last
+-- successful lookup; NULL means the wallet has never been looked up.
+-- ens_attempted_at records the last attempt, failed or not, so users whose
+-- lookups keep failing wait their turn rather than crowding out everyone else.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS ens_name TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS ens_avatar TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS ens_checked_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS ens_attempted_at TIMESTAMP WITH TIME ZONE;
+UPDATE users SET ens_attempted_at = ens_checked_at WHERE ens_attempted_at IS NULL AND ens_checked_at IS NOT NULL;
+
+-- The background refresher picks the users attempted longest ago
+DROP INDEX IF EXISTS idx_users_ens_checked_at;
+CREATE INDEX IF NOT EXISTS idx_users_ens_attempted_at ON users(ens_attempted_at NULLS FIRST);
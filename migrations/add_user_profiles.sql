@@ -0,0 +1,14 @@
+-- User Profiles Migration
+-- Public profiles show a display name, avatar and bio chosen by the user,
+-- and sections of their contribution history they may hide. Submissions
+-- and edits are shown by default; upvotes are not.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS display_name TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bio TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS show_submissions BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS show_edits BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS show_upvotes BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Profiles are looked up by wallet address in any letter case
+CREATE INDEX IF NOT EXISTS idx_users_wallet_address_lower ON users(LOWER(wallet_address));
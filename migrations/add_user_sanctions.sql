@@ -0,0 +1,11 @@
+-- User Sanctions Migration
+-- Bans may now expire: a ban with an expiry is a suspension. Users on
+-- probation keep access but their product edits are queued as pending
+-- edits until probation ends. Sanction history is kept in the audit log.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS ban_expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS probation_until TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS probation_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_users_sanctioned ON users(id)
+    WHERE banned_at IS NOT NULL OR probation_until IS NOT NULL;
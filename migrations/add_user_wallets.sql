@@ -0,0 +1,38 @@
+-- User Wallets Migration
+-- An account may hold several wallets, any of which signs in to it. The
+-- wallet on users stays the account's primary wallet, which roles and tokens
+-- follow. Wallets are linked by signing a server-issued message from both the
+-- primary wallet and the new one; linking a wallet that belongs to another
+-- account merges that account in when the message says so. Merged accounts
+-- are kept so their IDs redirect to the survivor.
+
+CREATE TABLE IF NOT EXISTS user_wallets (
+    address TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    linked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A wallet belongs to one account whatever its letter case
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_wallets_address_lower ON user_wallets(LOWER(address));
+CREATE INDEX IF NOT EXISTS idx_user_wallets_user_id ON user_wallets(user_id);
+
+-- Every existing account holds its own wallet. Where accounts differ only in
+-- the letter case of their wallet, the oldest keeps it and the others can
+-- only be merged into it by the admin.
+INSERT INTO user_wallets (address, user_id, linked_at)
+SELECT wallet_address, id, created_at FROM users
+ORDER BY created_at, id
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS merged_into TEXT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS merged_at TIMESTAMP WITH TIME ZONE;
+
+-- The wallet a user is linking, at most one at a time
+CREATE TABLE IF NOT EXISTS wallet_link_requests (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    address TEXT NOT NULL,
+    merge BOOLEAN NOT NULL DEFAULT FALSE,
+    message TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,57 @@
+-- Vote Anomaly Detection Migration
+-- Stores suspicious voting patterns flagged by the background analyzer, votes
+-- voided by admins, and an audit trail of administrative actions.
+
+CREATE TABLE IF NOT EXISTS vote_anomalies (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('burst', 'new_account', 'co_voting')),
+    subject TEXT NOT NULL, -- product ID, or sorted user IDs of a co-voting cluster
+    product_id TEXT REFERENCES products(id) ON DELETE CASCADE,
+    upvote_ids TEXT[] NOT NULL DEFAULT '{}',
+    user_ids TEXT[] NOT NULL DEFAULT '{}',
+    details JSONB,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'voided', 'dismissed')),
+    detected_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolved_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    resolution_note TEXT
+);
+
+-- The new-account detector was first recorded as 'new_wallet', though it
+-- measures account age rather than wallet age
+ALTER TABLE vote_anomalies DROP CONSTRAINT IF EXISTS vote_anomalies_kind_check;
+UPDATE vote_anomalies SET kind = 'new_account' WHERE kind = 'new_wallet';
+ALTER TABLE vote_anomalies ADD CONSTRAINT vote_anomalies_kind_check CHECK (kind IN ('burst', 'new_account', 'co_voting'));
+
+-- Only one open anomaly per kind and subject; re-detection refreshes it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vote_anomalies_open_subject ON vote_anomalies(kind, subject) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_vote_anomalies_status ON vote_anomalies(status, detected_at);
+
+-- Votes removed by admins, kept for the record
+CREATE TABLE IF NOT EXISTS voided_upvotes (
+    id TEXT PRIMARY KEY,
+    user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    product_id TEXT REFERENCES products(id) ON DELETE CASCADE,
+    weight DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE,
+    voided_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    voided_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    anomaly_id TEXT REFERENCES vote_anomalies(id) ON DELETE SET NULL,
+    reason TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_voided_upvotes_product_id ON voided_upvotes(product_id);
+
+-- Audit trail of administrative actions
+CREATE TABLE IF NOT EXISTS audit_log (
+    id TEXT PRIMARY KEY,
+    actor_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    details JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id);
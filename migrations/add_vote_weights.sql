@@ -0,0 +1,39 @@
+-- Vote Weighting Migration
+-- Each upvote carries a weight derived from the voter's trust signals, and
+-- products keep the sum of those weights alongside the raw upvote count.
+
+ALTER TABLE upvotes ADD COLUMN IF NOT EXISTS weight DOUBLE PRECISION NOT NULL DEFAULT 1;
+ALTER TABLE upvotes ADD COLUMN IF NOT EXISTS weight_signals JSONB;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS weighted_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+-- Existing votes keep full weight, so the score starts out equal to the count
+UPDATE products p
+SET weighted_score = COALESCE((SELECT SUM(u.weight) FROM upvotes u WHERE u.product_id = p.id), 0);
+
+-- Index for the "top_all" sort, which now ranks by weighted score
+CREATE INDEX IF NOT EXISTS idx_products_weighted_score ON products(weighted_score DESC, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_upvotes_product_created ON upvotes(product_id, created_at);
+
+-- Counter maintenance should not look like an edit, so keep updated_at
+-- unchanged when nothing but upvote_count and weighted_score move
+CREATE OR REPLACE FUNCTION update_products_updated_at_column()
+RETURNS TRIGGER AS $$
+DECLARE
+    old_content products%ROWTYPE := OLD;
+    new_content products%ROWTYPE := NEW;
+BEGIN
+    old_content.upvote_count := 0;
+    new_content.upvote_count := 0;
+    old_content.weighted_score := 0;
+    new_content.weighted_score := 0;
+    new_content.updated_at := old_content.updated_at;
+
+    IF new_content IS NOT DISTINCT FROM old_content THEN
+        NEW.updated_at = OLD.updated_at;
+    ELSE
+        NEW.updated_at = CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;